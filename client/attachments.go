@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func attachmentsPath(eventID uuid.UUID) string {
+	return "/events/" + eventID.String() + "/attachments"
+}
+
+// UploadAttachment attaches content to an event as filename. An empty
+// contentType lets the server sniff it. The content is read into memory
+// to build the multipart body.
+func (c *Client) UploadAttachment(ctx context.Context, eventID uuid.UUID, filename, contentType string, content io.Reader) (*structures.Attachment, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+quoteEscaper.Replace(filename)+`"`)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := c.send(ctx, http.MethodPost, c.endpoint(attachmentsPath(eventID), nil), body.Bytes(), header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, decodeError(resp)
+	}
+	var a structures.Attachment
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return nil, fmt.Errorf("client: decoding upload response: %w", err)
+	}
+	return &a, nil
+}
+
+// quoteEscaper escapes a multipart parameter as mime/multipart does.
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func (c *Client) ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error) {
+	var attachments []structures.Attachment
+	if _, err := c.do(ctx, http.MethodGet, attachmentsPath(eventID), nil, nil, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (c *Client) GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	var a structures.Attachment
+	if _, err := c.do(ctx, http.MethodGet, attachmentsPath(eventID)+"/"+id.String(), nil, nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// DownloadAttachment returns the attachment's content as it arrives; the
+// caller closes it.
+func (c *Client) DownloadAttachment(ctx context.Context, eventID, id uuid.UUID) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, http.MethodGet, c.endpoint(attachmentsPath(eventID)+"/"+id.String()+"/content", nil), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, attachmentsPath(eventID)+"/"+id.String(), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func attendeesPath(eventID uuid.UUID) string {
+	return "/events/" + eventID.String() + "/attendees"
+}
+
+func (c *Client) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error) {
+	var attendees []structures.Attendee
+	if _, err := c.do(ctx, http.MethodGet, attendeesPath(eventID), nil, nil, &attendees); err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+// AddAttendee registers an attendee; once the event is at capacity the
+// attendee comes back with Waitlisted set.
+func (c *Client) AddAttendee(ctx context.Context, eventID uuid.UUID, req structures.AddAttendeeRequest) (*structures.Attendee, error) {
+	var a structures.Attendee
+	if _, err := c.do(ctx, http.MethodPost, attendeesPath(eventID), nil, req, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (c *Client) RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error {
+	_, err := c.do(ctx, http.MethodDelete, attendeesPath(eventID)+"/"+url.PathEscape(email), nil, nil, nil)
+	return err
+}
+
+func (c *Client) UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error) {
+	var a structures.Attendee
+	path := attendeesPath(eventID) + "/" + url.PathEscape(email) + "/rsvp"
+	if _, err := c.do(ctx, http.MethodPut, path, nil, structures.UpdateRSVPRequest{Status: status}, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func remindersPath(eventID uuid.UUID) string {
+	return "/events/" + eventID.String() + "/reminders"
+}
+
+func (c *Client) ListReminders(ctx context.Context, eventID uuid.UUID) ([]structures.Reminder, error) {
+	var reminders []structures.Reminder
+	if _, err := c.do(ctx, http.MethodGet, remindersPath(eventID), nil, nil, &reminders); err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (c *Client) CreateReminder(ctx context.Context, eventID uuid.UUID, req structures.CreateReminderRequest) (*structures.Reminder, error) {
+	var r structures.Reminder
+	if _, err := c.do(ctx, http.MethodPost, remindersPath(eventID), nil, req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (c *Client) DeleteReminder(ctx context.Context, eventID, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, remindersPath(eventID)+"/"+id.String(), nil, nil, nil)
+	return err
+}
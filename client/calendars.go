@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func calendarPath(id uuid.UUID) string {
+	return "/calendars/" + id.String()
+}
+
+// ListCalendars returns the calendars the caller can read, each with the
+// caller's permission.
+func (c *Client) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	var calendars []structures.Calendar
+	if _, err := c.do(ctx, http.MethodGet, "/calendars", nil, nil, &calendars); err != nil {
+		return nil, err
+	}
+	return calendars, nil
+}
+
+func (c *Client) CreateCalendar(ctx context.Context, req structures.CalendarRequest) (*structures.Calendar, error) {
+	var cal structures.Calendar
+	if _, err := c.do(ctx, http.MethodPost, "/calendars", nil, req, &cal); err != nil {
+		return nil, err
+	}
+	return &cal, nil
+}
+
+func (c *Client) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	var cal structures.Calendar
+	if _, err := c.do(ctx, http.MethodGet, calendarPath(id), nil, nil, &cal); err != nil {
+		return nil, err
+	}
+	return &cal, nil
+}
+
+func (c *Client) UpdateCalendar(ctx context.Context, id uuid.UUID, req structures.CalendarRequest) (*structures.Calendar, error) {
+	var cal structures.Calendar
+	if _, err := c.do(ctx, http.MethodPut, calendarPath(id), nil, req, &cal); err != nil {
+		return nil, err
+	}
+	return &cal, nil
+}
+
+// DeleteCalendar fails with a 409 while the calendar has events outside
+// the trash.
+func (c *Client) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, calendarPath(id), nil, nil, nil)
+	return err
+}
+
+// ListCalendarEvents returns one page of the calendar's events; opts
+// filters them as in ListEvents.
+func (c *Client) ListCalendarEvents(ctx context.Context, id uuid.UUID, opts ListEventsOptions) (*structures.EventPage, error) {
+	var page structures.EventPage
+	resp, err := c.do(ctx, http.MethodGet, calendarPath(id)+"/events", opts.values(), nil, &page.Events)
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = nextCursor(resp.Header)
+	return &page, nil
+}
+
+func (c *Client) CreateCalendarEvent(ctx context.Context, id uuid.UUID, req structures.CreateEventRequest) (*structures.Event, error) {
+	var e structures.Event
+	if _, err := c.do(ctx, http.MethodPost, calendarPath(id)+"/events", nil, req, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (c *Client) ListCalendarShares(ctx context.Context, id uuid.UUID) ([]structures.CalendarShare, error) {
+	var shares []structures.CalendarShare
+	if _, err := c.do(ctx, http.MethodGet, calendarPath(id)+"/shares", nil, nil, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// ShareCalendar grants actor, or everyone with structures.Everyone, a
+// permission on the calendar, replacing any it had.
+func (c *Client) ShareCalendar(ctx context.Context, id uuid.UUID, actor, permission string) (*structures.CalendarShare, error) {
+	var share structures.CalendarShare
+	req := structures.ShareCalendarRequest{Permission: permission}
+	if _, err := c.do(ctx, http.MethodPut, calendarPath(id)+"/shares/"+url.PathEscape(actor), nil, req, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (c *Client) UnshareCalendar(ctx context.Context, id uuid.UUID, actor string) error {
+	_, err := c.do(ctx, http.MethodDelete, calendarPath(id)+"/shares/"+url.PathEscape(actor), nil, nil, nil)
+	return err
+}
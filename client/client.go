@@ -0,0 +1,290 @@
+// Package client is a typed Go client for the Events API described in
+// docs/openapi.yaml. Every operation in the spec has a method of the same
+// name on Client; request and response bodies use the structures types the
+// server itself encodes.
+//
+// Idempotent requests (GET, PUT and DELETE) are retried on network errors
+// and on 429, 502, 503 and 504 responses, with exponential backoff and
+// jitter, honouring Retry-After. Every other failure is returned as an
+// *Error.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries      = 3
+	defaultRetryBackoff = 200 * time.Millisecond
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// Client calls the Events API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	userAgent  string
+	retries    int
+	backoff    time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for every request. The default
+// is http.DefaultClient; callers bound requests with their contexts.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken sends token as a bearer token, for servers started with
+// API_TOKENS.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithRetries sets how many times an idempotent request is retried and the
+// backoff before the first retry, which doubles on each attempt. Zero
+// retries disables retrying.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client for the API at baseURL, for example
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: base URL must be http or https, got %q", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		userAgent:  "events-client-go",
+		retries:    defaultRetries,
+		backoff:    defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// endpoint joins the base URL with path, whose segments must already be
+// escaped, and query.
+func (c *Client) endpoint(path string, query url.Values) string {
+	u := *c.baseURL
+	u.RawPath = c.baseURL.EscapedPath() + path
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// do sends one API call, retrying it when that is safe. A non-nil in is
+// sent as JSON; a 2xx body is decoded into out when out is non-nil. The
+// response is returned so callers can read headers.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) (*http.Response, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.send(ctx, method, c.endpoint(path, query), body, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return resp, decodeError(resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, fmt.Errorf("client: decoding %s %s response: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
+
+// send performs the request, retrying idempotent methods. The caller owns
+// the returned body, whatever its status.
+func (c *Client) send(ctx context.Context, method, target string, body []byte, header http.Header) (*http.Response, error) {
+	retries := 0
+	if idempotent(method) {
+		retries = c.retries
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json, application/problem+json")
+		c.authorize(req.Header)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if attempt >= retries || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+		wait := c.retryDelay(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) authorize(h http.Header) {
+	if c.token != "" {
+		h.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.userAgent != "" {
+		h.Set("User-Agent", c.userAgent)
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay is the server's Retry-After when it sent one, and otherwise
+// backoff·2^attempt with full jitter, capped at maxRetryBackoff.
+func (c *Client) retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+				return min(time.Duration(s)*time.Second, maxRetryBackoff)
+			}
+			if t, err := http.ParseTime(v); err == nil {
+				return min(max(time.Until(t), 0), maxRetryBackoff)
+			}
+		}
+	}
+	d := min(c.backoff<<attempt, maxRetryBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d) + 1
+}
+
+// Error is a non-2xx response. Problem documents (RFC 9457) fill every
+// field; for the plain-text and JSON bodies the API sends today, Detail is
+// the message and Title the status text.
+type Error struct {
+	StatusCode int    `json:"status"`
+	Type       string `json:"type,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	Instance   string `json:"instance,omitempty"`
+
+	// Body is the raw response body, for example a BookingConflict
+	// document on 409.
+	Body []byte `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("events API: %d %s: %s", e.StatusCode, e.Title, e.Detail)
+	}
+	return fmt.Sprintf("events API: %d %s", e.StatusCode, e.Title)
+}
+
+const maxErrorBody = 1 << 20
+
+func decodeError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	e := &Error{StatusCode: resp.StatusCode, Body: body}
+
+	mediaType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "application/problem+json":
+		_ = json.Unmarshal(body, e)
+		e.StatusCode = resp.StatusCode
+	case "application/json":
+		var doc struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &doc) == nil {
+			e.Detail = doc.Error
+		}
+	default:
+		e.Detail = strings.TrimSpace(string(body))
+	}
+	if e.Title == "" {
+		e.Title = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
+
+// StatusCode returns the HTTP status of an *Error anywhere in err's chain,
+// or 0.
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a 409 from the API.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
@@ -0,0 +1,1070 @@
+package client_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"events/client"
+	"events/clients"
+	"events/controller"
+	"events/docs"
+	"events/services"
+	"events/structures"
+	"events/utils"
+
+	"github.com/google/uuid"
+)
+
+const testToken = "contract-token"
+
+// otherToken is a second caller, for the calendar permission checks.
+const otherToken = "other-contract-token"
+
+// newTestAPI serves the real controllers and services, wired like main
+// but over memStore, and returns a client for it and the server's URL.
+func newTestAPI(t *testing.T) (*client.Client, string) {
+	t.Helper()
+	store := newMemStore()
+	calendarSvc := services.NewCalendarService(store)
+	access := services.NewEventAccess(store, calendarSvc)
+	eventSvc := services.NewEventService(store, access)
+	attendeeSvc := services.NewAttendeeService(store, access)
+	resourceSvc := services.NewResourceService(store, access)
+	feed := services.NewChangeFeed(store, 10*time.Millisecond)
+	hub := services.NewHub(feed, 64)
+
+	mux := http.NewServeMux()
+	controller.NewEventController(eventSvc).RegisterRoutes(mux)
+	controller.NewAttendeeController(attendeeSvc).RegisterRoutes(mux)
+	controller.NewResourceController(resourceSvc).RegisterRoutes(mux)
+	controller.NewAvailabilityController(services.NewAvailabilityService(store, resourceSvc)).RegisterRoutes(mux)
+	controller.NewWebhookController(services.NewWebhookService(store)).RegisterRoutes(mux)
+	controller.NewGraphQLController(eventSvc, attendeeSvc, resourceSvc).RegisterRoutes(mux)
+	controller.NewStreamController(feed, access).RegisterRoutes(mux)
+	controller.NewWSController(hub, access).RegisterRoutes(mux)
+	controller.NewReminderController(services.NewReminderService(store, access)).RegisterRoutes(mux)
+	controller.NewCalendarController(calendarSvc, eventSvc).RegisterRoutes(mux)
+	blobs, err := clients.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.NewAttachmentController(services.NewAttachmentService(store, blobs, access)).RegisterRoutes(mux)
+	controller.NewCommentController(services.NewCommentService(store, access)).RegisterRoutes(mux)
+	controller.NewSearchController(eventSvc).RegisterRoutes(mux)
+	controller.NewOpenAPIController(docs.OpenAPI).RegisterRoutes(mux)
+
+	// Everything the client sends and gets back must match the spec.
+	validator, err := utils.NewSpecValidator(docs.OpenAPI, utils.ValidateRequests|utils.ValidateResponses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator.OnMismatch(func(r *http.Request, err error) {
+		t.Errorf("%s %s: response does not match the spec: %v", r.Method, r.URL.Path, err)
+	})
+	srv := httptest.NewServer(utils.NewTokenAuth([]string{testToken, otherToken}).Middleware(validator.Middleware(mux)))
+	t.Cleanup(srv.Close)
+
+	// Cancelled before srv.Close, so open streams end first.
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go feed.Run(ctx)
+	go hub.Run(ctx)
+
+	c, err := client.New(srv.URL, client.WithToken(testToken), client.WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, srv.URL
+}
+
+func eventRequest(title string, start time.Time) structures.CreateEventRequest {
+	return structures.CreateEventRequest{
+		Title:     title,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		TimeZone:  "Europe/Madrid",
+	}
+}
+
+var baseTime = time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+
+func TestClient_CoversEveryOperation(t *testing.T) {
+	spec, err := os.ReadFile("../docs/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods := make(map[string]bool)
+	typ := reflect.TypeOf(&client.Client{})
+	for i := 0; i < typ.NumMethod(); i++ {
+		methods[strings.ToLower(typ.Method(i).Name)] = true
+	}
+
+	ops := regexp.MustCompile(`operationId:\s*(\w+)`).FindAllStringSubmatch(string(spec), -1)
+	if len(ops) == 0 {
+		t.Fatal("no operationId found in the spec")
+	}
+	for _, op := range ops {
+		if !methods[strings.ToLower(op[1])] {
+			t.Errorf("operation %s has no client method", op[1])
+		}
+	}
+}
+
+func TestContract_Events(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+
+	var created []*structures.Event
+	for i, title := range []string{"Standup", "Planning", "Retro"} {
+		e, err := c.CreateEvent(ctx, eventRequest(title, baseTime.Add(time.Duration(i)*24*time.Hour)))
+		if err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+		created = append(created, e)
+	}
+
+	got, err := c.GetEventByID(ctx, created[0].ID, "America/New_York")
+	if err != nil {
+		t.Fatalf("GetEventByID: %v", err)
+	}
+	if got.Title != "Standup" || got.StartTime.Location().String() == "UTC" || !got.StartTime.Equal(baseTime) {
+		t.Errorf("unexpected event %+v", got)
+	}
+
+	all, err := c.ListEvents(ctx, client.ListEventsOptions{})
+	if err != nil || len(all.Events) != 3 || all.NextCursor != "" {
+		t.Fatalf("ListEvents = %+v, %v; want all three events on one page", all, err)
+	}
+	page, err := c.ListEvents(ctx, client.ListEventsOptions{Limit: 2})
+	if err != nil || len(page.Events) != 2 || page.NextCursor == "" {
+		t.Fatalf("ListEvents(limit 2) = %+v, %v; want a first page with a cursor", page, err)
+	}
+
+	var titles []string
+	for e, err := range c.Events(ctx, client.ListEventsOptions{Limit: 1}) {
+		if err != nil {
+			t.Fatalf("Events: %v", err)
+		}
+		titles = append(titles, e.Title)
+	}
+	if strings.Join(titles, ",") != "Standup,Planning,Retro" {
+		t.Errorf("Events yielded %v", titles)
+	}
+
+	beforeUpdate := time.Now()
+	req := eventRequest("Standup (moved)", baseTime.Add(time.Hour))
+	updated, err := c.UpdateEvent(ctx, created[0].ID, req)
+	if err != nil || updated.Title != req.Title {
+		t.Fatalf("UpdateEvent = %+v, %v", updated, err)
+	}
+
+	if err := c.DeleteEvent(ctx, created[0].ID); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if _, err := c.GetEventByID(ctx, created[0].ID, ""); !client.IsNotFound(err) {
+		t.Errorf("GetEventByID after delete: want 404, got %v", err)
+	}
+
+	history, err := c.ListEventHistory(ctx, created[0].ID)
+	if err != nil || len(history) != 3 {
+		t.Fatalf("ListEventHistory = %+v, %v; want create, update and delete", history, err)
+	}
+	if title := history[1].Diff["title"]; history[1].Operation != structures.HistoryUpdate || title.Before != "Standup" || title.After != req.Title {
+		t.Errorf("unexpected update entry %+v", history[1])
+	}
+	if !strings.HasPrefix(history[0].Actor, "token:") {
+		t.Errorf("expected changes to be attributed to the token, got %q", history[0].Actor)
+	}
+	old, err := c.GetEventAsOf(ctx, created[0].ID, beforeUpdate, "")
+	if err != nil || old.Title != "Standup" {
+		t.Errorf("GetEventAsOf(before the update) = %+v, %v", old, err)
+	}
+	if _, err := c.GetEventAsOf(ctx, created[0].ID, time.Now(), ""); !client.IsNotFound(err) {
+		t.Errorf("GetEventAsOf(after the delete): want 404, got %v", err)
+	}
+
+	withTrash, err := c.ListEvents(ctx, client.ListEventsOptions{IncludeDeleted: true})
+	if err != nil || len(withTrash.Events) != 3 || withTrash.Events[0].DeletedAt == nil {
+		t.Fatalf("ListEvents(include deleted) = %+v, %v; want the deleted event first", withTrash, err)
+	}
+	restored, err := c.RestoreEvent(ctx, created[0].ID)
+	if err != nil || restored.DeletedAt != nil || restored.Title != req.Title {
+		t.Fatalf("RestoreEvent = %+v, %v", restored, err)
+	}
+	if _, err := c.RestoreEvent(ctx, created[0].ID); !client.IsConflict(err) {
+		t.Errorf("RestoreEvent of a live event: want 409, got %v", err)
+	}
+	if _, err := c.GetEventByID(ctx, created[0].ID, ""); err != nil {
+		t.Errorf("GetEventByID after restore: %v", err)
+	}
+
+	draft := eventRequest("Offsite", baseTime.Add(72*time.Hour))
+	draft.Status = structures.StatusDraft
+	offsite, err := c.CreateEvent(ctx, draft)
+	if err != nil || offsite.Status != structures.StatusDraft {
+		t.Fatalf("CreateEvent(draft) = %+v, %v", offsite, err)
+	}
+	if _, err := c.CompleteEvent(ctx, offsite.ID, ""); !client.IsConflict(err) {
+		t.Errorf("CompleteEvent of a draft: want 409, got %v", err)
+	}
+	steps := []struct {
+		do   func(context.Context, uuid.UUID, string) (*structures.Event, error)
+		want string
+	}{
+		{c.ScheduleEvent, structures.StatusScheduled},
+		{c.PostponeEvent, structures.StatusPostponed},
+		{c.CancelEvent, structures.StatusCancelled},
+		{c.ScheduleEvent, structures.StatusScheduled},
+		{c.CompleteEvent, structures.StatusCompleted},
+	}
+	for _, step := range steps {
+		e, err := step.do(ctx, offsite.ID, "because "+step.want)
+		if err != nil || e.Status != step.want || e.StatusReason != "because "+step.want {
+			t.Fatalf("transition to %s = %+v, %v", step.want, e, err)
+		}
+	}
+	if _, err := c.ScheduleEvent(ctx, offsite.ID, ""); !client.IsConflict(err) {
+		t.Errorf("ScheduleEvent of a completed event: want 409, got %v", err)
+	}
+	done, err := c.ListEvents(ctx, client.ListEventsOptions{Status: []string{structures.StatusCompleted}})
+	if err != nil || len(done.Events) != 1 || done.Events[0].ID != offsite.ID {
+		t.Errorf("ListEvents(completed) = %+v, %v", done, err)
+	}
+
+	tagged := eventRequest("Planning", baseTime.Add(96*time.Hour))
+	tagged.Tags = []string{"Team:Payments", "project:atlas", "team:payments"}
+	tagged.Metadata = map[string]any{"room": map[string]any{"floor": "3"}, "budget": 1200}
+	planning, err := c.CreateEvent(ctx, tagged)
+	if err != nil || strings.Join(planning.Tags, ",") != "project:atlas,team:payments" || planning.Metadata["budget"] != 1200.0 {
+		t.Fatalf("CreateEvent(tagged) = %+v, %v", planning, err)
+	}
+	found, err := c.ListEvents(ctx, client.ListEventsOptions{
+		Tags:     []string{"team:payments", "project:atlas"},
+		Metadata: map[string]string{"room.floor": "3"},
+	})
+	if err != nil || len(found.Events) != 1 || found.Events[0].ID != planning.ID {
+		t.Errorf("ListEvents(tags, metadata) = %+v, %v", found, err)
+	}
+	none, err := c.ListEvents(ctx, client.ListEventsOptions{Tags: []string{"team:payments", "type:workshop"}})
+	if err != nil || len(none.Events) != 0 {
+		t.Errorf("ListEvents(missing tag) = %+v, %v", none, err)
+	}
+	tagged.Metadata = map[string]any{"a": map[string]any{"b": map[string]any{"c": map[string]any{"d": map[string]any{"e": map[string]any{"f": 1}}}}}}
+	var tooDeep *client.Error
+	if _, err := c.CreateEvent(ctx, tagged); !errors.As(err, &tooDeep) || tooDeep.StatusCode != http.StatusBadRequest {
+		t.Errorf("CreateEvent with metadata nested too deep: want 400, got %v", err)
+	}
+
+	_, err = c.CreateEvent(ctx, structures.CreateEventRequest{Title: "No times"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Detail == "" {
+		t.Errorf("CreateEvent without times: want a 400 with a detail, got %v", err)
+	}
+}
+
+func TestContract_Locations(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+
+	places := []struct {
+		title    string
+		lat, lng float64
+	}{
+		{"Berlin", 52.52, 13.405},
+		{"Potsdam", 52.3906, 13.0645},
+		{"Hamburg", 53.5511, 9.9937},
+	}
+	for i, p := range places {
+		req := eventRequest(p.title, baseTime.Add(time.Duration(i)*time.Hour))
+		req.Location = &structures.Location{Name: p.title + " office", Latitude: &p.lat, Longitude: &p.lng}
+		if _, err := c.CreateEvent(ctx, req); err != nil {
+			t.Fatalf("CreateEvent(%s): %v", p.title, err)
+		}
+	}
+	online := eventRequest("Webinar", baseTime)
+	online.Location = &structures.Location{Name: "Online"}
+	if e, err := c.CreateEvent(ctx, online); err != nil || e.Location == nil || e.Location.Latitude != nil {
+		t.Fatalf("CreateEvent(no coordinates) = %+v, %v", e, err)
+	}
+
+	near, err := c.ListEvents(ctx, client.ListEventsOptions{Near: &structures.GeoPoint{Lat: 52.52, Lng: 13.405}, RadiusKm: 50})
+	if err != nil || len(near.Events) != 2 {
+		t.Fatalf("ListEvents(near Berlin) = %+v, %v; want Berlin and Potsdam", near, err)
+	}
+	potsdam := near.Events[1]
+	if potsdam.Title != "Potsdam" || potsdam.DistanceKm == nil || *potsdam.DistanceKm < 26 || *potsdam.DistanceKm > 28 {
+		t.Errorf("unexpected distance to Potsdam: %+v", potsdam.DistanceKm)
+	}
+
+	half := 52.52
+	bad := eventRequest("Nowhere", baseTime)
+	bad.Location = &structures.Location{Latitude: &half}
+	var apiErr *client.Error
+	if _, err := c.CreateEvent(ctx, bad); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("CreateEvent with lat but no lng: want 400, got %v", err)
+	}
+}
+
+func TestContract_Attachments(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+
+	e, err := c.CreateEvent(ctx, eventRequest("Kickoff", baseTime))
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	agenda := "%PDF-1.7\n1 0 obj\n"
+	a, err := c.UploadAttachment(ctx, e.ID, "agenda.pdf", "application/pdf", strings.NewReader(agenda))
+	if err != nil {
+		t.Fatalf("UploadAttachment: %v", err)
+	}
+	sum := sha256.Sum256([]byte(agenda))
+	if a.Size != int64(len(agenda)) || a.SHA256 != hex.EncodeToString(sum[:]) || a.ContentType != "application/pdf" || a.UploadedBy == "" {
+		t.Fatalf("unexpected attachment %+v", a)
+	}
+	notes, err := c.UploadAttachment(ctx, e.ID, "notes.txt", "", strings.NewReader("bring snacks"))
+	if err != nil || !strings.HasPrefix(notes.ContentType, "text/plain") {
+		t.Fatalf("UploadAttachment(sniffed) = %+v, %v", notes, err)
+	}
+
+	list, err := c.ListAttachments(ctx, e.ID)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListAttachments = %+v, %v", list, err)
+	}
+	if got, err := c.GetAttachment(ctx, e.ID, a.ID); err != nil || got.Filename != "agenda.pdf" {
+		t.Fatalf("GetAttachment = %+v, %v", got, err)
+	}
+	body, err := c.DownloadAttachment(ctx, e.ID, a.ID)
+	if err != nil {
+		t.Fatalf("DownloadAttachment: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != agenda {
+		t.Errorf("DownloadAttachment returned %q", data)
+	}
+
+	var apiErr *client.Error
+	if _, err := c.UploadAttachment(ctx, e.ID, "tool.exe", "", strings.NewReader("MZ\x90\x00\x03")); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("UploadAttachment of an executable: want 415, got %v", err)
+	}
+	// The spec validator leaves uploads to the handler's own size limit.
+	tooLarge := io.MultiReader(strings.NewReader(agenda), io.LimitReader(zeroReader{}, structures.MaxAttachmentSize))
+	if _, err := c.UploadAttachment(ctx, e.ID, "huge.pdf", "application/pdf", tooLarge); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("UploadAttachment over the size limit: want 413, got %v", err)
+	}
+	if _, err := c.UploadAttachment(ctx, uuid.New(), "agenda.pdf", "application/pdf", strings.NewReader(agenda)); !client.IsNotFound(err) {
+		t.Errorf("UploadAttachment to a missing event: want 404, got %v", err)
+	}
+
+	if err := c.DeleteAttachment(ctx, e.ID, a.ID); err != nil {
+		t.Fatalf("DeleteAttachment: %v", err)
+	}
+	if _, err := c.DownloadAttachment(ctx, e.ID, a.ID); !client.IsNotFound(err) {
+		t.Errorf("DownloadAttachment after delete: want 404, got %v", err)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestContract_Comments(t *testing.T) {
+	c, url := newTestAPI(t)
+	ctx := context.Background()
+	other, err := client.New(url, client.WithToken(otherToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := c.CreateEvent(ctx, eventRequest("Offsite", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := c.CreateComment(ctx, e.ID, structures.CreateCommentRequest{Body: "Who brings the **projector**? <script>x</script>"})
+	if err != nil {
+		t.Fatalf("CreateComment: %v", err)
+	}
+	if root.BodyHTML != "<p>Who brings the <strong>projector</strong>? &lt;script&gt;x&lt;/script&gt;</p>\n" {
+		t.Errorf("unexpected body_html %q", root.BodyHTML)
+	}
+	reply, err := other.CreateComment(ctx, e.ID, structures.CreateCommentRequest{Body: "I will", ParentID: &root.ID})
+	if err != nil {
+		t.Fatalf("CreateComment(reply): %v", err)
+	}
+	if reply.Author == root.Author {
+		t.Errorf("reply attributed to the first author")
+	}
+	for _, body := range []string{"second", "third"} {
+		if _, err := c.CreateComment(ctx, e.ID, structures.CreateCommentRequest{Body: body}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := c.ListComments(ctx, e.ID, client.ListCommentsOptions{Limit: 2})
+	if err != nil || len(page.Comments) != 2 || page.NextCursor == "" {
+		t.Fatalf("ListComments = %+v, %v", page, err)
+	}
+	if page.Comments[0].ID != root.ID || page.Comments[0].ReplyCount != 1 {
+		t.Errorf("unexpected first comment %+v", page.Comments[0])
+	}
+	last, err := c.ListComments(ctx, e.ID, client.ListCommentsOptions{Limit: 2, Cursor: page.NextCursor})
+	if err != nil || len(last.Comments) != 1 || last.NextCursor != "" || last.Comments[0].Body != "third" {
+		t.Fatalf("ListComments(page 2) = %+v, %v", last, err)
+	}
+	replies, err := c.ListCommentReplies(ctx, e.ID, root.ID, client.ListCommentsOptions{})
+	if err != nil || len(replies.Comments) != 1 || replies.Comments[0].ID != reply.ID {
+		t.Fatalf("ListCommentReplies = %+v, %v", replies, err)
+	}
+
+	if _, err := other.UpdateComment(ctx, e.ID, root.ID, "mine now"); client.StatusCode(err) != http.StatusForbidden {
+		t.Errorf("UpdateComment by another actor: want 403, got %v", err)
+	}
+	edited, err := c.UpdateComment(ctx, e.ID, root.ID, "Who brings the *projector*?")
+	if err != nil || edited.EditedAt == nil {
+		t.Fatalf("UpdateComment = %+v, %v", edited, err)
+	}
+	revisions, err := c.ListCommentRevisions(ctx, e.ID, root.ID)
+	if err != nil || len(revisions) != 1 || !strings.Contains(revisions[0].Body, "**projector**") {
+		t.Fatalf("ListCommentRevisions = %+v, %v", revisions, err)
+	}
+
+	// The root has a reply, so it stays as a tombstone until the reply goes.
+	if err := c.DeleteComment(ctx, e.ID, root.ID); err != nil {
+		t.Fatalf("DeleteComment: %v", err)
+	}
+	tomb, err := c.GetComment(ctx, e.ID, root.ID)
+	if err != nil || !tomb.Deleted || tomb.Body != "" || tomb.BodyHTML != "" {
+		t.Fatalf("GetComment(tombstone) = %+v, %v", tomb, err)
+	}
+	if _, err := c.CreateComment(ctx, e.ID, structures.CreateCommentRequest{Body: "late", ParentID: &root.ID}); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("reply to a deleted comment: want 400, got %v", err)
+	}
+	if err := other.DeleteComment(ctx, e.ID, reply.ID); err != nil {
+		t.Fatalf("DeleteComment(reply): %v", err)
+	}
+	if _, err := c.GetComment(ctx, e.ID, root.ID); !client.IsNotFound(err) {
+		t.Errorf("tombstone without replies: want 404, got %v", err)
+	}
+
+	if _, err := c.CreateComment(ctx, uuid.New(), structures.CreateCommentRequest{Body: "hi"}); !client.IsNotFound(err) {
+		t.Errorf("CreateComment on a missing event: want 404, got %v", err)
+	}
+	if _, err := c.CreateComment(ctx, e.ID, structures.CreateCommentRequest{Body: "  "}); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("CreateComment with a blank body: want 400, got %v", err)
+	}
+}
+
+func TestContract_Stats(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+	// baseTime is a Monday; the last event is on Sunday night in Madrid,
+	// which is already Monday in UTC+3.
+	for _, e := range []struct {
+		start time.Time
+		hours int
+		tags  []string
+	}{
+		{baseTime, 1, []string{"team:payments", "type:review"}},
+		{baseTime.Add(2 * time.Hour), 2, []string{"team:payments"}},
+		{baseTime.Add(3 * 24 * time.Hour), 1, nil},
+		{time.Date(2030, 3, 10, 22, 0, 0, 0, time.UTC), 3, []string{"type:review"}},
+	} {
+		req := eventRequest("Review", e.start)
+		req.EndTime = e.start.Add(time.Duration(e.hours) * time.Hour)
+		req.Tags = e.tags
+		if _, err := c.CreateEvent(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	from, to := baseTime.AddDate(0, 0, -7), baseTime.AddDate(0, 0, 14)
+
+	daily, err := c.GetEventStats(ctx, from, to, client.StatsOptions{TZ: "Europe/Madrid"})
+	if err != nil {
+		t.Fatalf("GetEventStats: %v", err)
+	}
+	if daily.Interval != structures.StatsDay || daily.Total.Events != 4 || daily.Total.Hours != 7 || len(daily.Buckets) != 3 {
+		t.Fatalf("unexpected daily stats %+v", daily)
+	}
+	if first := daily.Buckets[0]; first.Events != 2 || first.Hours != 3 || first.Start.Hour() != 0 {
+		t.Errorf("unexpected first bucket %+v", first)
+	}
+
+	weekly, err := c.GetEventStats(ctx, from, to, client.StatsOptions{Interval: structures.StatsWeek, TZ: "Europe/Istanbul", GroupBy: structures.StatsByTag})
+	if err != nil {
+		t.Fatalf("GetEventStats(weekly by tag): %v", err)
+	}
+	var got []string
+	for _, b := range weekly.Buckets {
+		got = append(got, b.Start.Format("01-02")+" "+b.Group+" "+strconv.Itoa(b.Events))
+	}
+	want := "03-04 team:payments 2,03-04 type:review 1,03-11 type:review 1"
+	if strings.Join(got, ",") != want || weekly.Total.Events != 4 {
+		t.Errorf("weekly buckets = %v, total %+v; want %s", got, weekly.Total, want)
+	}
+
+	if _, err := c.GetEventStats(ctx, to, from, client.StatsOptions{}); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("to before from: want 400, got %v", err)
+	}
+	if _, err := c.GetEventStats(ctx, from, from.AddDate(2, 0, 0), client.StatsOptions{}); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("two years of days: want 400, got %v", err)
+	}
+}
+
+func TestContract_Search(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+	req := eventRequest("Quarterly <planning> review", baseTime)
+	req.Description = "Bring the planning notes."
+	planning, err := c.CreateEvent(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if planning.Language != structures.DefaultSearchLanguage {
+		t.Errorf("expected the default language, got %q", planning.Language)
+	}
+	req = eventRequest("Revisión de planning", baseTime)
+	req.Language = "spanish"
+	spanish, err := c.CreateEvent(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateEvent(ctx, eventRequest("Standup", baseTime)); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := c.SearchEvents(ctx, "planning", client.SearchOptions{Limit: 1})
+	if err != nil || len(page.Results) != 1 || page.NextCursor == "" {
+		t.Fatalf("SearchEvents = %+v, %v", page, err)
+	}
+	best := page.Results[0]
+	if best.Event.ID != planning.ID {
+		t.Errorf("expected the title and description match first, got %q", best.Event.Title)
+	}
+	if !strings.Contains(best.TitleHeadline, "<mark>") || strings.Contains(best.TitleHeadline, "<planning>") {
+		t.Errorf("unexpected title headline %q", best.TitleHeadline)
+	}
+	if !strings.Contains(best.DescriptionHeadline, "<mark>planning</mark>") {
+		t.Errorf("unexpected description headline %q", best.DescriptionHeadline)
+	}
+	next, err := c.SearchEvents(ctx, "planning", client.SearchOptions{Limit: 1, Cursor: page.NextCursor})
+	if err != nil || len(next.Results) != 1 || next.NextCursor != "" || next.Results[0].Event.ID != spanish.ID {
+		t.Fatalf("SearchEvents(page 2) = %+v, %v", next, err)
+	}
+
+	only, err := c.SearchEvents(ctx, "planning", client.SearchOptions{Language: "spanish", TZ: "UTC"})
+	if err != nil || len(only.Results) != 1 || only.Results[0].Event.ID != spanish.ID {
+		t.Fatalf("SearchEvents(lang) = %+v, %v", only, err)
+	}
+	if _, err := c.SearchEvents(ctx, " ", client.SearchOptions{}); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("blank q: want 400, got %v", err)
+	}
+	if _, err := c.SearchEvents(ctx, "planning", client.SearchOptions{Language: "klingon"}); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("unknown lang: want 400, got %v", err)
+	}
+}
+
+func TestContract_AttendeesAndReminders(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+	e, err := c.CreateEvent(ctx, eventRequest("Review", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := c.AddAttendee(ctx, e.ID, structures.AddAttendeeRequest{Email: "Ana+dev@Example.com", DisplayName: "Ana"})
+	if err != nil || a.Email != "ana+dev@example.com" || a.RSVPStatus != structures.RSVPPending {
+		t.Fatalf("AddAttendee = %+v, %v", a, err)
+	}
+	a, err = c.UpdateRSVP(ctx, e.ID, a.Email, structures.RSVPAccepted)
+	if err != nil || a.RSVPStatus != structures.RSVPAccepted {
+		t.Fatalf("UpdateRSVP = %+v, %v", a, err)
+	}
+	list, err := c.ListAttendees(ctx, e.ID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListAttendees = %+v, %v", list, err)
+	}
+	if err := c.RemoveAttendee(ctx, e.ID, a.Email); err != nil {
+		t.Fatalf("RemoveAttendee: %v", err)
+	}
+	if err := c.RemoveAttendee(ctx, e.ID, a.Email); !client.IsNotFound(err) {
+		t.Errorf("RemoveAttendee twice: want 404, got %v", err)
+	}
+
+	r, err := c.CreateReminder(ctx, e.ID, structures.CreateReminderRequest{MinutesBefore: 15, Channel: structures.ReminderLog})
+	if err != nil || !r.RemindAt.Equal(baseTime.Add(-15*time.Minute)) {
+		t.Fatalf("CreateReminder = %+v, %v", r, err)
+	}
+	reminders, err := c.ListReminders(ctx, e.ID)
+	if err != nil || len(reminders) != 1 {
+		t.Fatalf("ListReminders = %+v, %v", reminders, err)
+	}
+	if err := c.DeleteReminder(ctx, e.ID, r.ID); err != nil {
+		t.Fatalf("DeleteReminder: %v", err)
+	}
+}
+
+func TestContract_ResourcesAndAvailability(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+	first, err := c.CreateEvent(ctx, eventRequest("First", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.CreateEvent(ctx, eventRequest("Second", baseTime.Add(30*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	room, err := c.CreateResource(ctx, structures.CreateResourceRequest{Name: "Room A", Kind: structures.ResourceRoom})
+	if err != nil {
+		t.Fatalf("CreateResource: %v", err)
+	}
+	if _, err := c.CreateResource(ctx, structures.CreateResourceRequest{Name: "Room A", Kind: structures.ResourceRoom}); !client.IsConflict(err) {
+		t.Errorf("CreateResource with a taken name: want 409, got %v", err)
+	}
+	if got, err := c.GetResourceByID(ctx, room.ID); err != nil || got.Name != "Room A" {
+		t.Errorf("GetResourceByID = %+v, %v", got, err)
+	}
+	if _, err := c.GetResourceByID(ctx, uuid.New()); !client.IsNotFound(err) {
+		t.Errorf("GetResourceByID of an unknown id: want 404, got %v", err)
+	}
+	if all, err := c.ListResources(ctx); err != nil || len(all) != 1 {
+		t.Errorf("ListResources = %+v, %v", all, err)
+	}
+
+	if _, err := c.BookResource(ctx, first.ID, room.ID); err != nil {
+		t.Fatalf("BookResource: %v", err)
+	}
+	_, err = c.BookResource(ctx, second.ID, room.ID)
+	conflict, ok := client.BookingConflict(err)
+	if !ok || len(conflict.ConflictingEventIDs) != 1 || conflict.ConflictingEventIDs[0] != first.ID {
+		t.Fatalf("BookResource over a taken slot: want a conflict with %s, got %v", first.ID, err)
+	}
+	if booked, err := c.ListEventResources(ctx, first.ID); err != nil || len(booked) != 1 {
+		t.Errorf("ListEventResources = %+v, %v", booked, err)
+	}
+
+	avail, err := c.GetAvailability(ctx, structures.AvailabilityQuery{
+		ResourceIDs: []uuid.UUID{room.ID},
+		From:        baseTime.Add(-time.Hour),
+		To:          baseTime.Add(2 * time.Hour),
+		Duration:    30 * time.Minute,
+	})
+	if err != nil || len(avail.Busy) != 1 || len(avail.FreeSlots) != 2 {
+		t.Fatalf("GetAvailability = %+v, %v", avail, err)
+	}
+
+	if err := c.ReleaseResource(ctx, first.ID, room.ID); err != nil {
+		t.Fatalf("ReleaseResource: %v", err)
+	}
+	if _, err := c.BookResource(ctx, second.ID, room.ID); err != nil {
+		t.Errorf("BookResource after release: %v", err)
+	}
+}
+
+func TestContract_Webhooks(t *testing.T) {
+	c, url := newTestAPI(t)
+	ctx := context.Background()
+	other, err := client.New(url, client.WithToken(otherToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook, err := c.CreateWebhook(ctx, structures.CreateWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{structures.EventCreated}})
+	if err != nil || hook.Secret == "" {
+		t.Fatalf("CreateWebhook = %+v, %v", hook, err)
+	}
+	if got, err := c.GetWebhook(ctx, hook.ID); err != nil || got.URL != hook.URL || got.Secret != "" {
+		t.Errorf("GetWebhook = %+v, %v; want it without the secret", got, err)
+	}
+	if hooks, err := c.ListWebhooks(ctx); err != nil || len(hooks) != 1 {
+		t.Errorf("ListWebhooks = %+v, %v", hooks, err)
+	}
+	if _, err := c.ListWebhookDeliveries(ctx, hook.ID, structures.DeliveryFailed, 10); err != nil {
+		t.Errorf("ListWebhookDeliveries: %v", err)
+	}
+
+	// Webhooks belong to their creator; other callers cannot see them.
+	if hooks, err := other.ListWebhooks(ctx); err != nil || len(hooks) != 0 {
+		t.Errorf("ListWebhooks as another caller = %+v, %v; want none", hooks, err)
+	}
+	if _, err := other.GetWebhook(ctx, hook.ID); !client.IsNotFound(err) {
+		t.Errorf("GetWebhook as another caller: want 404, got %v", err)
+	}
+	if _, err := other.ListWebhookDeliveries(ctx, hook.ID, "", 10); !client.IsNotFound(err) {
+		t.Errorf("ListWebhookDeliveries as another caller: want 404, got %v", err)
+	}
+	if err := other.DeleteWebhook(ctx, hook.ID); !client.IsNotFound(err) {
+		t.Errorf("DeleteWebhook as another caller: want 404, got %v", err)
+	}
+	if _, err := c.ListWebhookDeliveries(ctx, hook.ID, "bogus", 0); client.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("ListWebhookDeliveries with a bad status: want 400, got %v", err)
+	}
+	if err := c.DeleteWebhook(ctx, hook.ID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if err := c.DeleteWebhook(ctx, hook.ID); !client.IsNotFound(err) {
+		t.Errorf("DeleteWebhook twice: want 404, got %v", err)
+	}
+}
+
+func TestContract_StreamAndWebSocket(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e, err := c.CreateEvent(ctx, eventRequest("Launch", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := int64(0)
+	next, stop := iter.Pull2(c.StreamEvents(ctx, client.StreamOptions{LastEventID: &from}))
+	defer stop()
+	ch, err, ok := next()
+	if !ok || err != nil || ch.ID != 1 || ch.Type != structures.EventCreated || ch.EventID != e.ID {
+		t.Fatalf("first streamed change = %+v, %v", ch, err)
+	}
+	if got, err := ch.Event(); err != nil || got.Title != "Launch" {
+		t.Errorf("Change.Event = %+v, %v", got, err)
+	}
+
+	sub, err := c.EventsWebSocket(ctx)
+	if err != nil {
+		t.Fatalf("EventsWebSocket: %v", err)
+	}
+	defer sub.Close()
+	if err := sub.Subscribe(structures.ChangeFilter{EventIDs: []uuid.UUID{e.ID}}); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := sub.Next(); err != nil || m.Type != "subscribed" {
+		t.Fatalf("expected a subscribed ack, got %+v, %v", m, err)
+	}
+
+	if _, err := c.UpdateEvent(ctx, e.ID, eventRequest("Launch v2", baseTime)); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := sub.Next(); err != nil || m.Type != "change" || m.Event != structures.EventUpdated || m.EventID != e.ID {
+		t.Errorf("WebSocket change = %+v, %v", m, err)
+	}
+	ch, err, ok = next()
+	if !ok || err != nil || ch.ID != 2 || ch.Type != structures.EventUpdated {
+		t.Errorf("second streamed change = %+v, %v", ch, err)
+	}
+}
+
+func TestContract_GraphQL(t *testing.T) {
+	c, _ := newTestAPI(t)
+	ctx := context.Background()
+	e, err := c.CreateEvent(ctx, eventRequest("Demo", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Event struct {
+			Title string `json:"title"`
+		} `json:"event"`
+	}
+	if err := c.GraphQL(ctx, `query($id: ID!) { event(id: $id) { title } }`, map[string]any{"id": e.ID}, &out); err != nil {
+		t.Fatalf("GraphQL: %v", err)
+	}
+	if out.Event.Title != "Demo" {
+		t.Errorf("event title = %q", out.Event.Title)
+	}
+
+	var gqlErr *client.GraphQLError
+	if err := c.GraphQL(ctx, `{ nope }`, nil, nil); !errors.As(err, &gqlErr) {
+		t.Errorf("invalid query: want a GraphQLError, got %v", err)
+	}
+}
+
+func TestContract_OpenAPISpec(t *testing.T) {
+	c, _ := newTestAPI(t)
+	spec, err := c.GetOpenAPISpec(context.Background())
+	if err != nil || string(spec) != string(docs.OpenAPI) {
+		t.Fatalf("GetOpenAPISpec = %d bytes, %v; want docs/openapi.yaml", len(spec), err)
+	}
+}
+
+func TestClient_Unauthenticated(t *testing.T) {
+	_, url := newTestAPI(t)
+	c, err := client.New(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListEvents(context.Background(), client.ListEventsOptions{}); client.StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("want 401 without a token, got %v", err)
+	}
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	c, err := client.New(srv.URL, client.WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.ListWebhooks(context.Background()); err != nil || calls.Load() != 3 {
+		t.Fatalf("ListWebhooks = %v after %d calls; want success on the third", err, calls.Load())
+	}
+
+	calls.Store(0)
+	_, err = c.CreateWebhook(context.Background(), structures.CreateWebhookRequest{URL: "https://example.com"})
+	if client.StatusCode(err) != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("CreateWebhook = %v after %d calls; want one unretried 503", err, calls.Load())
+	}
+}
+
+func TestClient_GivesUpWhenContextEnds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	c, err := client.New(srv.URL, client.WithRetries(10, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListResources(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want the context error, got %v", err)
+	}
+}
+
+func TestClient_DecodesProblemDetails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"type": "https://example.com/probs/capacity", "title": "Event is full", "status": 422, "detail": "capacity 10 reached"}`))
+	}))
+	defer srv.Close()
+	c, err := client.New(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.AddAttendee(context.Background(), uuid.New(), structures.AddAttendeeRequest{Email: "a@example.com"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want *client.Error, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Title != "Event is full" ||
+		apiErr.Detail != "capacity 10 reached" || apiErr.Type != "https://example.com/probs/capacity" {
+		t.Errorf("unexpected problem %+v", apiErr)
+	}
+}
+
+func TestContract_Calendars(t *testing.T) {
+	c, url := newTestAPI(t)
+	ctx := context.Background()
+	other, err := client.New(url, client.WithToken(otherToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherActor, err := utils.NewTokenAuth([]string{otherToken}).Authenticate("Bearer " + otherToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team, err := c.CreateCalendar(ctx, structures.CalendarRequest{Name: "Team", TimeZone: "Europe/Madrid"})
+	if err != nil || team.Color != structures.DefaultCalendarColor || team.Permission != structures.PermissionManage {
+		t.Fatalf("CreateCalendar = %+v, %v", team, err)
+	}
+	req := eventRequest("Retro", baseTime)
+	req.TimeZone = ""
+	retro, err := c.CreateCalendarEvent(ctx, team.ID, req)
+	if err != nil || retro.CalendarID != team.ID || retro.TimeZone != "Europe/Madrid" {
+		t.Fatalf("CreateCalendarEvent = %+v, %v; want it in the calendar's time zone", retro, err)
+	}
+	flat, err := c.CreateEvent(ctx, eventRequest("Elsewhere", baseTime))
+	if err != nil || flat.CalendarID != structures.DefaultCalendarID {
+		t.Fatalf("CreateEvent = %+v, %v; want it in the default calendar", flat, err)
+	}
+	page, err := c.ListCalendarEvents(ctx, team.ID, client.ListEventsOptions{})
+	if err != nil || len(page.Events) != 1 || page.Events[0].ID != retro.ID {
+		t.Fatalf("ListCalendarEvents = %+v, %v", page, err)
+	}
+
+	// Until it is shared, the other caller cannot tell the calendar exists.
+	if _, err := other.GetCalendar(ctx, team.ID); !client.IsNotFound(err) {
+		t.Errorf("GetCalendar by a stranger: want 404, got %v", err)
+	}
+	if cals, err := other.ListCalendars(ctx); err != nil || len(cals) != 1 || cals[0].ID != structures.DefaultCalendarID {
+		t.Errorf("ListCalendars by a stranger = %+v, %v; want only the default calendar", cals, err)
+	}
+	sneaky := eventRequest("Sneaky", baseTime)
+	sneaky.CalendarID = team.ID
+	if _, err := other.CreateEvent(ctx, sneaky); !client.IsNotFound(err) {
+		t.Errorf("CreateEvent in a stranger's calendar: want 404, got %v", err)
+	}
+
+	if _, err := c.ShareCalendar(ctx, team.ID, otherActor, structures.PermissionRead); err != nil {
+		t.Fatalf("ShareCalendar: %v", err)
+	}
+	if _, err := other.ListCalendarEvents(ctx, team.ID, client.ListEventsOptions{}); err != nil {
+		t.Errorf("ListCalendarEvents by a reader: %v", err)
+	}
+	var apiErr *client.Error
+	if _, err := other.CreateCalendarEvent(ctx, team.ID, eventRequest("Nope", baseTime)); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("CreateCalendarEvent by a reader: want 403, got %v", err)
+	}
+	if _, err := other.ShareCalendar(ctx, team.ID, otherActor, structures.PermissionManage); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("ShareCalendar by a reader: want 403, got %v", err)
+	}
+
+	if _, err := c.ShareCalendar(ctx, team.ID, otherActor, structures.PermissionWrite); err != nil {
+		t.Fatalf("ShareCalendar(write): %v", err)
+	}
+	if _, err := other.CreateCalendarEvent(ctx, team.ID, eventRequest("Planning", baseTime.Add(time.Hour))); err != nil {
+		t.Errorf("CreateCalendarEvent by a writer: %v", err)
+	}
+	shares, err := c.ListCalendarShares(ctx, team.ID)
+	if err != nil || len(shares) != 1 || shares[0].Actor != otherActor || shares[0].Permission != structures.PermissionWrite {
+		t.Errorf("ListCalendarShares = %+v, %v", shares, err)
+	}
+	renamed, err := c.UpdateCalendar(ctx, team.ID, structures.CalendarRequest{Name: "Core team", Color: "#ff8800"})
+	if err != nil || renamed.Name != "Core team" || renamed.TimeZone != "UTC" {
+		t.Errorf("UpdateCalendar = %+v, %v", renamed, err)
+	}
+	if err := c.UnshareCalendar(ctx, team.ID, otherActor); err != nil {
+		t.Errorf("UnshareCalendar: %v", err)
+	}
+	if err := c.UnshareCalendar(ctx, team.ID, otherActor); !client.IsNotFound(err) {
+		t.Errorf("UnshareCalendar twice: want 404, got %v", err)
+	}
+
+	if err := c.DeleteCalendar(ctx, team.ID); !client.IsConflict(err) {
+		t.Errorf("DeleteCalendar with events: want 409, got %v", err)
+	}
+	all, err := c.ListCalendarEvents(ctx, team.ID, client.ListEventsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range all.Events {
+		if err := c.DeleteEvent(ctx, e.ID); err != nil {
+			t.Fatalf("DeleteEvent: %v", err)
+		}
+	}
+	if err := c.DeleteCalendar(ctx, team.ID); err != nil {
+		t.Errorf("DeleteCalendar: %v", err)
+	}
+	if err := c.DeleteCalendar(ctx, structures.DefaultCalendarID); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("DeleteCalendar(default): want 403, got %v", err)
+	}
+}
+
+func TestContract_EventPermissions(t *testing.T) {
+	c, url := newTestAPI(t)
+	ctx := context.Background()
+	other, err := client.New(url, client.WithToken(otherToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherActor, err := utils.NewTokenAuth([]string{otherToken}).Authenticate("Bearer " + otherToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	private, err := c.CreateCalendar(ctx, structures.CalendarRequest{Name: "Private"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := c.CreateCalendarEvent(ctx, private.ID, eventRequest("Secret offsite", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddAttendee(ctx, secret.ID, structures.AddAttendeeRequest{Email: "ana@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	public, err := c.CreateEvent(ctx, eventRequest("Public offsite", baseTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The event is in a calendar the other caller cannot read, so for them
+	// it does not exist.
+	notFound := map[string]error{}
+	_, notFound["GetEventByID"] = other.GetEventByID(ctx, secret.ID, "")
+	_, notFound["UpdateEvent"] = other.UpdateEvent(ctx, secret.ID, eventRequest("Mine now", baseTime))
+	notFound["DeleteEvent"] = other.DeleteEvent(ctx, secret.ID)
+	_, notFound["CancelEvent"] = other.CancelEvent(ctx, secret.ID, "")
+	_, notFound["ListEventHistory"] = other.ListEventHistory(ctx, secret.ID)
+	_, notFound["ListAttendees"] = other.ListAttendees(ctx, secret.ID)
+	_, notFound["AddAttendee"] = other.AddAttendee(ctx, secret.ID, structures.AddAttendeeRequest{Email: "eve@example.com"})
+	_, notFound["ListComments"] = other.ListComments(ctx, secret.ID, client.ListCommentsOptions{})
+	_, notFound["CreateComment"] = other.CreateComment(ctx, secret.ID, structures.CreateCommentRequest{Body: "hi"})
+	_, notFound["ListAttachments"] = other.ListAttachments(ctx, secret.ID)
+	for op, err := range notFound {
+		if !client.IsNotFound(err) {
+			t.Errorf("%s by a stranger: want 404, got %v", op, err)
+		}
+	}
+
+	page, err := other.ListEvents(ctx, client.ListEventsOptions{})
+	if err != nil || len(page.Events) != 1 || page.Events[0].ID != public.ID {
+		t.Errorf("ListEvents by a stranger = %+v, %v; want only the public event", page, err)
+	}
+	found, err := other.SearchEvents(ctx, "offsite", client.SearchOptions{})
+	if err != nil || len(found.Results) != 1 || found.Results[0].Event.ID != public.ID {
+		t.Errorf("SearchEvents by a stranger = %+v, %v; want only the public event", found, err)
+	}
+	stats, err := other.GetEventStats(ctx, baseTime, baseTime.Add(24*time.Hour), client.StatsOptions{})
+	if err != nil || stats.Total.Events != 1 {
+		t.Errorf("GetEventStats by a stranger = %+v, %v; want only the public event counted", stats, err)
+	}
+
+	// A read share shows the event but does not let the reader change it.
+	if _, err := c.ShareCalendar(ctx, private.ID, otherActor, structures.PermissionRead); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.GetEventByID(ctx, secret.ID, ""); err != nil {
+		t.Errorf("GetEventByID by a reader: %v", err)
+	}
+	if attendees, err := other.ListAttendees(ctx, secret.ID); err != nil || len(attendees) != 1 {
+		t.Errorf("ListAttendees by a reader = %+v, %v", attendees, err)
+	}
+	forbidden := map[string]error{}
+	_, forbidden["UpdateEvent"] = other.UpdateEvent(ctx, secret.ID, eventRequest("Mine now", baseTime))
+	forbidden["DeleteEvent"] = other.DeleteEvent(ctx, secret.ID)
+	_, forbidden["CancelEvent"] = other.CancelEvent(ctx, secret.ID, "")
+	forbidden["RemoveAttendee"] = other.RemoveAttendee(ctx, secret.ID, "ana@example.com")
+	_, forbidden["CreateComment"] = other.CreateComment(ctx, secret.ID, structures.CreateCommentRequest{Body: "hi"})
+	_, forbidden["UploadAttachment"] = other.UploadAttachment(ctx, secret.ID, "notes.txt", "", strings.NewReader("notes"))
+	for op, err := range forbidden {
+		var apiErr *client.Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+			t.Errorf("%s by a reader: want 403, got %v", op, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func commentsPath(eventID uuid.UUID) string {
+	return "/events/" + eventID.String() + "/comments"
+}
+
+func commentPath(eventID, id uuid.UUID) string {
+	return commentsPath(eventID) + "/" + id.String()
+}
+
+// ListCommentsOptions pages through a thread. Zero values use the server
+// defaults.
+type ListCommentsOptions struct {
+	Limit  int
+	Cursor string
+}
+
+func (o ListCommentsOptions) values() url.Values {
+	q := url.Values{}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q
+}
+
+// CreateComment comments on an event, or replies to req.ParentID.
+func (c *Client) CreateComment(ctx context.Context, eventID uuid.UUID, req structures.CreateCommentRequest) (*structures.Comment, error) {
+	var comment structures.Comment
+	if _, err := c.do(ctx, http.MethodPost, commentsPath(eventID), nil, req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments returns one page of the top-level comments of an event.
+// NextCursor is empty on the last page.
+func (c *Client) ListComments(ctx context.Context, eventID uuid.UUID, opts ListCommentsOptions) (*structures.CommentPage, error) {
+	return c.listComments(ctx, commentsPath(eventID), opts)
+}
+
+// ListCommentReplies returns one page of the direct replies to a comment.
+func (c *Client) ListCommentReplies(ctx context.Context, eventID, id uuid.UUID, opts ListCommentsOptions) (*structures.CommentPage, error) {
+	return c.listComments(ctx, commentPath(eventID, id)+"/replies", opts)
+}
+
+func (c *Client) listComments(ctx context.Context, path string, opts ListCommentsOptions) (*structures.CommentPage, error) {
+	var page structures.CommentPage
+	resp, err := c.do(ctx, http.MethodGet, path, opts.values(), nil, &page.Comments)
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = nextCursor(resp.Header)
+	return &page, nil
+}
+
+func (c *Client) GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error) {
+	var comment structures.Comment
+	if _, err := c.do(ctx, http.MethodGet, commentPath(eventID, id), nil, nil, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (c *Client) UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string) (*structures.Comment, error) {
+	var comment structures.Comment
+	req := structures.UpdateCommentRequest{Body: body}
+	if _, err := c.do(ctx, http.MethodPut, commentPath(eventID, id), nil, req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (c *Client) DeleteComment(ctx context.Context, eventID, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, commentPath(eventID, id), nil, nil, nil)
+	return err
+}
+
+// ListCommentRevisions returns the earlier bodies of a comment, oldest
+// first.
+func (c *Client) ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error) {
+	var revisions []structures.CommentRevision
+	if _, err := c.do(ctx, http.MethodGet, commentPath(eventID, id)+"/revisions", nil, nil, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// ListEventsOptions are the query parameters of listEvents. With only TZ
+// set the server returns every event in one response.
+type ListEventsOptions struct {
+	// TZ renders times in this IANA zone instead of each event's own.
+	TZ string
+
+	From  *time.Time
+	To    *time.Time
+	Title string
+
+	Limit  int
+	Cursor string
+
+	// Status keeps events in any of these statuses.
+	Status []string
+
+	// Tags keeps events that have all of these tags.
+	Tags []string
+	// Metadata keeps events whose metadata has these string values, keyed
+	// by dotted path such as "room.floor".
+	Metadata map[string]string
+
+	// Near keeps events within RadiusKm of it and reports their distance.
+	Near     *structures.GeoPoint
+	RadiusKm float64
+
+	// IncludeDeleted also lists events in the trash.
+	IncludeDeleted bool
+}
+
+func (o ListEventsOptions) values() url.Values {
+	q := url.Values{}
+	if o.TZ != "" {
+		q.Set("tz", o.TZ)
+	}
+	if o.From != nil {
+		q.Set("from", o.From.Format(time.RFC3339))
+	}
+	if o.To != nil {
+		q.Set("to", o.To.Format(time.RFC3339))
+	}
+	if o.Title != "" {
+		q.Set("title", o.Title)
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	if len(o.Status) > 0 {
+		q.Set("status", strings.Join(o.Status, ","))
+	}
+	for _, tag := range o.Tags {
+		q.Add("tag", tag)
+	}
+	for path, v := range o.Metadata {
+		q.Set("metadata."+path, v)
+	}
+	if o.Near != nil {
+		q.Set("near", strconv.FormatFloat(o.Near.Lat, 'f', -1, 64)+","+strconv.FormatFloat(o.Near.Lng, 'f', -1, 64))
+		q.Set("radius_km", strconv.FormatFloat(o.RadiusKm, 'f', -1, 64))
+	}
+	if o.IncludeDeleted {
+		q.Set("include_deleted", "true")
+	}
+	return q
+}
+
+// ListEvents returns one page of events. NextCursor is empty on the last
+// page; pass it back as opts.Cursor for the next one, or use Events.
+func (c *Client) ListEvents(ctx context.Context, opts ListEventsOptions) (*structures.EventPage, error) {
+	var page structures.EventPage
+	resp, err := c.do(ctx, http.MethodGet, "/events", opts.values(), nil, &page.Events)
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = nextCursor(resp.Header)
+	return &page, nil
+}
+
+// Events iterates over every event matching opts, fetching pages as
+// needed. Iteration stops after the first error.
+func (c *Client) Events(ctx context.Context, opts ListEventsOptions) iter.Seq2[structures.Event, error] {
+	if opts.Limit == 0 {
+		opts.Limit = structures.MaxEventPageSize
+	}
+	return func(yield func(structures.Event, error) bool) {
+		for {
+			page, err := c.ListEvents(ctx, opts)
+			if err != nil {
+				yield(structures.Event{}, err)
+				return
+			}
+			for _, e := range page.Events {
+				if !yield(e, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+}
+
+// nextCursor extracts the cursor of the rel="next" link, if any.
+func nextCursor(h http.Header) string {
+	for _, link := range h.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(part, ";")
+			if !ok || !strings.Contains(params, `rel="next"`) {
+				continue
+			}
+			target = strings.Trim(strings.TrimSpace(target), "<>")
+			if u, err := url.Parse(target); err == nil {
+				return u.Query().Get("cursor")
+			}
+		}
+	}
+	return ""
+}
+
+func (c *Client) CreateEvent(ctx context.Context, req structures.CreateEventRequest) (*structures.Event, error) {
+	var e structures.Event
+	if _, err := c.do(ctx, http.MethodPost, "/events", nil, req, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetEventByID returns the event, with times in tz when it is not empty.
+func (c *Client) GetEventByID(ctx context.Context, id uuid.UUID, tz string) (*structures.Event, error) {
+	q := url.Values{}
+	if tz != "" {
+		q.Set("tz", tz)
+	}
+	var e structures.Event
+	if _, err := c.do(ctx, http.MethodGet, "/events/"+id.String(), q, nil, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetEventAsOf returns the event as it was at the given time, rebuilt from
+// its history. It is a 404 when the event did not exist then.
+func (c *Client) GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time, tz string) (*structures.Event, error) {
+	q := url.Values{"as_of": {at.Format(time.RFC3339Nano)}}
+	if tz != "" {
+		q.Set("tz", tz)
+	}
+	var e structures.Event
+	if _, err := c.do(ctx, http.MethodGet, "/events/"+id.String(), q, nil, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListEventHistory returns every change made to the event, oldest first.
+func (c *Client) ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error) {
+	var entries []structures.EventHistoryEntry
+	if _, err := c.do(ctx, http.MethodGet, "/events/"+id.String()+"/history", nil, nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (c *Client) UpdateEvent(ctx context.Context, id uuid.UUID, req structures.CreateEventRequest) (*structures.Event, error) {
+	var e structures.Event
+	if _, err := c.do(ctx, http.MethodPut, "/events/"+id.String(), nil, req, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (c *Client) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, "/events/"+id.String(), nil, nil, nil)
+	return err
+}
+
+// RestoreEvent takes a deleted event out of the trash. It is a 409 when
+// the event is not deleted.
+func (c *Client) RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	var e structures.Event
+	if _, err := c.do(ctx, http.MethodPost, "/events/"+id.String()+":restore", nil, nil, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ScheduleEvent publishes a draft or brings back a postponed or cancelled
+// event. Every transition is a 409 when the event's current status does
+// not allow it; reason may be empty.
+func (c *Client) ScheduleEvent(ctx context.Context, id uuid.UUID, reason string) (*structures.Event, error) {
+	return c.transition(ctx, id, "schedule", reason)
+}
+
+func (c *Client) PostponeEvent(ctx context.Context, id uuid.UUID, reason string) (*structures.Event, error) {
+	return c.transition(ctx, id, "postpone", reason)
+}
+
+func (c *Client) CancelEvent(ctx context.Context, id uuid.UUID, reason string) (*structures.Event, error) {
+	return c.transition(ctx, id, "cancel", reason)
+}
+
+func (c *Client) CompleteEvent(ctx context.Context, id uuid.UUID, reason string) (*structures.Event, error) {
+	return c.transition(ctx, id, "complete", reason)
+}
+
+func (c *Client) transition(ctx context.Context, id uuid.UUID, action, reason string) (*structures.Event, error) {
+	var e structures.Event
+	req := structures.StatusChangeRequest{Reason: reason}
+	if _, err := c.do(ctx, http.MethodPost, "/events/"+id.String()+":"+action, nil, req, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetOpenAPISpec returns the API description the server was built with.
+func (c *Client) GetOpenAPISpec(ctx context.Context) ([]byte, error) {
+	resp, err := c.send(ctx, http.MethodGet, c.endpoint("/openapi.yaml", nil), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,1045 @@
+package client_test
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// memStore is an in-memory stand-in for every Postgres store, so the
+// contract test runs the real controllers and services without a
+// database. The scheduler and delivery worker methods are left to the
+// embedded nil interfaces; nothing in the test calls them.
+type memStore struct {
+	services.ReminderStore
+	services.WebhookStore
+
+	mu        sync.Mutex
+	events    map[uuid.UUID]structures.Event
+	trash     map[uuid.UUID]structures.Event
+	attendees map[uuid.UUID][]structures.Attendee
+	resources map[uuid.UUID]structures.Resource
+	bookings  []structures.Booking
+	reminders []structures.Reminder
+	webhooks  []structures.Webhook
+	outbox    []structures.OutboxMessage
+	history   []memHistory
+	calendars map[uuid.UUID]structures.Calendar
+	shares    map[uuid.UUID]map[string]structures.CalendarShare
+
+	attachments []structures.Attachment
+	comments    []structures.Comment
+	revisions   []structures.CommentRevision
+}
+
+type memHistory struct {
+	entry    structures.EventHistoryEntry
+	snapshot structures.Event
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		events:    make(map[uuid.UUID]structures.Event),
+		trash:     make(map[uuid.UUID]structures.Event),
+		attendees: make(map[uuid.UUID][]structures.Attendee),
+		resources: make(map[uuid.UUID]structures.Resource),
+		calendars: map[uuid.UUID]structures.Calendar{
+			structures.DefaultCalendarID: {ID: structures.DefaultCalendarID, Name: "Default", Owner: structures.SystemActor, TimeZone: "UTC"},
+		},
+		shares: map[uuid.UUID]map[string]structures.CalendarShare{
+			structures.DefaultCalendarID: {structures.Everyone: {
+				CalendarID: structures.DefaultCalendarID, Actor: structures.Everyone, Permission: structures.PermissionWrite,
+			}},
+		},
+	}
+}
+
+// record appends an outbox message; the caller holds mu.
+func (s *memStore) record(typ string, eventID uuid.UUID, v any) {
+	payload, _ := json.Marshal(v)
+	s.outbox = append(s.outbox, structures.OutboxMessage{
+		ID:        int64(len(s.outbox) + 1),
+		EventID:   eventID,
+		Type:      typ,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	})
+}
+
+// audit appends a history entry; the caller holds mu.
+func (s *memStore) audit(ctx context.Context, op string, before, after *structures.Event) {
+	snapshot := after
+	if snapshot == nil {
+		snapshot = before
+	}
+	diff, _ := structures.DiffEvents(before, after)
+	s.history = append(s.history, memHistory{
+		entry: structures.EventHistoryEntry{
+			ID:        int64(len(s.history) + 1),
+			EventID:   snapshot.ID,
+			Operation: op,
+			Actor:     structures.ActorFromContext(ctx),
+			ChangedAt: time.Now(),
+			Diff:      diff,
+		},
+		snapshot: *snapshot,
+	})
+}
+
+func (s *memStore) CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[e.ID] = *e
+	s.record(structures.EventCreated, e.ID, e)
+	s.audit(ctx, structures.HistoryCreate, nil, e)
+	return e, nil
+}
+
+func (s *memStore) sortedEvents(includeDeleted bool) []structures.Event {
+	events := make([]structures.Event, 0, len(s.events))
+	for _, e := range s.events {
+		e.Attendees = structures.AttendeeCounts{Total: len(s.attendees[e.ID])}
+		events = append(events, e)
+	}
+	if includeDeleted {
+		for _, e := range s.trash {
+			events = append(events, e)
+		}
+	}
+	slices.SortFunc(events, func(a, b structures.Event) int {
+		if c := a.StartTime.Compare(b.StartTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return events
+}
+
+// hasMetadata reports whether every dotted path in filters leads to the
+// same string value in metadata.
+func hasMetadata(metadata map[string]any, filters map[string]string) bool {
+	for path, want := range filters {
+		var v any = metadata
+		for _, k := range strings.Split(path, ".") {
+			obj, _ := v.(map[string]any)
+			v = obj[k]
+		}
+		if v != want {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *memStore) ListEvents(ctx context.Context) ([]structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedEvents(false), nil
+}
+
+func (s *memStore) QueryEvents(ctx context.Context, q structures.EventQuery) (*structures.EventPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page := &structures.EventPage{Events: []structures.Event{}}
+	for _, e := range s.sortedEvents(q.IncludeDeleted) {
+		switch {
+		case q.From != nil && !e.EndTime.After(*q.From),
+			q.To != nil && !e.StartTime.Before(*q.To),
+			!strings.Contains(strings.ToLower(e.Title), strings.ToLower(q.TitleContains)),
+			len(q.Statuses) > 0 && !slices.Contains(q.Statuses, e.Status),
+			slices.ContainsFunc(q.Tags, func(t string) bool { return !slices.Contains(e.Tags, t) }),
+			!hasMetadata(e.Metadata, q.Metadata),
+			q.CalendarID != nil && e.CalendarID != *q.CalendarID,
+			!inCalendars(e, q.CalendarIDs),
+			q.Near != nil && !within(e, *q.Near, q.RadiusKm):
+			continue
+		case q.After != nil:
+			c := e.StartTime.Compare(q.After.StartTime)
+			if c < 0 || c == 0 && strings.Compare(e.ID.String(), q.After.ID.String()) <= 0 {
+				continue
+			}
+		}
+		if len(page.Events) == q.Limit {
+			page.NextCursor = structures.CursorOf(page.Events[q.Limit-1]).Encode()
+			break
+		}
+		if p, ok := e.Location.Point(); ok && q.Near != nil {
+			d := q.Near.DistanceKm(p)
+			e.DistanceKm = &d
+		}
+		page.Events = append(page.Events, e)
+	}
+	return page, nil
+}
+
+// SearchEvents ranks events by how many of the query's words their title
+// (twice) and description contain; there is no stemming.
+func (s *memStore) SearchEvents(ctx context.Context, q structures.SearchQuery) (*structures.SearchPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	words := strings.Fields(strings.ToLower(q.Text))
+	var results []structures.SearchResult
+	for _, e := range s.events {
+		if q.Language != "" && e.Language != q.Language || !inCalendars(e, q.CalendarIDs) {
+			continue
+		}
+		title, titleHits := markWords(e.Title, words)
+		description, descriptionHits := markWords(e.Description, words)
+		if titleHits+descriptionHits == 0 {
+			continue
+		}
+		results = append(results, structures.SearchResult{
+			Event:               e,
+			Rank:                float32(2*titleHits + descriptionHits),
+			TitleHeadline:       title,
+			DescriptionHeadline: description,
+		})
+	}
+	slices.SortFunc(results, func(a, b structures.SearchResult) int {
+		if a.Rank != b.Rank {
+			return -cmp.Compare(a.Rank, b.Rank)
+		}
+		return strings.Compare(a.Event.ID.String(), b.Event.ID.String())
+	})
+	page := &structures.SearchPage{Results: []structures.SearchResult{}}
+	for _, r := range results {
+		if a := q.After; a != nil && (r.Rank > a.Rank || r.Rank == a.Rank && strings.Compare(r.Event.ID.String(), a.ID.String()) <= 0) {
+			continue
+		}
+		if len(page.Results) == q.Limit {
+			page.NextCursor = structures.SearchCursorOf(page.Results[q.Limit-1]).Encode()
+			break
+		}
+		page.Results = append(page.Results, r)
+	}
+	return page, nil
+}
+
+func (s *memStore) EventStats(ctx context.Context, q structures.StatsQuery) (*structures.EventStats, error) {
+	loc, err := structures.LoadTimeZone(q.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := &structures.EventStats{Interval: q.Interval, TimeZone: q.TimeZone, GroupBy: q.GroupBy, Buckets: []structures.StatsBucket{}}
+	buckets := make(map[structures.StatsBucket]*structures.StatsBucket)
+	for _, e := range s.events {
+		if e.StartTime.Before(q.From) || !e.StartTime.Before(q.To) || !inCalendars(e, q.CalendarIDs) {
+			continue
+		}
+		hours := e.EndTime.Sub(e.StartTime).Hours()
+		stats.Total.Events++
+		stats.Total.Hours += hours
+		groups := []string{""}
+		switch q.GroupBy {
+		case structures.StatsByStatus:
+			groups = []string{e.Status}
+		case structures.StatsByTag:
+			groups = e.Tags
+		}
+		for _, g := range groups {
+			key := structures.StatsBucket{Start: truncate(e.StartTime.In(loc), q.Interval), Group: g}
+			b, ok := buckets[key]
+			if !ok {
+				b = &structures.StatsBucket{Start: key.Start, Group: g}
+				buckets[key] = b
+			}
+			b.Events++
+			b.Hours += hours
+		}
+	}
+	for _, b := range buckets {
+		stats.Buckets = append(stats.Buckets, *b)
+	}
+	slices.SortFunc(stats.Buckets, func(a, b structures.StatsBucket) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Group, b.Group)
+	})
+	return stats, nil
+}
+
+// truncate is Postgres' date_trunc for the stats intervals.
+func truncate(t time.Time, interval string) time.Time {
+	y, m, d := t.Date()
+	switch interval {
+	case structures.StatsMonth:
+		d = 1
+	case structures.StatsWeek:
+		d -= (int(t.Weekday()) + 6) % 7
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// markWords wraps the words of text that are in words with the highlight
+// markers and counts them.
+func markWords(text string, words []string) (string, int) {
+	hits := 0
+	fields := strings.Fields(text)
+	for i, f := range fields {
+		word := strings.TrimFunc(f, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if slices.Contains(words, strings.ToLower(word)) {
+			fields[i] = structures.HighlightStart + f + structures.HighlightStop
+			hits++
+		}
+	}
+	return strings.Join(fields, " "), hits
+}
+
+// inCalendars reports whether e is in one of calendarIDs; nil allows all.
+func inCalendars(e structures.Event, calendarIDs []uuid.UUID) bool {
+	return calendarIDs == nil || slices.Contains(calendarIDs, e.CalendarID)
+}
+
+func within(e structures.Event, near structures.GeoPoint, radiusKm float64) bool {
+	p, ok := e.Location.Point()
+	return ok && near.DistanceKm(p) <= radiusKm
+}
+
+func (s *memStore) GetEvents(ctx context.Context, ids []uuid.UUID) ([]structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []structures.Event
+	for _, id := range ids {
+		if e, ok := s.events[id]; ok {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) GetEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[id]
+	if !ok {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+func (s *memStore) EventCalendarID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.events[id]; ok {
+		return e.CalendarID, nil
+	}
+	if e, ok := s.trash[id]; ok {
+		return e.CalendarID, nil
+	}
+	return uuid.Nil, structures.ErrEventNotFound
+}
+
+func (s *memStore) UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.events[e.ID]
+	if !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	for i, b := range s.bookings {
+		if b.EventID != e.ID {
+			continue
+		}
+		if err := s.conflict(b.ResourceID, e.ID, e.StartTime, e.EndTime); err != nil {
+			return nil, err
+		}
+		s.bookings[i].StartTime, s.bookings[i].EndTime = e.StartTime, e.EndTime
+	}
+	e.CreatedAt, e.CalendarID = old.CreatedAt, old.CalendarID
+	e.Status, e.StatusReason = old.Status, old.StatusReason
+	s.events[e.ID] = *e
+	s.record(structures.EventUpdated, e.ID, e)
+	s.audit(ctx, structures.HistoryUpdate, &old, e)
+	return e, nil
+}
+
+func (s *memStore) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[id]
+	if !ok {
+		return structures.ErrEventNotFound
+	}
+	deleted := e
+	now := time.Now()
+	deleted.DeletedAt = &now
+	delete(s.events, id)
+	s.trash[id] = deleted
+	s.bookings = slices.DeleteFunc(s.bookings, func(b structures.Booking) bool { return b.EventID == id })
+	s.record(structures.EventDeleted, id, deleted)
+	s.audit(ctx, structures.HistoryDelete, &e, &deleted)
+	return nil
+}
+
+func (s *memStore) TransitionEvent(ctx context.Context, c structures.StatusChange) (*structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before, ok := s.events[c.EventID]
+	if !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	if before.Status != c.From {
+		return nil, structures.ErrStatusChanged
+	}
+	e := before
+	e.Status, e.StatusReason = c.To, c.Reason
+	s.events[e.ID] = e
+	if c.To == structures.StatusCancelled {
+		s.bookings = slices.DeleteFunc(s.bookings, func(b structures.Booking) bool { return b.EventID == e.ID })
+	}
+	s.record(structures.EventUpdated, e.ID, e)
+	s.audit(ctx, structures.HistoryUpdate, &before, &e)
+	return &e, nil
+}
+
+func (s *memStore) RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted, ok := s.trash[id]
+	if !ok {
+		if _, ok := s.events[id]; ok {
+			return nil, structures.ErrEventNotDeleted
+		}
+		return nil, structures.ErrEventNotFound
+	}
+	e := deleted
+	e.DeletedAt = nil
+	delete(s.trash, id)
+	s.events[id] = e
+	s.record(structures.EventCreated, id, e)
+	s.audit(ctx, structures.HistoryRestore, &deleted, &e)
+	return &e, nil
+}
+
+func (s *memStore) ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := []structures.EventHistoryEntry{}
+	for _, h := range s.history {
+		if h.entry.EventID == id {
+			entries = append(entries, h.entry)
+		}
+	}
+	if _, ok := s.events[id]; !ok && len(entries) == 0 {
+		return nil, structures.ErrEventNotFound
+	}
+	return entries, nil
+}
+
+func (s *memStore) GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*structures.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found *memHistory
+	for i, h := range s.history {
+		if h.entry.EventID == id && !h.entry.ChangedAt.After(at) {
+			found = &s.history[i]
+		}
+	}
+	if found == nil || found.entry.Operation == structures.HistoryDelete || found.entry.Operation == structures.HistoryPurge {
+		return nil, nil
+	}
+	e := found.snapshot
+	return &e, nil
+}
+
+func (s *memStore) AddAttendee(ctx context.Context, a *structures.Attendee) (*structures.Attendee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[a.EventID]; !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	for _, existing := range s.attendees[a.EventID] {
+		if existing.Email == a.Email {
+			return nil, structures.ErrAttendeeExists
+		}
+	}
+	a.CreatedAt, a.UpdatedAt = time.Now(), time.Now()
+	s.attendees[a.EventID] = append(s.attendees[a.EventID], *a)
+	return a, nil
+}
+
+func (s *memStore) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[eventID]; !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	return append([]structures.Attendee{}, s.attendees[eventID]...), nil
+}
+
+func (s *memStore) ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[uuid.UUID][]structures.Attendee)
+	for _, id := range eventIDs {
+		out[id] = s.attendees[id]
+	}
+	return out, nil
+}
+
+func (s *memStore) RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.attendees[eventID]
+	i := slices.IndexFunc(list, func(a structures.Attendee) bool { return a.Email == email })
+	if i < 0 {
+		return structures.ErrAttendeeNotFound
+	}
+	s.attendees[eventID] = slices.Delete(list, i, i+1)
+	return nil
+}
+
+func (s *memStore) UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, a := range s.attendees[eventID] {
+		if a.Email == email {
+			a.RSVPStatus = status
+			s.attendees[eventID][i] = a
+			return &a, nil
+		}
+	}
+	return nil, structures.ErrAttendeeNotFound
+}
+
+func (s *memStore) CreateResource(ctx context.Context, r *structures.Resource) (*structures.Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.resources {
+		if existing.Name == r.Name {
+			return nil, structures.ErrResourceExists
+		}
+	}
+	s.resources[r.ID] = *r
+	return r, nil
+}
+
+func (s *memStore) ListResources(ctx context.Context) ([]structures.Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]structures.Resource, 0, len(s.resources))
+	for _, r := range s.resources {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *memStore) GetResource(ctx context.Context, id uuid.UUID) (*structures.Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.resources[id]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// conflict reports the other events holding resourceID during [start,
+// end); the caller holds mu.
+func (s *memStore) conflict(resourceID, eventID uuid.UUID, start, end time.Time) error {
+	var ids []uuid.UUID
+	for _, b := range s.bookings {
+		if b.ResourceID == resourceID && b.EventID != eventID && b.StartTime.Before(end) && b.EndTime.After(start) {
+			ids = append(ids, b.EventID)
+		}
+	}
+	if len(ids) > 0 {
+		return &structures.BookingConflictError{ResourceID: resourceID, EventIDs: ids}
+	}
+	return nil
+}
+
+func (s *memStore) BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[eventID]
+	if !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	if _, ok := s.resources[resourceID]; !ok {
+		return nil, structures.ErrResourceNotFound
+	}
+	if err := s.conflict(resourceID, eventID, e.StartTime, e.EndTime); err != nil {
+		return nil, err
+	}
+	b := structures.Booking{EventID: eventID, ResourceID: resourceID, StartTime: e.StartTime, EndTime: e.EndTime, CreatedAt: time.Now()}
+	s.bookings = append(s.bookings, b)
+	return &b, nil
+}
+
+func (s *memStore) ListEventResources(ctx context.Context, eventID uuid.UUID) ([]structures.Resource, error) {
+	m, err := s.ListResourcesForEvents(ctx, []uuid.UUID{eventID})
+	return append([]structures.Resource{}, m[eventID]...), err
+}
+
+func (s *memStore) ListResourcesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[uuid.UUID][]structures.Resource)
+	for _, b := range s.bookings {
+		if slices.Contains(eventIDs, b.EventID) {
+			out[b.EventID] = append(out[b.EventID], s.resources[b.ResourceID])
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) ReleaseResource(ctx context.Context, eventID, resourceID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.bookings)
+	s.bookings = slices.DeleteFunc(s.bookings, func(b structures.Booking) bool {
+		return b.EventID == eventID && b.ResourceID == resourceID
+	})
+	if len(s.bookings) == n {
+		return structures.ErrBookingNotFound
+	}
+	return nil
+}
+
+func (s *memStore) BusyIntervals(ctx context.Context, q structures.AvailabilityQuery) ([]structures.Interval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var busy []structures.Interval
+	for _, b := range s.bookings {
+		if slices.Contains(q.ResourceIDs, b.ResourceID) {
+			busy = append(busy, structures.Interval{Start: b.StartTime, End: b.EndTime})
+		}
+	}
+	return busy, nil
+}
+
+func (s *memStore) CreateReminder(ctx context.Context, r *structures.Reminder) (*structures.Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.events[r.EventID]
+	if !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	r.RemindAt = e.StartTime.Add(-time.Duration(r.MinutesBefore) * time.Minute)
+	r.Status = structures.ReminderPending
+	s.reminders = append(s.reminders, *r)
+	return r, nil
+}
+
+func (s *memStore) ListReminders(ctx context.Context, eventID uuid.UUID) ([]structures.Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []structures.Reminder{}
+	for _, r := range s.reminders {
+		if r.EventID == eventID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) DeleteReminder(ctx context.Context, eventID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.reminders)
+	s.reminders = slices.DeleteFunc(s.reminders, func(r structures.Reminder) bool {
+		return r.EventID == eventID && r.ID == id
+	})
+	if len(s.reminders) == n {
+		return structures.ErrReminderNotFound
+	}
+	return nil
+}
+
+func (s *memStore) CreateWebhook(ctx context.Context, w *structures.Webhook) (*structures.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *w
+	stored.Secret = "" // like the Postgres store, only the create response has it
+	s.webhooks = append(s.webhooks, stored)
+	return w, nil
+}
+
+func (s *memStore) ListWebhooks(ctx context.Context) ([]structures.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actor := structures.ActorFromContext(ctx)
+	webhooks := []structures.Webhook{}
+	for _, w := range s.webhooks {
+		if w.Owner == actor {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *memStore) GetWebhook(ctx context.Context, id uuid.UUID) (*structures.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, w := range s.webhooks {
+		if w.ID == id && w.Owner == structures.ActorFromContext(ctx) {
+			return &w, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memStore) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.webhooks)
+	s.webhooks = slices.DeleteFunc(s.webhooks, func(w structures.Webhook) bool {
+		return w.ID == id && w.Owner == structures.ActorFromContext(ctx)
+	})
+	if len(s.webhooks) == n {
+		return structures.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *memStore) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit int) ([]structures.WebhookDelivery, error) {
+	if w, err := s.GetWebhook(ctx, webhookID); err != nil || w == nil {
+		if err == nil {
+			err = structures.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return []structures.WebhookDelivery{}, nil
+}
+
+func (s *memStore) OutboxAfter(ctx context.Context, afterID int64, limit int) ([]structures.OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if afterID >= int64(len(s.outbox)) {
+		return nil, nil
+	}
+	end := min(afterID+int64(limit), int64(len(s.outbox)))
+	return append([]structures.OutboxMessage{}, s.outbox[afterID:end]...), nil
+}
+
+func (s *memStore) LatestOutboxID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.outbox)), nil
+}
+
+// withPermission fills in the permission of the actor in ctx; the caller
+// holds mu.
+func (s *memStore) withPermission(ctx context.Context, c structures.Calendar) structures.Calendar {
+	actor := structures.ActorFromContext(ctx)
+	c.Permission = ""
+	if c.Owner == actor {
+		c.Permission = structures.PermissionManage
+		return c
+	}
+	for _, sh := range s.shares[c.ID] {
+		if (sh.Actor == actor || sh.Actor == structures.Everyone) && !structures.PermissionAllows(c.Permission, sh.Permission) {
+			c.Permission = sh.Permission
+		}
+	}
+	return c
+}
+
+func (s *memStore) CreateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calendars[c.ID] = *c
+	created := s.withPermission(ctx, *c)
+	return &created, nil
+}
+
+func (s *memStore) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]structures.Calendar, 0)
+	for _, c := range s.calendars {
+		if c = s.withPermission(ctx, c); c.Permission != "" {
+			out = append(out, c)
+		}
+	}
+	slices.SortFunc(out, func(a, b structures.Calendar) int { return strings.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+func (s *memStore) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.calendars[id]
+	if !ok {
+		return nil, nil
+	}
+	c = s.withPermission(ctx, c)
+	return &c, nil
+}
+
+func (s *memStore) UpdateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.calendars[c.ID]
+	if !ok {
+		return nil, structures.ErrCalendarNotFound
+	}
+	existing.Name, existing.Color, existing.TimeZone = c.Name, c.Color, c.TimeZone
+	s.calendars[c.ID] = existing
+	updated := s.withPermission(ctx, existing)
+	return &updated, nil
+}
+
+func (s *memStore) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.calendars[id]; !ok {
+		return structures.ErrCalendarNotFound
+	}
+	for _, e := range s.events {
+		if e.CalendarID == id {
+			return structures.ErrCalendarNotEmpty
+		}
+	}
+	for eventID, e := range s.trash {
+		if e.CalendarID == id {
+			delete(s.trash, eventID)
+			s.audit(ctx, structures.HistoryPurge, &e, nil)
+		}
+	}
+	delete(s.calendars, id)
+	delete(s.shares, id)
+	return nil
+}
+
+func (s *memStore) ShareCalendar(ctx context.Context, sh *structures.CalendarShare) (*structures.CalendarShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shares[sh.CalendarID] == nil {
+		s.shares[sh.CalendarID] = make(map[string]structures.CalendarShare)
+	}
+	if existing, ok := s.shares[sh.CalendarID][sh.Actor]; ok {
+		sh.CreatedAt = existing.CreatedAt
+	} else {
+		sh.CreatedAt = time.Now()
+	}
+	s.shares[sh.CalendarID][sh.Actor] = *sh
+	return sh, nil
+}
+
+func (s *memStore) ListCalendarShares(ctx context.Context, calendarID uuid.UUID) ([]structures.CalendarShare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]structures.CalendarShare, 0)
+	for _, sh := range s.shares[calendarID] {
+		out = append(out, sh)
+	}
+	slices.SortFunc(out, func(a, b structures.CalendarShare) int { return strings.Compare(a.Actor, b.Actor) })
+	return out, nil
+}
+
+func (s *memStore) UnshareCalendar(ctx context.Context, calendarID uuid.UUID, actor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.shares[calendarID][actor]; !ok {
+		return structures.ErrShareNotFound
+	}
+	delete(s.shares[calendarID], actor)
+	return nil
+}
+
+func (s *memStore) CreateAttachment(ctx context.Context, a *structures.Attachment) (*structures.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[a.EventID]; !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	s.attachments = append(s.attachments, *a)
+	return a, nil
+}
+
+func (s *memStore) ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[eventID]; !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	out := []structures.Attachment{}
+	for _, a := range s.attachments {
+		if a.EventID == eventID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[eventID]; !ok {
+		return nil, nil
+	}
+	for _, a := range s.attachments {
+		if a.ID == id && a.EventID == eventID {
+			return &a, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memStore) DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.attachments, func(a structures.Attachment) bool { return a.ID == id && a.EventID == eventID })
+	if _, ok := s.events[eventID]; !ok || i < 0 {
+		return nil, structures.ErrAttachmentNotFound
+	}
+	a := s.attachments[i]
+	s.attachments = slices.Delete(s.attachments, i, i+1)
+	return &a, nil
+}
+
+func (s *memStore) CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[c.EventID]; !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	if c.ParentID != nil {
+		i := s.commentIndex(c.EventID, *c.ParentID)
+		if i < 0 || s.comments[i].Deleted {
+			return nil, structures.ErrParentCommentNotFound
+		}
+	}
+	s.comments = append(s.comments, *c)
+	return c, nil
+}
+
+func (s *memStore) ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[q.EventID]; !ok {
+		return nil, structures.ErrEventNotFound
+	}
+	if q.ParentID != nil && s.commentIndex(q.EventID, *q.ParentID) < 0 {
+		return nil, structures.ErrCommentNotFound
+	}
+	var thread []structures.Comment
+	for _, c := range s.comments {
+		if c.EventID != q.EventID || (c.ParentID == nil) != (q.ParentID == nil) ||
+			(q.ParentID != nil && *c.ParentID != *q.ParentID) {
+			continue
+		}
+		if q.After != nil && compareComments(structures.CommentCursorOf(c), *q.After) <= 0 {
+			continue
+		}
+		thread = append(thread, s.withReplyCount(c))
+	}
+	slices.SortFunc(thread, func(a, b structures.Comment) int {
+		return compareComments(structures.CommentCursorOf(a), structures.CommentCursorOf(b))
+	})
+	page := &structures.CommentPage{Comments: []structures.Comment{}}
+	if len(thread) > q.Limit {
+		page.NextCursor = structures.CommentCursorOf(thread[q.Limit-1]).Encode()
+		thread = thread[:q.Limit]
+	}
+	page.Comments = append(page.Comments, thread...)
+	return page, nil
+}
+
+func (s *memStore) GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[eventID]; !ok {
+		return nil, nil
+	}
+	i := s.commentIndex(eventID, id)
+	if i < 0 {
+		return nil, nil
+	}
+	c := s.withReplyCount(s.comments[i])
+	return &c, nil
+}
+
+func (s *memStore) UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string, editedAt time.Time) (*structures.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.commentIndex(eventID, id)
+	if _, ok := s.events[eventID]; !ok || i < 0 || s.comments[i].Deleted {
+		return nil, structures.ErrCommentNotFound
+	}
+	s.revisions = append(s.revisions, structures.CommentRevision{
+		ID: int64(len(s.revisions) + 1), CommentID: id, Body: s.comments[i].Body, ReplacedAt: editedAt,
+	})
+	s.comments[i].Body, s.comments[i].EditedAt = body, &editedAt
+	c := s.withReplyCount(s.comments[i])
+	return &c, nil
+}
+
+func (s *memStore) ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[eventID]; !ok || s.commentIndex(eventID, id) < 0 {
+		return nil, structures.ErrCommentNotFound
+	}
+	out := []structures.CommentRevision{}
+	for _, r := range s.revisions {
+		if r.CommentID == id {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) DeleteComment(ctx context.Context, eventID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.commentIndex(eventID, id)
+	if _, ok := s.events[eventID]; !ok || i < 0 || s.comments[i].Deleted {
+		return structures.ErrCommentNotFound
+	}
+	s.revisions = slices.DeleteFunc(s.revisions, func(r structures.CommentRevision) bool { return r.CommentID == id })
+	if s.withReplyCount(s.comments[i]).ReplyCount > 0 {
+		s.comments[i].Body, s.comments[i].EditedAt, s.comments[i].Deleted = "", nil, true
+		return nil
+	}
+	// Remove the comment, then the tombstones it leaves without replies.
+	for {
+		parentID := s.comments[i].ParentID
+		s.comments = slices.Delete(s.comments, i, i+1)
+		if parentID == nil {
+			return nil
+		}
+		i = s.commentIndex(eventID, *parentID)
+		if i < 0 || !s.comments[i].Deleted || s.withReplyCount(s.comments[i]).ReplyCount > 0 {
+			return nil
+		}
+	}
+}
+
+// compareComments orders comments as the cursor does: by creation time,
+// then by ID as Postgres compares UUIDs.
+func compareComments(a, b structures.CommentCursor) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID.String(), b.ID.String())
+}
+
+func (s *memStore) commentIndex(eventID, id uuid.UUID) int {
+	return slices.IndexFunc(s.comments, func(c structures.Comment) bool { return c.ID == id && c.EventID == eventID })
+}
+
+func (s *memStore) withReplyCount(c structures.Comment) structures.Comment {
+	c.ReplyCount = 0
+	for _, r := range s.comments {
+		if r.ParentID != nil && *r.ParentID == c.ID {
+			c.ReplyCount++
+		}
+	}
+	return c
+}
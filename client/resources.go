@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) ListResources(ctx context.Context) ([]structures.Resource, error) {
+	var resources []structures.Resource
+	if _, err := c.do(ctx, http.MethodGet, "/resources", nil, nil, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (c *Client) CreateResource(ctx context.Context, req structures.CreateResourceRequest) (*structures.Resource, error) {
+	var r structures.Resource
+	if _, err := c.do(ctx, http.MethodPost, "/resources", nil, req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (c *Client) GetResourceByID(ctx context.Context, id uuid.UUID) (*structures.Resource, error) {
+	var r structures.Resource
+	if _, err := c.do(ctx, http.MethodGet, "/resources/"+id.String(), nil, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func eventResourcesPath(eventID uuid.UUID) string {
+	return "/events/" + eventID.String() + "/resources"
+}
+
+func (c *Client) ListEventResources(ctx context.Context, eventID uuid.UUID) ([]structures.Resource, error) {
+	var resources []structures.Resource
+	if _, err := c.do(ctx, http.MethodGet, eventResourcesPath(eventID), nil, nil, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// BookResource books a resource for the event's time range. When it is
+// taken, use BookingConflict on the error to see by which events.
+func (c *Client) BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error) {
+	var b structures.Booking
+	req := structures.BookResourceRequest{ResourceID: resourceID}
+	if _, err := c.do(ctx, http.MethodPost, eventResourcesPath(eventID), nil, req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) ReleaseResource(ctx context.Context, eventID, resourceID uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, eventResourcesPath(eventID)+"/"+resourceID.String(), nil, nil, nil)
+	return err
+}
+
+// BookingConflict returns the body of a 409 caused by overlapping
+// bookings, as returned by BookResource and UpdateEvent.
+func BookingConflict(err error) (*structures.BookingConflictResponse, bool) {
+	var e *Error
+	if !errors.As(err, &e) || e.StatusCode != http.StatusConflict {
+		return nil, false
+	}
+	var conflict structures.BookingConflictResponse
+	if json.Unmarshal(e.Body, &conflict) != nil || conflict.ResourceID == uuid.Nil {
+		return nil, false
+	}
+	return &conflict, true
+}
+
+// GetAvailability returns the busy intervals and free slots shared by the
+// resources and attendees in q.
+func (c *Client) GetAvailability(ctx context.Context, q structures.AvailabilityQuery) (*structures.Availability, error) {
+	values := url.Values{}
+	if len(q.ResourceIDs) > 0 {
+		ids := make([]string, len(q.ResourceIDs))
+		for i, id := range q.ResourceIDs {
+			ids[i] = id.String()
+		}
+		values.Set("resources", strings.Join(ids, ","))
+	}
+	if len(q.Attendees) > 0 {
+		values.Set("attendees", strings.Join(q.Attendees, ","))
+	}
+	values.Set("from", q.From.Format(time.RFC3339))
+	values.Set("to", q.To.Format(time.RFC3339))
+	values.Set("duration", q.Duration.String())
+
+	var a structures.Availability
+	if _, err := c.do(ctx, http.MethodGet, "/availability", values, nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"events/structures"
+)
+
+// SearchOptions are the optional query parameters of searchEvents.
+type SearchOptions struct {
+	// Language only searches events in this text search configuration.
+	Language string
+	// TZ renders times in this IANA zone instead of each event's own.
+	TZ string
+
+	Limit  int
+	Cursor string
+}
+
+func (o SearchOptions) values(text string) url.Values {
+	q := url.Values{}
+	q.Set("q", text)
+	if o.Language != "" {
+		q.Set("lang", o.Language)
+	}
+	if o.TZ != "" {
+		q.Set("tz", o.TZ)
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	return q
+}
+
+// SearchEvents returns one page of the events matching text, best match
+// first. NextCursor is empty on the last page.
+func (c *Client) SearchEvents(ctx context.Context, text string, opts SearchOptions) (*structures.SearchPage, error) {
+	var page structures.SearchPage
+	resp, err := c.do(ctx, http.MethodGet, "/search", opts.values(text), nil, &page.Results)
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = nextCursor(resp.Header)
+	return &page, nil
+}
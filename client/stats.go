@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"events/structures"
+)
+
+// StatsOptions are the optional query parameters of getEventStats. Zero
+// values use the server defaults: daily buckets in UTC, not grouped.
+type StatsOptions struct {
+	// Interval is structures.StatsDay, StatsWeek or StatsMonth.
+	Interval string
+	// TZ is the IANA zone whose midnights start the buckets.
+	TZ string
+	// GroupBy is structures.StatsByStatus or StatsByTag.
+	GroupBy string
+}
+
+func (o StatsOptions) values(from, to time.Time) url.Values {
+	q := url.Values{}
+	q.Set("from", from.Format(time.RFC3339))
+	q.Set("to", to.Format(time.RFC3339))
+	if o.Interval != "" {
+		q.Set("interval", o.Interval)
+	}
+	if o.TZ != "" {
+		q.Set("tz", o.TZ)
+	}
+	if o.GroupBy != "" {
+		q.Set("group_by", o.GroupBy)
+	}
+	return q
+}
+
+// GetEventStats counts the events starting in [from, to). The server may
+// answer from a cache up to 30 seconds old.
+func (c *Client) GetEventStats(ctx context.Context, from, to time.Time, opts StatsOptions) (*structures.EventStats, error) {
+	var stats structures.EventStats
+	if _, err := c.do(ctx, http.MethodGet, "/events/stats", opts.values(from, to), nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Change is one event.created, event.updated or event.deleted message from
+// the change feed. Data is the event JSON, as it was before deletion for
+// event.deleted.
+type Change struct {
+	ID      int64
+	Type    string
+	EventID uuid.UUID
+	Data    json.RawMessage
+}
+
+// Event decodes Data.
+func (ch Change) Event() (*structures.Event, error) {
+	var e structures.Event
+	if err := json.Unmarshal(ch.Data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// StreamOptions configure StreamEvents.
+type StreamOptions struct {
+	// LastEventID resumes after this change id; nil starts with the next
+	// change.
+	LastEventID *int64
+}
+
+// StreamEvents follows the Server-Sent Events feed until ctx is cancelled.
+// A dropped connection is reopened with Last-Event-ID, so no change is
+// missed or repeated; iteration stops with an error only once reconnecting
+// has failed as many times as the client retries.
+func (c *Client) StreamEvents(ctx context.Context, opts StreamOptions) iter.Seq2[Change, error] {
+	return func(yield func(Change, error) bool) {
+		last := opts.LastEventID
+		delay := c.backoff
+		for failures := 0; ; {
+			header := http.Header{"Accept": {"text/event-stream"}}
+			if last != nil {
+				header.Set("Last-Event-ID", strconv.FormatInt(*last, 10))
+			}
+			resp, err := c.send(ctx, http.MethodGet, c.endpoint("/events/stream", nil), nil, header)
+			if err == nil && resp.StatusCode != http.StatusOK {
+				err = decodeError(resp)
+				resp.Body.Close()
+				// Only a server-side failure is worth reconnecting for.
+				if StatusCode(err) < 500 {
+					yield(Change{}, err)
+					return
+				}
+			}
+			if err == nil {
+				err = readSSE(resp, func(ch Change, retry time.Duration) bool {
+					if retry > 0 {
+						delay = retry
+					}
+					if ch.ID == 0 {
+						return true
+					}
+					failures = 0
+					last = &ch.ID
+					return yield(ch, nil)
+				})
+				resp.Body.Close()
+				if errors.Is(err, errStopped) {
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if failures++; failures > c.retries {
+				if err == nil {
+					err = errors.New("client: change stream closed by the server")
+				}
+				yield(Change{}, err)
+				return
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+var errStopped = errors.New("stopped")
+
+// readSSE parses the stream, calling fn for every message and for every
+// retry field (with a zero Change). It returns errStopped when fn returns
+// false, and nil when the server ends the stream.
+func readSSE(resp *http.Response, fn func(Change, time.Duration) bool) error {
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64<<10), 4<<20)
+
+	var ch Change
+	var data []string
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			if len(data) > 0 {
+				ch.Data = json.RawMessage(strings.Join(data, "\n"))
+				var ref struct {
+					ID uuid.UUID `json:"id"`
+				}
+				_ = json.Unmarshal(ch.Data, &ref)
+				ch.EventID = ref.ID
+				if !fn(ch, 0) {
+					return errStopped
+				}
+			}
+			ch, data = Change{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			ch.ID, _ = strconv.ParseInt(value, 10, 64)
+		case "event":
+			ch.Type = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				if !fn(Change{}, time.Duration(ms)*time.Millisecond) {
+					return errStopped
+				}
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// Subscription is an open eventsWebSocket connection. Close it when done.
+type Subscription struct {
+	conn *websocket.Conn
+	stop func() bool
+}
+
+// WSMessage is a message from the server on a Subscription. Type is
+// "change", "subscribed", "unsubscribed" or "error"; the change fields are
+// set only for "change".
+type WSMessage struct {
+	Type    string                   `json:"type"`
+	ID      int64                    `json:"id,omitempty"`
+	Event   string                   `json:"event,omitempty"`
+	EventID uuid.UUID                `json:"event_id,omitempty"`
+	Data    json.RawMessage          `json:"data,omitempty"`
+	Filter  *structures.ChangeFilter `json:"filter,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+// EventsWebSocket opens a WebSocket subscription. It receives nothing until
+// Subscribe is called. Cancelling ctx closes the connection.
+func (c *Client) EventsWebSocket(ctx context.Context) (*Subscription, error) {
+	// ws:// for http://, wss:// for https://.
+	target := "ws" + strings.TrimPrefix(c.endpoint("/events/ws", nil), "http")
+	header := http.Header{}
+	c.authorize(header)
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: 10 * time.Second}
+	conn, resp, err := dialer.DialContext(ctx, target, header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			return nil, decodeError(resp)
+		}
+		return nil, err
+	}
+	return &Subscription{conn: conn, stop: context.AfterFunc(ctx, func() { conn.Close() })}, nil
+}
+
+// Subscribe replaces the subscription's filter; an empty filter matches
+// every change. The server acknowledges with a "subscribed" message.
+func (s *Subscription) Subscribe(filter structures.ChangeFilter) error {
+	return s.conn.WriteJSON(map[string]any{"action": "subscribe", "filter": filter})
+}
+
+// Unsubscribe clears the filter, so no more changes arrive.
+func (s *Subscription) Unsubscribe() error {
+	return s.conn.WriteJSON(map[string]any{"action": "unsubscribe"})
+}
+
+// Next blocks until the next message arrives. It fails once the
+// connection is closed; a close with status 1013 means the client fell
+// behind and should reconnect.
+func (s *Subscription) Next() (WSMessage, error) {
+	var m WSMessage
+	err := s.conn.ReadJSON(&m)
+	return m, err
+}
+
+func (s *Subscription) Close() error {
+	s.stop()
+	return s.conn.Close()
+}
+
+// GraphQLError is returned when a GraphQL response carries errors. Data
+// that resolved despite them is still decoded.
+type GraphQLError struct {
+	Errors []GraphQLMessage
+}
+
+type GraphQLMessage struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+func (e *GraphQLError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Message
+	}
+	return "graphql: " + strings.Join(msgs, "; ")
+}
+
+// GraphQL runs a query or mutation against /graphql and decodes its data
+// into out.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	req := map[string]any{"query": query}
+	if variables != nil {
+		req["variables"] = variables
+	}
+	var resp struct {
+		Data   json.RawMessage  `json:"data"`
+		Errors []GraphQLMessage `json:"errors"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/graphql", nil, req, &resp); err != nil {
+		return err
+	}
+	if out != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return err
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return &GraphQLError{Errors: resp.Errors}
+	}
+	return nil
+}
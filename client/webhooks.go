@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]structures.Webhook, error) {
+	var hooks []structures.Webhook
+	if _, err := c.do(ctx, http.MethodGet, "/webhooks", nil, nil, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// CreateWebhook subscribes a URL. The response is the only one that
+// carries the signing secret.
+func (c *Client) CreateWebhook(ctx context.Context, req structures.CreateWebhookRequest) (*structures.Webhook, error) {
+	var hook structures.Webhook
+	if _, err := c.do(ctx, http.MethodPost, "/webhooks", nil, req, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id uuid.UUID) (*structures.Webhook, error) {
+	var hook structures.Webhook
+	if _, err := c.do(ctx, http.MethodGet, "/webhooks/"+id.String(), nil, nil, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, "/webhooks/"+id.String(), nil, nil, nil)
+	return err
+}
+
+// ListWebhookDeliveries returns the newest deliveries of a webhook. status
+// filters by delivery state when not empty; limit 0 uses the server's
+// default.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id uuid.UUID, status string, limit int) ([]structures.WebhookDelivery, error) {
+	q := url.Values{}
+	if status != "" {
+		q.Set("status", status)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var deliveries []structures.WebhookDelivery
+	if _, err := c.do(ctx, http.MethodGet, "/webhooks/"+id.String()+"/deliveries", q, nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
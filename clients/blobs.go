@@ -0,0 +1,332 @@
+package clients
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"events/structures"
+)
+
+// LocalBlobStore keeps each blob as a file under a directory, at the path
+// its key spells out.
+type LocalBlobStore struct {
+	dir string
+}
+
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+func (s *LocalBlobStore) path(key string) (string, error) {
+	p := filepath.FromSlash(key)
+	if !filepath.IsLocal(p) {
+		return "", fmt.Errorf("blob key %q leaves the store", key)
+	}
+	return filepath.Join(s.dir, p), nil
+}
+
+// Put writes a temporary file next to the blob and renames it into place,
+// so readers never see a partial blob.
+func (s *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err == nil && n != size {
+		err = fmt.Errorf("blob %s: read %d bytes, expected %d", key, n, size)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, structures.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+type S3Config struct {
+	// Endpoint is the base URL of the service, such as
+	// https://s3.eu-west-1.amazonaws.com or http://localhost:9000 for
+	// MinIO. Objects are addressed path style: <endpoint>/<bucket>/<key>.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// Timeout bounds each request, uploads included.
+	Timeout time.Duration
+}
+
+// S3BlobStore keeps blobs as objects in a bucket of an S3-compatible
+// service. Requests are signed with AWS Signature Version 4; uploads
+// send UNSIGNED-PAYLOAD rather than hashing the body twice.
+type S3BlobStore struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3BlobStore(cfg S3Config) *S3BlobStore {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 2 * time.Minute
+	}
+	return &S3BlobStore{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}, now: time.Now}
+}
+
+// S3Error is an error response from the object store.
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("s3: status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if size == 0 {
+		r = http.NoBody
+	}
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, r, size, header)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	var s3Err *S3Error
+	if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound {
+		return nil, structures.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	var s3Err *S3Error
+	if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a signed request for the object at key. Responses other than
+// 2xx are returned as *S3Error with the body closed.
+func (s *S3BlobStore) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u, err := url.Parse(strings.TrimRight(s.cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	// RawPath carries the escaping the signature is computed over.
+	base := u.EscapedPath()
+	u.Path += "/" + s.cfg.Bucket + "/" + key
+	u.RawPath = base + "/" + s3Escape(s.cfg.Bucket) + "/" + s3EscapePath(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	payload := emptyPayloadHash
+	if body != nil {
+		payload = unsignedPayload
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	signV4(req, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		s3Err := &S3Error{}
+		_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(s3Err)
+		s3Err.StatusCode = resp.StatusCode
+		return nil, s3Err
+	}
+	return resp, nil
+}
+
+const (
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// emptyPayloadHash is the hex SHA-256 of no bytes.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// signV4 adds X-Amz-Date and an Authorization header to req, signing the
+// host and every header already set on req. X-Amz-Content-Sha256 must be
+// set.
+func signV4(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	signed := []string{"host"}
+	for name := range req.Header {
+		if name != "Authorization" && name != "User-Agent" {
+			signed = append(signed, strings.ToLower(name))
+		}
+	}
+	sort.Strings(signed)
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headerValue := func(name string) string {
+		if name == "host" {
+			return host
+		}
+		return strings.Join(req.Header.Values(name), ",")
+	}
+	sig := v4Signature(secretKey, region, amzDate, req.Method, req.URL.EscapedPath(), req.URL.Query(),
+		headerValue, signed, req.Header.Get("X-Amz-Content-Sha256"))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+v4Scope(amzDate, region)+
+		",SignedHeaders="+strings.Join(signed, ";")+",Signature="+sig)
+}
+
+// v4Signature signs a request described by its parts. signed lists the
+// lower-case header names in order, and header reads their values.
+func v4Signature(secretKey, region, amzDate, method, escapedPath string, query url.Values,
+	header func(string) string, signed []string, payloadHash string) string {
+	var canonical strings.Builder
+	canonical.WriteString(method + "\n" + escapedPath + "\n")
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+	canonical.WriteString(strings.Join(pairs, "&") + "\n")
+	for _, name := range signed {
+		canonical.WriteString(name + ":" + strings.TrimSpace(header(name)) + "\n")
+	}
+	canonical.WriteString("\n" + strings.Join(signed, ";") + "\n" + payloadHash)
+
+	digest := sha256.Sum256([]byte(canonical.String()))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + v4Scope(amzDate, region) + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), amzDate[:8])
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+func v4Scope(amzDate, region string) string {
+	return amzDate[:8] + "/" + region + "/s3/aws4_request"
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape percent-encodes everything but the unreserved characters, as
+// Signature Version 4 requires.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3EscapePath escapes each segment of an object key, keeping the slashes.
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = s3Escape(seg)
+	}
+	return strings.Join(segments, "/")
+}
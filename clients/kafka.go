@@ -0,0 +1,299 @@
+package clients
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"events/structures"
+)
+
+type KafkaConfig struct {
+	Brokers  []string
+	Topic    string
+	ClientID string
+	// Timeout bounds each request when ctx has no earlier deadline.
+	Timeout time.Duration
+}
+
+// KafkaPublisher produces EventMessages to a Kafka topic, keyed by event
+// ID so every change to one event lands on the same partition in order.
+// Requests are serialized over one connection per broker.
+type KafkaPublisher struct {
+	cfg KafkaConfig
+
+	mu          sync.Mutex
+	conns       map[string]net.Conn
+	leaders     []string // leader address per partition
+	correlation int32
+}
+
+func NewKafkaPublisher(cfg KafkaConfig) *KafkaPublisher {
+	if cfg.ClientID == "" {
+		cfg.ClientID = "events"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &KafkaPublisher{cfg: cfg, conns: make(map[string]net.Conn)}
+}
+
+// KafkaError is an error code returned by a broker.
+type KafkaError struct {
+	Code int16
+}
+
+func (e *KafkaError) Error() string {
+	return "kafka: broker returned error code " + strconv.Itoa(int(e.Code))
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, m structures.EventMessage) error {
+	value, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	headers := []KafkaHeader{
+		{Key: "schema-version", Value: []byte(strconv.Itoa(m.SchemaVersion))},
+		{Key: "event-type", Value: []byte(m.Type)},
+		{Key: "content-type", Value: []byte("application/json")},
+	}
+	return p.produce(ctx, []byte(m.EventID.String()), value, headers, m.OccurredAt)
+}
+
+func (p *KafkaPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reset()
+	return nil
+}
+
+// produce sends one record, refreshing metadata and retrying once when the
+// connection or the partition leadership has changed under it.
+func (p *KafkaPublisher) produce(ctx context.Context, key, value []byte, headers []KafkaHeader, ts time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	batch := encodeRecordBatch(key, value, headers, ts)
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if p.leaders == nil {
+			if err = p.refreshMetadata(ctx); err != nil {
+				p.reset()
+				continue
+			}
+		}
+		partition := kafkaPartition(key, len(p.leaders))
+		err = p.produceTo(ctx, p.leaders[partition], int32(partition), batch)
+		if err == nil {
+			return nil
+		}
+		var kerr *KafkaError
+		if errors.As(err, &kerr) && !retriableKafkaError(kerr.Code) {
+			return err
+		}
+		p.reset()
+	}
+	return err
+}
+
+func retriableKafkaError(code int16) bool {
+	switch code {
+	case kafkaUnknownTopicOrPartition, kafkaLeaderNotAvailable, kafkaNotLeaderForPartition:
+		return true
+	}
+	return false
+}
+
+func (p *KafkaPublisher) refreshMetadata(ctx context.Context) error {
+	var req kafkaWriter
+	req.int32(1)
+	req.string(p.cfg.Topic)
+
+	var lastErr error
+	for _, addr := range p.cfg.Brokers {
+		resp, err := p.roundTrip(ctx, addr, kafkaMetadataKey, kafkaMetaVersion, req.Bytes())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return p.parseMetadata(resp)
+	}
+	if lastErr == nil {
+		lastErr = errors.New("kafka: no brokers configured")
+	}
+	return lastErr
+}
+
+func (p *KafkaPublisher) parseMetadata(resp []byte) error {
+	r := &kafkaReader{buf: resp}
+	brokers := make(map[int32]string)
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		id := r.int32()
+		host := r.string()
+		port := r.int32()
+		r.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	r.int32() // controller id
+
+	var leaders []string
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		code := r.int16()
+		name := r.string()
+		r.int8() // is internal
+		count := r.int32()
+		if count < 0 || int64(count)*kafkaMinPartitionSize > int64(len(r.buf)) {
+			return errKafkaShortRead
+		}
+		parts := make([]string, count)
+		for pn := count; pn > 0 && r.err == nil; pn-- {
+			r.int16() // partition error code
+			index := r.int32()
+			leader := r.int32()
+			for rn := r.int32(); rn > 0 && r.err == nil; rn-- { // replicas
+				r.int32()
+			}
+			for in := r.int32(); in > 0 && r.err == nil; in-- { // isr
+				r.int32()
+			}
+			if r.err != nil || name != p.cfg.Topic {
+				continue
+			}
+			if index < 0 || index >= count {
+				return fmt.Errorf("kafka: metadata lists partition %d of %d", index, count)
+			}
+			addr, ok := brokers[leader]
+			if !ok {
+				// No leader (-1) or one missing from the broker list.
+				return &KafkaError{Code: kafkaLeaderNotAvailable}
+			}
+			parts[index] = addr
+		}
+		if name != p.cfg.Topic {
+			continue
+		}
+		if code != kafkaNoError {
+			return &KafkaError{Code: code}
+		}
+		leaders = parts
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if len(leaders) == 0 {
+		return fmt.Errorf("kafka: topic %q has no partitions", p.cfg.Topic)
+	}
+	for _, addr := range leaders {
+		if addr == "" {
+			return &KafkaError{Code: kafkaLeaderNotAvailable}
+		}
+	}
+	p.leaders = leaders
+	return nil
+}
+
+func (p *KafkaPublisher) produceTo(ctx context.Context, addr string, partition int32, batch []byte) error {
+	var req kafkaWriter
+	req.nullString() // transactional id
+	req.int16(-1)    // acks: all in-sync replicas
+	req.int32(int32(p.cfg.Timeout.Milliseconds()))
+	req.int32(1)
+	req.string(p.cfg.Topic)
+	req.int32(1)
+	req.int32(partition)
+	req.bytes(batch)
+
+	resp, err := p.roundTrip(ctx, addr, kafkaProduceKey, kafkaProduceVersion, req.Bytes())
+	if err != nil {
+		return err
+	}
+
+	r := &kafkaReader{buf: resp}
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		r.string() // topic
+		for pn := r.int32(); pn > 0 && r.err == nil; pn-- {
+			r.int32() // partition
+			code := r.int16()
+			r.int64() // base offset
+			r.int64() // log append time
+			if code != kafkaNoError {
+				return &KafkaError{Code: code}
+			}
+		}
+	}
+	return r.err
+}
+
+// roundTrip sends one request to addr and returns the response body after
+// the correlation id.
+func (p *KafkaPublisher) roundTrip(ctx context.Context, addr string, apiKey, version int16, body []byte) ([]byte, error) {
+	conn, err := p.conn(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(p.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	p.correlation++
+	var req kafkaWriter
+	req.int32(0) // size, patched below
+	req.int16(apiKey)
+	req.int16(version)
+	req.int32(p.correlation)
+	req.string(p.cfg.ClientID)
+	req.Write(body)
+	msg := req.Bytes()
+	binary.BigEndian.PutUint32(msg, uint32(len(msg)-4))
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > kafkaMaxResponseSize {
+		return nil, fmt.Errorf("kafka: %d byte response exceeds the %d byte limit", n, kafkaMaxResponseSize)
+	}
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	if len(resp) < 4 || int32(binary.BigEndian.Uint32(resp)) != p.correlation {
+		return nil, errors.New("kafka: response correlation id mismatch")
+	}
+	return resp[4:], nil
+}
+
+func (p *KafkaPublisher) conn(ctx context.Context, addr string) (net.Conn, error) {
+	if c, ok := p.conns[addr]; ok {
+		return c, nil
+	}
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[addr] = c
+	return c, nil
+}
+
+// reset drops every connection and the cached metadata.
+func (p *KafkaPublisher) reset() {
+	for addr, c := range p.conns {
+		c.Close()
+		delete(p.conns, addr)
+	}
+	p.leaders = nil
+}
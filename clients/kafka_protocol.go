@@ -0,0 +1,241 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"time"
+)
+
+// The subset of the Kafka protocol the publisher speaks: Metadata v1 and
+// Produce v3 with v2 record batches. See
+// https://kafka.apache.org/protocol for the wire format.
+const (
+	kafkaProduceKey     int16 = 0
+	kafkaMetadataKey    int16 = 3
+	kafkaProduceVersion int16 = 3
+	kafkaMetaVersion    int16 = 1
+	kafkaRecordMagic    int8  = 2
+)
+
+// Kafka error codes the publisher reacts to.
+const (
+	kafkaNoError                 int16 = 0
+	kafkaUnknownTopicOrPartition int16 = 3
+	kafkaLeaderNotAvailable      int16 = 5
+	kafkaNotLeaderForPartition   int16 = 6
+)
+
+// kafkaMaxResponseSize bounds the responses the publisher reads; Metadata
+// and Produce answers for one topic are far smaller.
+const kafkaMaxResponseSize = 64 << 20
+
+// kafkaMinPartitionSize is the smallest encoding of a partition in a
+// Metadata v1 response: error code, index, leader and two empty arrays.
+const kafkaMinPartitionSize = 2 + 4 + 4 + 4 + 4
+
+var errKafkaShortRead = errors.New("kafka: short read")
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+type kafkaWriter struct {
+	bytes.Buffer
+}
+
+func (w *kafkaWriter) int8(v int8)   { w.WriteByte(byte(v)) }
+func (w *kafkaWriter) int16(v int16) { w.Write(binary.BigEndian.AppendUint16(nil, uint16(v))) }
+func (w *kafkaWriter) int32(v int32) { w.Write(binary.BigEndian.AppendUint32(nil, uint32(v))) }
+func (w *kafkaWriter) int64(v int64) { w.Write(binary.BigEndian.AppendUint64(nil, uint64(v))) }
+
+func (w *kafkaWriter) string(s string) {
+	w.int16(int16(len(s)))
+	w.WriteString(s)
+}
+
+func (w *kafkaWriter) nullString() { w.int16(-1) }
+
+func (w *kafkaWriter) bytes(b []byte) {
+	w.int32(int32(len(b)))
+	w.Write(b)
+}
+
+// varint writes a zigzag varint, as used inside record batches.
+func (w *kafkaWriter) varint(v int64) { w.Write(binary.AppendVarint(nil, v)) }
+
+func (w *kafkaWriter) varBytes(b []byte) {
+	if b == nil {
+		w.varint(-1)
+		return
+	}
+	w.varint(int64(len(b)))
+	w.Write(b)
+}
+
+// kafkaReader decodes big-endian fields; the first error sticks and every
+// later read returns zero values.
+type kafkaReader struct {
+	buf []byte
+	err error
+}
+
+func (r *kafkaReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = errKafkaShortRead
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *kafkaReader) int8() int8 {
+	if b := r.take(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (r *kafkaReader) int16() int16 {
+	if b := r.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) int32() int32 {
+	if b := r.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) int64() int64 {
+	if b := r.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) string() string {
+	n := r.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(r.take(int(n)))
+}
+
+func (r *kafkaReader) bytes() []byte {
+	n := r.int32()
+	if n < 0 {
+		return nil
+	}
+	return r.take(int(n))
+}
+
+func (r *kafkaReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errKafkaShortRead
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *kafkaReader) varBytes() []byte {
+	n := r.varint()
+	if n < 0 {
+		return nil
+	}
+	return r.take(int(n))
+}
+
+// encodeRecordBatch builds a v2 record batch holding a single record.
+func encodeRecordBatch(key, value []byte, headers []KafkaHeader, ts time.Time) []byte {
+	var rec kafkaWriter
+	rec.int8(0)   // attributes
+	rec.varint(0) // timestamp delta
+	rec.varint(0) // offset delta
+	rec.varBytes(key)
+	rec.varBytes(value)
+	rec.varint(int64(len(headers)))
+	for _, h := range headers {
+		rec.varBytes([]byte(h.Key))
+		rec.varBytes(h.Value)
+	}
+
+	// Everything from attributes onwards is covered by the CRC.
+	var body kafkaWriter
+	body.int16(0) // attributes: no compression, create time
+	body.int32(0) // last offset delta
+	body.int64(ts.UnixMilli())
+	body.int64(ts.UnixMilli())
+	body.int64(-1) // producer id
+	body.int16(-1) // producer epoch
+	body.int32(-1) // base sequence
+	body.int32(1)  // record count
+	body.varint(int64(rec.Len()))
+	body.Write(rec.Bytes())
+
+	var batch kafkaWriter
+	batch.int64(0) // base offset, assigned by the broker
+	batch.int32(int32(4 + 1 + 4 + body.Len()))
+	batch.int32(-1) // partition leader epoch
+	batch.int8(kafkaRecordMagic)
+	batch.int32(int32(crc32.Checksum(body.Bytes(), castagnoli)))
+	batch.Write(body.Bytes())
+	return batch.Bytes()
+}
+
+// kafkaPartition picks a partition the way the Java client's default
+// partitioner does for keyed records, so all producers agree on where an
+// event's messages go.
+func kafkaPartition(key []byte, partitions int) int {
+	return int(murmur2(key)&0x7fffffff) % partitions
+}
+
+func murmur2(data []byte) int32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := length &^ 3
+	switch length % 4 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
@@ -0,0 +1,396 @@
+package clients
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// fakeKafkaBroker is a single-node, in-process broker that answers Metadata
+// v1 and Produce v3 and keeps the records it receives.
+type fakeKafkaBroker struct {
+	t          *testing.T
+	ln         net.Listener
+	topic      string
+	partitions int
+
+	mu      sync.Mutex
+	records []fakeRecord
+	// failNext makes the next produce request fail with this error code.
+	failNext int16
+}
+
+type fakeRecord struct {
+	partition int32
+	key       []byte
+	value     []byte
+	headers   map[string]string
+}
+
+func newFakeKafkaBroker(t *testing.T, topic string, partitions int) *fakeKafkaBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	b := &fakeKafkaBroker{t: t, ln: ln, topic: topic, partitions: partitions}
+	go b.serve()
+	t.Cleanup(func() { ln.Close() })
+	return b
+}
+
+func (b *fakeKafkaBroker) addr() string { return b.ln.Addr().String() }
+
+func (b *fakeKafkaBroker) serve() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeKafkaBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		buf := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		r := &kafkaReader{buf: buf}
+		apiKey, version, correlation := r.int16(), r.int16(), r.int32()
+		r.string() // client id
+
+		var resp kafkaWriter
+		resp.int32(correlation)
+		switch {
+		case apiKey == kafkaMetadataKey && version == 1:
+			b.metadata(r, &resp)
+		case apiKey == kafkaProduceKey && version == 3:
+			b.produce(r, &resp)
+		default:
+			b.t.Errorf("unexpected request: api key %d version %d", apiKey, version)
+			return
+		}
+		if r.err != nil {
+			b.t.Errorf("decode request: %v", r.err)
+			return
+		}
+
+		var out kafkaWriter
+		out.bytes(resp.Bytes())
+		if _, err := conn.Write(out.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeKafkaBroker) metadata(r *kafkaReader, resp *kafkaWriter) {
+	for n := r.int32(); n > 0; n-- {
+		r.string()
+	}
+	host, port, _ := net.SplitHostPort(b.addr())
+	portNum, _ := strconv.Atoi(port)
+
+	resp.int32(1) // brokers
+	resp.int32(1)
+	resp.string(host)
+	resp.int32(int32(portNum))
+	resp.nullString()
+	resp.int32(1) // controller id
+	resp.int32(1) // topics
+	resp.int16(kafkaNoError)
+	resp.string(b.topic)
+	resp.int8(0)
+	resp.int32(int32(b.partitions))
+	for i := 0; i < b.partitions; i++ {
+		resp.int16(kafkaNoError)
+		resp.int32(int32(i))
+		resp.int32(1) // leader
+		resp.int32(1) // replicas
+		resp.int32(1)
+		resp.int32(1) // isr
+		resp.int32(1)
+	}
+}
+
+func (b *fakeKafkaBroker) produce(r *kafkaReader, resp *kafkaWriter) {
+	r.string() // transactional id
+	if acks := r.int16(); acks != -1 {
+		b.t.Errorf("expected acks=-1, got %d", acks)
+	}
+	r.int32() // timeout
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	code := b.failNext
+	b.failNext = kafkaNoError
+
+	resp.int32(1)
+	for n := r.int32(); n > 0; n-- {
+		topic := r.string()
+		resp.string(topic)
+		parts := r.int32()
+		resp.int32(parts)
+		for ; parts > 0; parts-- {
+			partition := r.int32()
+			batch := r.bytes()
+			if code == kafkaNoError {
+				b.records = append(b.records, b.decodeBatch(partition, batch)...)
+			}
+			resp.int32(partition)
+			resp.int16(code)
+			resp.int64(int64(len(b.records)))
+			resp.int64(-1)
+		}
+	}
+	resp.int32(0) // throttle time
+}
+
+func (b *fakeKafkaBroker) decodeBatch(partition int32, batch []byte) []fakeRecord {
+	r := &kafkaReader{buf: batch}
+	r.int64() // base offset
+	length := r.int32()
+	if int(length) != len(r.buf) {
+		b.t.Errorf("batch length %d, %d bytes follow", length, len(r.buf))
+	}
+	r.int32() // partition leader epoch
+	if magic := r.int8(); magic != kafkaRecordMagic {
+		b.t.Errorf("unexpected magic %d", magic)
+	}
+	crc := uint32(r.int32())
+	if got := crc32.Checksum(r.buf, castagnoli); got != crc {
+		b.t.Errorf("crc mismatch: header %x, computed %x", crc, got)
+	}
+	r.int16() // attributes
+	r.int32() // last offset delta
+	r.int64() // first timestamp
+	r.int64() // max timestamp
+	r.int64() // producer id
+	r.int16() // producer epoch
+	r.int32() // base sequence
+	var records []fakeRecord
+	for n := r.int32(); n > 0; n-- {
+		r.varint() // length
+		r.int8()   // attributes
+		r.varint() // timestamp delta
+		r.varint() // offset delta
+		rec := fakeRecord{partition: partition, key: r.varBytes(), value: r.varBytes(), headers: map[string]string{}}
+		for h := r.varint(); h > 0; h-- {
+			rec.headers[string(r.varBytes())] = string(r.varBytes())
+		}
+		records = append(records, rec)
+	}
+	if r.err != nil {
+		b.t.Errorf("decode batch: %v", r.err)
+	}
+	return records
+}
+
+func TestKafkaPublisher_ProducesKeyedVersionedRecords(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "calendar-events", 6)
+	pub := NewKafkaPublisher(KafkaConfig{Brokers: []string{broker.addr()}, Topic: "calendar-events"})
+	defer pub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range ids {
+		msg := structures.EventMessage{
+			SchemaVersion: structures.EventSchemaVersion,
+			Type:          structures.EventCreated,
+			EventID:       id,
+			OccurredAt:    time.Now(),
+			Event:         &structures.Event{ID: id, Title: "Standup"},
+		}
+		if err := pub.Publish(ctx, msg); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if len(broker.records) != len(ids) {
+		t.Fatalf("expected %d records, got %d", len(ids), len(broker.records))
+	}
+	for i, rec := range broker.records {
+		if string(rec.key) != ids[i].String() {
+			t.Fatalf("record %d keyed %q, want event id %s", i, rec.key, ids[i])
+		}
+		if want := int32(kafkaPartition(rec.key, 6)); rec.partition != want {
+			t.Fatalf("record %d on partition %d, want %d", i, rec.partition, want)
+		}
+		if rec.headers["schema-version"] != "1" || rec.headers["event-type"] != structures.EventCreated {
+			t.Fatalf("unexpected headers: %v", rec.headers)
+		}
+		var got structures.EventMessage
+		if err := json.Unmarshal(rec.value, &got); err != nil {
+			t.Fatalf("decode value: %v", err)
+		}
+		if got.SchemaVersion != structures.EventSchemaVersion || got.Event == nil || got.Event.Title != "Standup" {
+			t.Fatalf("unexpected value: %+v", got)
+		}
+	}
+}
+
+func TestKafkaPublisher_RetriesAfterLeaderChange(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "events", 1)
+	broker.failNext = kafkaNotLeaderForPartition
+	pub := NewKafkaPublisher(KafkaConfig{Brokers: []string{broker.addr()}, Topic: "events"})
+	defer pub.Close()
+
+	msg := structures.EventMessage{SchemaVersion: 1, Type: structures.EventDeleted, EventID: uuid.New()}
+	if err := pub.Publish(context.Background(), msg); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if len(broker.records) != 1 {
+		t.Fatalf("expected the retry to deliver 1 record, got %d", len(broker.records))
+	}
+}
+
+// metadataPartition is one partition of a Metadata v1 response.
+type metadataPartition struct {
+	index, leader    int32
+	replicas, isr    int32
+	omitReplicaArray bool
+}
+
+// metadataResponse encodes a Metadata v1 response with broker 1 and one
+// topic; a nil partitions slice claims count partitions without any.
+func metadataResponse(topic string, count int32, partitions []metadataPartition) []byte {
+	var w kafkaWriter
+	w.int32(1) // brokers
+	w.int32(1)
+	w.string("127.0.0.1")
+	w.int32(9092)
+	w.nullString()
+	w.int32(1) // controller id
+	w.int32(1) // topics
+	w.int16(kafkaNoError)
+	w.string(topic)
+	w.int8(0)
+	w.int32(count)
+	for _, p := range partitions {
+		w.int16(kafkaNoError)
+		w.int32(p.index)
+		w.int32(p.leader)
+		w.int32(p.replicas)
+		if p.omitReplicaArray {
+			continue
+		}
+		for i := int32(0); i < p.replicas; i++ {
+			w.int32(1)
+		}
+		w.int32(p.isr)
+		for i := int32(0); i < p.isr; i++ {
+			w.int32(1)
+		}
+	}
+	return w.Bytes()
+}
+
+func TestKafkaPublisher_RejectsMalformedMetadata(t *testing.T) {
+	ok := metadataPartition{index: 0, leader: 1, replicas: 1, isr: 1}
+	tests := []struct {
+		name string
+		resp []byte
+		want func(error) bool
+	}{
+		{"negative index", metadataResponse("events", 1, []metadataPartition{{index: -1, leader: 1}}), nil},
+		{"index past the partition count", metadataResponse("events", 2, []metadataPartition{ok, {index: 2, leader: 1}}), nil},
+		{"unknown leader", metadataResponse("events", 1, []metadataPartition{{index: 0, leader: 7}}), isKafkaCode(kafkaLeaderNotAvailable)},
+		{"no leader", metadataResponse("events", 1, []metadataPartition{{index: 0, leader: -1}}), isKafkaCode(kafkaLeaderNotAvailable)},
+		{"partition count past the response", metadataResponse("events", 1<<30, nil), isShortRead},
+		{"replica count past the response", metadataResponse("events", 1, []metadataPartition{{index: 0, leader: 1, replicas: 1 << 30, omitReplicaArray: true}}), isShortRead},
+		{"truncated", metadataResponse("events", 1, []metadataPartition{ok})[:40], isShortRead},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pub := NewKafkaPublisher(KafkaConfig{Topic: "events"})
+			err := pub.parseMetadata(tc.resp)
+			if err == nil || tc.want != nil && !tc.want(err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if pub.leaders != nil {
+				t.Fatalf("expected no leaders, got %v", pub.leaders)
+			}
+		})
+	}
+
+	pub := NewKafkaPublisher(KafkaConfig{Topic: "events"})
+	if err := pub.parseMetadata(metadataResponse("events", 1, []metadataPartition{ok})); err != nil || len(pub.leaders) != 1 {
+		t.Fatalf("parseMetadata of a valid response = %v, leaders %v", err, pub.leaders)
+	}
+}
+
+func isKafkaCode(code int16) func(error) bool {
+	return func(err error) bool {
+		var kerr *KafkaError
+		return errors.As(err, &kerr) && kerr.Code == code
+	}
+}
+
+func isShortRead(err error) bool { return errors.Is(err, errKafkaShortRead) }
+
+func TestKafkaPublisher_RejectsOversizedResponse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var size [4]byte
+		io.ReadFull(conn, size[:])
+		io.CopyN(io.Discard, conn, int64(binary.BigEndian.Uint32(size[:])))
+		conn.Write(binary.BigEndian.AppendUint32(nil, 1<<31))
+		io.Copy(io.Discard, conn)
+	}()
+
+	pub := NewKafkaPublisher(KafkaConfig{Brokers: []string{ln.Addr().String()}, Topic: "events", Timeout: 5 * time.Second})
+	defer pub.Close()
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	if _, err := pub.roundTrip(context.Background(), ln.Addr().String(), kafkaMetadataKey, kafkaMetaVersion, nil); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected the response to be rejected, got %v", err)
+	}
+}
+
+func TestMurmur2_MatchesJavaClient(t *testing.T) {
+	// Values from the Java client's murmur2 tests.
+	tests := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for in, want := range tests {
+		if got := murmur2([]byte(in)); got != want {
+			t.Errorf("murmur2(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PGListener holds a dedicated connection that LISTENs on one channel.
+// database/sql pools connections, so it cannot be used for LISTEN.
+type PGListener struct {
+	dsn     string
+	channel string
+}
+
+func NewPGListener(dsn, channel string) *PGListener {
+	return &PGListener{dsn: dsn, channel: channel}
+}
+
+// Listen calls notify for every notification until ctx is cancelled,
+// reconnecting with backoff when the connection drops. notify is also
+// called after each (re)connect, since notifications sent while
+// disconnected are lost.
+func (l *PGListener) Listen(ctx context.Context, notify func()) {
+	delay := time.Second
+	for ctx.Err() == nil {
+		err := l.listen(ctx, notify, func() { delay = time.Second })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("listener %s: %v; reconnecting in %s", l.channel, err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, time.Minute)
+	}
+}
+
+func (l *PGListener) listen(ctx context.Context, notify func(), connected func()) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return err
+	}
+	connected()
+	notify()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		notify()
+	}
+}
@@ -0,0 +1,116 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"events/structures"
+)
+
+// reminderText renders the subject and body shared by every notifier, with
+// times in the event's own zone.
+func reminderText(d structures.DueReminder) (subject, body string) {
+	e := d.Event.In(nil)
+	when := e.StartTime.Format("Mon 2 Jan 2006 15:04 MST")
+	if e.AllDay {
+		when = e.StartTime.Format("Mon 2 Jan 2006") + " (all day)"
+	}
+	subject = "Reminder: " + e.Title
+	body = fmt.Sprintf("%s starts %s.\n", e.Title, when)
+	if e.Description != "" {
+		body += "\n" + e.Description + "\n"
+	}
+	return subject, body
+}
+
+// LogNotifier writes reminders to the process log.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, d structures.DueReminder) error {
+	subject, _ := reminderText(d)
+	log.Printf("reminder %s for event %s: %s", d.Reminder.ID, d.Event.ID, subject)
+	return nil
+}
+
+// SMTPNotifier emails reminders to the reminder's target address.
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifier sends through the server at addr (host:port). PLAIN auth
+// is used when username is set; net/smtp only allows it over TLS or to
+// localhost.
+func NewSMTPNotifier(addr, from, username, password string) *SMTPNotifier {
+	n := &SMTPNotifier{addr: addr, from: from}
+	if username != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, d structures.DueReminder) error {
+	subject, body := reminderText(d)
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", d.Reminder.Target)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	// net/smtp has no context support; the scheduler's lease bounds how
+	// long a stuck send can hold a reminder.
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{d.Reminder.Target}, msg.Bytes())
+}
+
+// ReminderWebhookNotifier POSTs reminders as JSON to the reminder's target
+// URL. Any non-2xx response is a failure.
+type ReminderWebhookNotifier struct {
+	client *http.Client
+}
+
+func NewReminderWebhookNotifier(timeout time.Duration) *ReminderWebhookNotifier {
+	return &ReminderWebhookNotifier{client: &http.Client{Timeout: timeout}}
+}
+
+type reminderPayload struct {
+	Type     string              `json:"type"`
+	Reminder structures.Reminder `json:"reminder"`
+	Event    structures.Event    `json:"event"`
+}
+
+func (n *ReminderWebhookNotifier) Notify(ctx context.Context, d structures.DueReminder) error {
+	b, err := json.Marshal(reminderPayload{Type: "event.reminder", Reminder: d.Reminder, Event: d.Event.In(nil)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Reminder.Target, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("reminder webhook: %s returned %s", d.Reminder.Target, resp.Status)
+	}
+	return nil
+}
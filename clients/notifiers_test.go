@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func dueReminder(channel, target string) structures.DueReminder {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return structures.DueReminder{
+		Reminder: structures.Reminder{ID: uuid.New(), MinutesBefore: 15, Channel: channel, Target: target},
+		Event:    structures.Event{ID: uuid.New(), Title: "Standup", StartTime: start, EndTime: start.Add(15 * time.Minute), TimeZone: "UTC"},
+	}
+}
+
+// fakeSMTPServer accepts one message and sends it on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	msgs := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost fake SMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line + " ")[0]); cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "MAIL", "RCPT", "RSET", "NOOP":
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotLines()
+				if err != nil {
+					return
+				}
+				msgs <- strings.Join(data, "\n")
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), msgs
+}
+
+func TestSMTPNotifier_SendsMail(t *testing.T) {
+	addr, msgs := fakeSMTPServer(t)
+	n := NewSMTPNotifier(addr, "events@example.com", "", "")
+
+	if err := n.Notify(context.Background(), dueReminder(structures.ReminderEmail, "ana@example.com")); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	select {
+	case msg := <-msgs:
+		r := textproto.NewReader(bufio.NewReader(strings.NewReader(msg + "\n")))
+		h, err := r.ReadMIMEHeader()
+		if err != nil {
+			t.Fatalf("malformed message: %v", err)
+		}
+		if h.Get("To") != "ana@example.com" || h.Get("Subject") != "Reminder: Standup" {
+			t.Fatalf("unexpected headers: %v", h)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server received no message")
+	}
+}
+
+func TestReminderWebhookNotifier(t *testing.T) {
+	var got reminderPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := NewReminderWebhookNotifier(time.Second)
+	d := dueReminder(structures.ReminderWebhook, srv.URL)
+	if err := n.Notify(context.Background(), d); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	if got.Type != "event.reminder" || got.Reminder.ID != d.Reminder.ID || got.Event.Title != "Standup" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if err := n.Notify(context.Background(), dueReminder(structures.ReminderWebhook, failing.URL)); err == nil {
+		t.Fatal("expected an error for a non-2xx response")
+	}
+}
@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"events/structures"
+)
+
+// StdoutSink writes each outbox message as a line of JSON to an io.Writer,
+// os.Stdout by default.
+type StdoutSink struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewStdoutSink() *StdoutSink {
+	return &StdoutSink{out: os.Stdout}
+}
+
+func (s *StdoutSink) Publish(ctx context.Context, m structures.OutboxMessage) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.out.Write(append(b, '\n'))
+	return err
+}
+
+// FileSink appends outbox messages to a JSON lines file.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f}, nil
+}
+
+func (s *FileSink) Publish(ctx context.Context, m structures.OutboxMessage) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.f.Write(append(b, '\n'))
+	return err
+}
+
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
+
+// WebhookSink POSTs each outbox message as JSON to a fixed URL. Any non-2xx
+// response is treated as a failure so the relay retries it.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, m structures.OutboxMessage) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Outbox-Message-Id", strconv.FormatInt(m.ID, 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook sink: %s returned %s", s.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func TestStdoutSink_WritesJSONLine(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &StdoutSink{out: &buf}
+
+	msg := structures.OutboxMessage{ID: 4, EventID: uuid.New(), Type: structures.EventCreated, Payload: json.RawMessage(`{}`)}
+	if err := sink.Publish(context.Background(), msg); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+
+	var got structures.OutboxMessage
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &got); err != nil {
+		t.Fatalf("decode line: %v", err)
+	}
+	if got.ID != 4 || got.Type != structures.EventCreated {
+		t.Fatalf("unexpected message: %+v", got)
+	}
+}
+
+func TestFileSink_AppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	for i := int64(1); i <= 2; i++ {
+		msg := structures.OutboxMessage{ID: i, Type: structures.EventUpdated, Payload: json.RawMessage(`{}`)}
+		if err := sink.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	lines := 0
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		lines++
+	}
+	if lines != 2 {
+		t.Fatalf("expected 2 lines, got %d", lines)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var gotID string
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.Header.Get("X-Outbox-Message-Id")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := NewWebhookSink(srv.URL)
+	msg := structures.OutboxMessage{ID: 9, Type: structures.EventDeleted, Payload: json.RawMessage(`{}`)}
+
+	if err := sink.Publish(context.Background(), msg); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if gotID != "9" {
+		t.Fatalf("unexpected message id header: %q", gotID)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Publish(context.Background(), msg); err == nil {
+		t.Fatalf("expected error for 503 response")
+	}
+}
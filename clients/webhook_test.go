@@ -0,0 +1,59 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookClient_SignsPayload(t *testing.T) {
+	const secret = "0123456789abcdef"
+	now := time.Unix(1765360800, 0)
+
+	var verifyErr error
+	var delivery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = VerifyWebhook(secret, r.Header.Get(WebhookSignatureHeader), body, now, 5*time.Minute)
+		delivery = r.Header.Get(WebhookDeliveryHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewWebhookClient(time.Second)
+	c.now = func() time.Time { return now }
+
+	status, err := c.Post(context.Background(), srv.URL, secret, 42, []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", status)
+	}
+	if verifyErr != nil {
+		t.Fatalf("receiver could not verify signature: %v", verifyErr)
+	}
+	if delivery != "42" {
+		t.Fatalf("unexpected delivery header: %q", delivery)
+	}
+}
+
+func TestVerifyWebhook_RejectsTamperingAndReplays(t *testing.T) {
+	const secret = "0123456789abcdef"
+	now := time.Unix(1765360800, 0)
+	body := []byte(`{"id":1}`)
+	header := SignWebhook(secret, now, body)
+
+	if err := VerifyWebhook(secret, header, []byte(`{"id":2}`), now, time.Minute); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for tampered body, got %v", err)
+	}
+	if err := VerifyWebhook("another-secret-value", header, body, now, time.Minute); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	if err := VerifyWebhook(secret, header, body, now.Add(time.Hour), time.Minute); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature for stale signature, got %v", err)
+	}
+}
@@ -4,13 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"events/clients"
 	"events/controller"
+	"events/docs"
 	"events/providers"
 	"events/services"
+	"events/structures"
 	"events/utils"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -41,23 +48,247 @@ func main() {
 	}
 
 	repo := providers.NewPGEventStore(db)
-	svc := services.NewEventService(repo)
+	webhookStore := providers.NewPGWebhookStore(db)
+	calendarSvc := services.NewCalendarService(providers.NewPGCalendarStore(db))
+	access := services.NewEventAccess(repo, calendarSvc)
+	svc := services.NewEventService(repo, access)
 	ec := controller.NewEventController(svc)
+	attendeeSvc := services.NewAttendeeService(providers.NewPGAttendeeStore(db), access)
+	ac := controller.NewAttendeeController(attendeeSvc)
+	resourceSvc := services.NewResourceService(providers.NewPGResourceStore(db), access)
+	rc := controller.NewResourceController(resourceSvc)
+	avc := controller.NewAvailabilityController(services.NewAvailabilityService(repo, resourceSvc))
+	gqlc := controller.NewGraphQLController(svc, attendeeSvc, resourceSvc)
+	wc := controller.NewWebhookController(services.NewWebhookService(webhookStore))
+	outboxStore := providers.NewPGOutboxStore(db)
+	feed := services.NewChangeFeed(outboxStore, 5*time.Second)
+	sc := controller.NewStreamController(feed, access)
+	hub := services.NewHub(feed, 64)
+	wsc := controller.NewWSController(hub, access)
+	reminderStore := providers.NewPGReminderStore(db)
+	rmc := controller.NewReminderController(services.NewReminderService(reminderStore, access))
+	cc := controller.NewCalendarController(calendarSvc, svc)
+	blobs, err := blobStore()
+	if err != nil {
+		log.Fatalf("blob store: %v", err)
+	}
+	atc := controller.NewAttachmentController(services.NewAttachmentService(providers.NewPGAttachmentStore(db), blobs, access))
+	cmc := controller.NewCommentController(services.NewCommentService(providers.NewPGCommentStore(db), access))
+	src := controller.NewSearchController(svc)
+
+	sink, err := outboxSink()
+	if err != nil {
+		log.Fatalf("outbox sink: %v", err)
+	}
+	relay := services.NewOutboxRelay(outboxStore,
+		services.MultiSink{sink, eventPublisher(), services.NewWebhookDispatcher(webhookStore, access)}, time.Second)
+	deliverer := services.NewWebhookDeliverer(webhookStore, clients.NewWebhookClient(10*time.Second), time.Second)
+	scheduler := services.NewReminderScheduler(reminderStore, reminderNotifiers(), 15*time.Second)
+
+	// Deleted events stay in the trash for EVENT_RETENTION (30 days by
+	// default) before they are purged.
+	retention := 30 * 24 * time.Hour
+	if v := os.Getenv("EVENT_RETENTION"); v != "" {
+		if retention, err = time.ParseDuration(v); err != nil || retention < 0 {
+			log.Fatalf("EVENT_RETENTION must be a non-negative duration such as 720h, got %q", v)
+		}
+	}
+	purger := services.NewEventPurger(repo, retention, time.Hour)
+
+	// Published outbox messages and finished webhook deliveries are kept
+	// for OUTBOX_RETENTION (7 days by default).
+	outboxRetention := 7 * 24 * time.Hour
+	if v := os.Getenv("OUTBOX_RETENTION"); v != "" {
+		if outboxRetention, err = time.ParseDuration(v); err != nil || outboxRetention < 0 {
+			log.Fatalf("OUTBOX_RETENTION must be a non-negative duration such as 168h, got %q", v)
+		}
+	}
+	outboxPurger := services.NewOutboxPurger(webhookStore, outboxStore, outboxRetention, time.Hour)
+
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go relay.Run(runCtx)
+	go deliverer.Run(runCtx)
+	go scheduler.Run(runCtx)
+	go purger.Run(runCtx)
+	go outboxPurger.Run(runCtx)
+	go clients.NewPGListener(dsn, providers.OutboxChannel).Listen(runCtx, feed.Notify)
+	go func() {
+		if err := feed.Run(runCtx); err != nil {
+			log.Printf("change feed: %v", err)
+		}
+	}()
+	go hub.Run(runCtx)
 
 	mux := http.NewServeMux()
 	ec.RegisterRoutes(mux)
+	ac.RegisterRoutes(mux)
+	rc.RegisterRoutes(mux)
+	avc.RegisterRoutes(mux)
+	wc.RegisterRoutes(mux)
+	gqlc.RegisterRoutes(mux)
+	sc.RegisterRoutes(mux)
+	wsc.RegisterRoutes(mux)
+	rmc.RegisterRoutes(mux)
+	cc.RegisterRoutes(mux)
+	atc.RegisterRoutes(mux)
+	cmc.RegisterRoutes(mux)
+	src.RegisterRoutes(mux)
+
+	// Both servers accept the same bearer tokens.
+	auth := utils.NewTokenAuth(strings.Split(os.Getenv("API_TOKENS"), ","))
+	if !auth.Enabled() {
+		log.Printf("API_TOKENS is not set; authentication is disabled")
+	}
+
+	validation, err := utils.ParseValidationMode(os.Getenv("OPENAPI_VALIDATION"))
+	if err != nil {
+		log.Fatalf("OPENAPI_VALIDATION: %v", err)
+	}
+	validator, err := utils.NewSpecValidator(docs.OpenAPI, validation)
+	if err != nil {
+		log.Fatalf("OpenAPI spec: %v", err)
+	}
+
+	// The spec is public; everything else needs a token.
+	root := http.NewServeMux()
+	controller.NewOpenAPIController(docs.OpenAPI).RegisterRoutes(root)
+	root.Handle("/", auth.Middleware(validator.Middleware(mux)))
+
+	grpcAddr := os.Getenv("GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
+	grpcServer := controller.NewGRPCServer(auth, svc, feed, access)
+	grpcLis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatalf("gRPC listen: %v", err)
+	}
+	go func() {
+		log.Printf("gRPC listening on %s", grpcAddr)
+		if err := grpcServer.Serve(grpcLis); err != nil {
+			log.Printf("gRPC Serve: %v", err)
+		}
+	}()
 
 	addr := ":8080"
 	httpServer := &http.Server{
 		Addr:         addr,
-		Handler:      utils.LoggingMiddleware(mux),
+		Handler:      utils.LoggingMiddleware(root),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	go func() {
+		<-runCtx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Shutdown: %v", err)
+		}
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}()
+
 	log.Printf("listening on %s", addr)
 	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("ListenAndServe: %v", err)
 	}
 }
+
+// eventPublisher relays event changes from the outbox to Kafka when
+// KAFKA_BROKERS is set, and nowhere otherwise.
+func eventPublisher() services.Sink {
+	brokers := os.Getenv("KAFKA_BROKERS")
+	if brokers == "" {
+		return services.MultiSink{}
+	}
+	topic := os.Getenv("KAFKA_TOPIC")
+	if topic == "" {
+		topic = "events"
+	}
+	return services.PublisherSink{Publisher: clients.NewKafkaPublisher(clients.KafkaConfig{
+		Brokers:  strings.Split(brokers, ","),
+		Topic:    topic,
+		ClientID: os.Getenv("KAFKA_CLIENT_ID"),
+	})}
+}
+
+// reminderNotifiers sends email reminders through SMTP_ADDR, or to the log
+// when no SMTP server is configured.
+func reminderNotifiers() map[string]services.Notifier {
+	var email services.Notifier = clients.LogNotifier{}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		from := os.Getenv("SMTP_FROM")
+		if from == "" {
+			from = "events@localhost"
+		}
+		email = clients.NewSMTPNotifier(addr, from, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+	}
+	return map[string]services.Notifier{
+		structures.ReminderEmail:   email,
+		structures.ReminderWebhook: clients.NewReminderWebhookNotifier(10 * time.Second),
+		structures.ReminderLog:     clients.LogNotifier{},
+	}
+}
+
+// outboxSink picks where relayed outbox messages go from OUTBOX_SINK
+// (stdout, file, webhook or none).
+func outboxSink() (services.Sink, error) {
+	switch kind := os.Getenv("OUTBOX_SINK"); kind {
+	case "none":
+		return services.MultiSink{}, nil
+	case "", "stdout":
+		return clients.NewStdoutSink(), nil
+	case "file":
+		path := os.Getenv("OUTBOX_FILE")
+		if path == "" {
+			return nil, errors.New("OUTBOX_FILE is required for the file sink")
+		}
+		return clients.NewFileSink(path)
+	case "webhook":
+		url := os.Getenv("OUTBOX_WEBHOOK_URL")
+		if url == "" {
+			return nil, errors.New("OUTBOX_WEBHOOK_URL is required for the webhook sink")
+		}
+		return clients.NewWebhookSink(url), nil
+	default:
+		return nil, errors.New("unknown OUTBOX_SINK " + kind)
+	}
+}
+
+// blobStore picks where attachment content is kept from BLOB_STORE (local
+// or s3).
+func blobStore() (services.BlobStore, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "data/attachments"
+		}
+		return clients.NewLocalBlobStore(dir)
+	case "s3":
+		cfg := clients.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}
+		if cfg.Endpoint == "" || cfg.Bucket == "" {
+			return nil, errors.New("S3_ENDPOINT and S3_BUCKET are required for the s3 blob store")
+		}
+		return clients.NewS3BlobStore(cfg), nil
+	default:
+		return nil, errors.New("unknown BLOB_STORE " + kind)
+	}
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// transferTimeout bounds an upload or download, which moves up to
+// structures.MaxAttachmentSize over the caller's connection.
+const transferTimeout = 2 * time.Minute
+
+type AttachmentController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type attachmentController struct {
+	svc services.AttachmentService
+}
+
+func NewAttachmentController(svc services.AttachmentService) AttachmentController {
+	return &attachmentController{svc: svc}
+}
+
+func (c *attachmentController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /events/{id}/attachments", c.handleUploadAttachment)
+	mux.HandleFunc("GET /events/{id}/attachments", c.handleListAttachments)
+	mux.HandleFunc("GET /events/{id}/attachments/{attachment_id}", c.handleGetAttachment)
+	mux.HandleFunc("GET /events/{id}/attachments/{attachment_id}/content", c.handleDownloadAttachment)
+	mux.HandleFunc("DELETE /events/{id}/attachments/{attachment_id}", c.handleDeleteAttachment)
+}
+
+// handleUploadAttachment streams the "file" part of a multipart body to
+// the service without buffering it in memory. A part without a usable
+// Content-Type is sniffed.
+func (c *attachmentController) handleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	// The server's read timeout is meant for small JSON bodies.
+	_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(transferTimeout))
+	// Leave room for the multipart framing around the file.
+	r.Body = http.MaxBytesReader(w, r.Body, structures.MaxAttachmentSize+64<<10)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "expected a multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+	var part io.Reader
+	var filename, contentType string
+	for part == nil {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			http.Error(w, "a file part is required", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeAttachmentError(w, "Upload attachment", err)
+			return
+		}
+		if p.FormName() != "file" {
+			continue
+		}
+		part = p
+		filename = strings.TrimSpace(p.FileName())
+		contentType = p.Header.Get("Content-Type")
+	}
+	if filename == "" || len(filename) > 255 {
+		http.Error(w, "the file part needs a filename of at most 255 characters", http.StatusBadRequest)
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "" || mediaType == "application/octet-stream" {
+		br := bufio.NewReaderSize(part, 512)
+		head, _ := br.Peek(512)
+		contentType, part = http.DetectContentType(head), br
+	}
+	if !structures.AllowedAttachmentType(contentType) {
+		http.Error(w, "content type "+contentType+" is not accepted; use one of "+strings.Join(structures.AttachmentTypes, ", "),
+			http.StatusUnsupportedMediaType)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), transferTimeout)
+	defer cancel()
+
+	a, err := c.svc.UploadAttachment(ctx, &structures.Attachment{
+		ID:          uuid.New(),
+		EventID:     eventID,
+		Filename:    filename,
+		ContentType: contentType,
+		CreatedAt:   time.Now(),
+	}, part)
+	if err != nil {
+		writeAttachmentError(w, "Upload attachment", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, a)
+}
+
+func (c *attachmentController) handleListAttachments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	attachments, err := c.svc.ListAttachments(ctx, eventID)
+	if err != nil {
+		writeAttachmentError(w, "List attachments", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attachments)
+}
+
+func (c *attachmentController) handleGetAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "attachment_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	a, err := c.svc.GetAttachment(ctx, eventID, id)
+	if err != nil {
+		writeAttachmentError(w, "Get attachment", err)
+		return
+	}
+	if a == nil {
+		http.Error(w, structures.ErrAttachmentNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// handleDownloadAttachment copies the blob to the response as it is read.
+// It is always served as a download, never rendered inline.
+func (c *attachmentController) handleDownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "attachment_id")
+	if !ok {
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(transferTimeout))
+	ctx, cancel := context.WithTimeout(r.Context(), transferTimeout)
+	defer cancel()
+
+	a, body, err := c.svc.OpenAttachment(ctx, eventID, id)
+	if err != nil {
+		writeAttachmentError(w, "Download attachment", err)
+		return
+	}
+	defer body.Close()
+
+	h := w.Header()
+	h.Set("Content-Type", a.ContentType)
+	h.Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("ETag", `"`+a.SHA256+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("Download attachment %s: %v", a.ID, err)
+	}
+}
+
+func (c *attachmentController) handleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "attachment_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.DeleteAttachment(ctx, eventID, id); err != nil {
+		writeAttachmentError(w, "Delete attachment", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAttachmentError(w http.ResponseWriter, op string, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, structures.ErrEventNotFound), errors.Is(err, structures.ErrAttachmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, structures.ErrAttachmentTooLarge), errors.As(err, &tooLarge):
+		http.Error(w, "attachments must be at most "+strconv.Itoa(structures.MaxAttachmentSize>>20)+" MiB", http.StatusRequestEntityTooLarge)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to process attachment request", http.StatusInternalServerError)
+	}
+}
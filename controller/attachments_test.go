@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockAttachmentService struct {
+	attachment *structures.Attachment
+	content    string
+
+	uploadReq     *structures.Attachment
+	uploadContent string
+	uploadErr     error
+}
+
+func (m *mockAttachmentService) UploadAttachment(ctx context.Context, a *structures.Attachment, content io.Reader) (*structures.Attachment, error) {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	m.uploadReq, m.uploadContent = a, string(b)
+	if m.uploadErr != nil {
+		return nil, m.uploadErr
+	}
+	a.Size = int64(len(b))
+	return a, nil
+}
+
+func (m *mockAttachmentService) ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error) {
+	return nil, nil
+}
+
+func (m *mockAttachmentService) GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	return m.attachment, nil
+}
+
+func (m *mockAttachmentService) OpenAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, io.ReadCloser, error) {
+	if m.attachment == nil {
+		return nil, nil, structures.ErrAttachmentNotFound
+	}
+	return m.attachment, io.NopCloser(strings.NewReader(m.content)), nil
+}
+
+func (m *mockAttachmentService) DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) error {
+	return nil
+}
+
+// --- tests ---
+
+func newAttachmentMux(svc *mockAttachmentService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewAttachmentController(svc).RegisterRoutes(mux)
+	return mux
+}
+
+// multipartBody builds a body with a single part named field.
+func multipartBody(t *testing.T, field, filename, contentType, content string) (io.Reader, string) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="`+field+`"; filename="`+filename+`"`)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(part, content)
+	mw.Close()
+	return &body, mw.FormDataContentType()
+}
+
+func TestHandleUploadAttachment(t *testing.T) {
+	svc := &mockAttachmentService{}
+	eventID := uuid.New()
+	body, ct := multipartBody(t, "file", "agenda.pdf", "application/pdf", "%PDF-1.4 agenda")
+
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/attachments", body)
+	req.Header.Set("Content-Type", ct)
+	w := httptest.NewRecorder()
+	newAttachmentMux(svc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	a := svc.uploadReq
+	if a.EventID != eventID || a.Filename != "agenda.pdf" || a.ContentType != "application/pdf" || a.ID == uuid.Nil {
+		t.Errorf("unexpected attachment: %+v", a)
+	}
+	if svc.uploadContent != "%PDF-1.4 agenda" {
+		t.Errorf("unexpected content %q", svc.uploadContent)
+	}
+}
+
+func TestHandleUploadAttachment_SniffsContentType(t *testing.T) {
+	svc := &mockAttachmentService{}
+	body, ct := multipartBody(t, "file", "notes.txt", "application/octet-stream", "plain notes")
+
+	req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/attachments", body)
+	req.Header.Set("Content-Type", ct)
+	w := httptest.NewRecorder()
+	newAttachmentMux(svc).ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if svc.uploadReq.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("expected a sniffed text type, got %q", svc.uploadReq.ContentType)
+	}
+	// The sniffed bytes must still reach the service.
+	if svc.uploadContent != "plain notes" {
+		t.Errorf("unexpected content %q", svc.uploadContent)
+	}
+}
+
+func TestHandleUploadAttachment_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    string
+		filename string
+		ct       string
+		svcErr   error
+		want     int
+	}{
+		{"no file part", "document", "a.pdf", "application/pdf", nil, http.StatusBadRequest},
+		{"no filename", "file", " ", "application/pdf", nil, http.StatusBadRequest},
+		{"unsupported type", "file", "setup.exe", "application/x-msdownload", nil, http.StatusUnsupportedMediaType},
+		{"too large", "file", "a.pdf", "application/pdf", structures.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+		{"missing event", "file", "a.pdf", "application/pdf", structures.ErrEventNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAttachmentService{uploadErr: tt.svcErr}
+			body, ct := multipartBody(t, tt.field, tt.filename, tt.ct, "content")
+			req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/attachments", body)
+			req.Header.Set("Content-Type", ct)
+			w := httptest.NewRecorder()
+			newAttachmentMux(svc).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleUploadAttachment_RequiresMultipart(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/attachments", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	newAttachmentMux(&mockAttachmentService{}).ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestHandleDownloadAttachment(t *testing.T) {
+	a := &structures.Attachment{
+		ID:          uuid.New(),
+		EventID:     uuid.New(),
+		Filename:    "agenda.pdf",
+		ContentType: "application/pdf",
+		Size:        15,
+		SHA256:      "abc123",
+	}
+	svc := &mockAttachmentService{attachment: a, content: "%PDF-1.4 agenda"}
+	w := httptest.NewRecorder()
+	newAttachmentMux(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/events/"+a.EventID.String()+"/attachments/"+a.ID.String()+"/content", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	for header, want := range map[string]string{
+		"Content-Type":           "application/pdf",
+		"Content-Length":         "15",
+		"Content-Disposition":    "attachment; filename=agenda.pdf",
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   `"abc123"`,
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s: expected %q, got %q", header, want, got)
+		}
+	}
+	if w.Body.String() != "%PDF-1.4 agenda" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestHandleDownloadAttachment_NotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	newAttachmentMux(&mockAttachmentService{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/events/"+uuid.NewString()+"/attachments/"+uuid.NewString()+"/content", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
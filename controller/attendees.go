@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"events/services"
+	"events/structures"
+)
+
+type AttendeeController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type attendeeController struct {
+	svc services.AttendeeService
+}
+
+func NewAttendeeController(svc services.AttendeeService) AttendeeController {
+	return &attendeeController{svc: svc}
+}
+
+func (c *attendeeController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /events/{id}/attendees", c.handleAddAttendee)
+	mux.HandleFunc("GET /events/{id}/attendees", c.handleListAttendees)
+	mux.HandleFunc("DELETE /events/{id}/attendees/{email}", c.handleRemoveAttendee)
+	mux.HandleFunc("PUT /events/{id}/attendees/{email}/rsvp", c.handleUpdateRSVP)
+}
+
+func (c *attendeeController) handleAddAttendee(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	var req structures.AddAttendeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	// Validation
+	if _, err := mail.ParseAddress(req.Email); err != nil || req.Email == "" {
+		http.Error(w, "a valid email is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.DisplayName) > 100 {
+		http.Error(w, "display_name must be at most 100 characters", http.StatusBadRequest)
+		return
+	}
+	if req.Role != "" && !structures.ValidRole(req.Role) {
+		http.Error(w, "role must be one of organizer, required, optional", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	a, err := c.svc.AddAttendee(ctx, &structures.Attendee{
+		EventID:     eventID,
+		Email:       req.Email,
+		DisplayName: req.DisplayName,
+		Role:        req.Role,
+	})
+	if err != nil {
+		writeAttendeeError(w, "Add attendee", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, a)
+}
+
+func (c *attendeeController) handleListAttendees(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	attendees, err := c.svc.ListAttendees(ctx, eventID)
+	if err != nil {
+		writeAttendeeError(w, "List attendees", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, attendees)
+}
+
+func (c *attendeeController) handleRemoveAttendee(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.RemoveAttendee(ctx, eventID, r.PathValue("email")); err != nil {
+		writeAttendeeError(w, "Remove attendee", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *attendeeController) handleUpdateRSVP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	var req structures.UpdateRSVPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if !structures.ValidRSVPStatus(req.Status) {
+		http.Error(w, "status must be one of pending, accepted, declined, tentative", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	a, err := c.svc.UpdateRSVP(ctx, eventID, r.PathValue("email"), req.Status)
+	if err != nil {
+		writeAttendeeError(w, "Update RSVP", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func writeAttendeeError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, structures.ErrEventNotFound), errors.Is(err, structures.ErrAttendeeNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, structures.ErrAttendeeExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to process attendee request", http.StatusInternalServerError)
+	}
+}
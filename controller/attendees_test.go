@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockAttendeeService struct {
+	addCalled bool
+	addReq    *structures.Attendee
+	addResp   *structures.Attendee
+	addErr    error
+
+	listResp []structures.Attendee
+	listErr  error
+
+	removeEmail string
+	removeErr   error
+
+	rsvpStatus string
+	rsvpResp   *structures.Attendee
+	rsvpErr    error
+}
+
+func (m *mockAttendeeService) AddAttendee(ctx context.Context, a *structures.Attendee) (*structures.Attendee, error) {
+	m.addCalled = true
+	m.addReq = a
+	return m.addResp, m.addErr
+}
+
+func (m *mockAttendeeService) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error) {
+	return m.listResp, m.listErr
+}
+
+func (m *mockAttendeeService) ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error) {
+	return nil, nil
+}
+
+func (m *mockAttendeeService) RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error {
+	m.removeEmail = email
+	return m.removeErr
+}
+
+func (m *mockAttendeeService) UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error) {
+	m.rsvpStatus = status
+	return m.rsvpResp, m.rsvpErr
+}
+
+// --- tests ---
+
+func TestHandleAddAttendee_Success(t *testing.T) {
+	eventID := uuid.New()
+	mockSvc := &mockAttendeeService{
+		addResp: &structures.Attendee{EventID: eventID, Email: "ana@example.com", Role: structures.RoleRequired},
+	}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.AddAttendeeRequest{Email: "ana@example.com", DisplayName: "Ana"})
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/attendees", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if !mockSvc.addCalled || mockSvc.addReq.EventID != eventID {
+		t.Fatalf("expected AddAttendee to be called for event %v, got %+v", eventID, mockSvc.addReq)
+	}
+}
+
+func TestHandleAddAttendee_InvalidEmail(t *testing.T) {
+	mockSvc := &mockAttendeeService{}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.AddAttendeeRequest{Email: "not-an-email"})
+	req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/attendees", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if mockSvc.addCalled {
+		t.Fatalf("service should not be called on invalid email")
+	}
+}
+
+func TestHandleAddAttendee_Duplicate(t *testing.T) {
+	mockSvc := &mockAttendeeService{addErr: structures.ErrAttendeeExists}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.AddAttendeeRequest{Email: "ana@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/attendees", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestHandleListAttendees_EventNotFound(t *testing.T) {
+	mockSvc := &mockAttendeeService{listErr: structures.ErrEventNotFound}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+uuid.NewString()+"/attendees", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHandleRemoveAttendee_Success(t *testing.T) {
+	mockSvc := &mockAttendeeService{}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+uuid.NewString()+"/attendees/ana@example.com", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if mockSvc.removeEmail != "ana@example.com" {
+		t.Fatalf("service called with wrong email: %q", mockSvc.removeEmail)
+	}
+}
+
+func TestHandleUpdateRSVP_InvalidStatus(t *testing.T) {
+	mockSvc := &mockAttendeeService{}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	body := bytes.NewBufferString(`{"status":"maybe"}`)
+	req := httptest.NewRequest(http.MethodPut, "/events/"+uuid.NewString()+"/attendees/ana@example.com/rsvp", body)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if mockSvc.rsvpStatus != "" {
+		t.Fatalf("service should not be called on invalid status")
+	}
+}
+
+func TestHandleUpdateRSVP_Success(t *testing.T) {
+	mockSvc := &mockAttendeeService{
+		rsvpResp: &structures.Attendee{Email: "ana@example.com", RSVPStatus: structures.RSVPAccepted},
+	}
+	mux := http.NewServeMux()
+	NewAttendeeController(mockSvc).RegisterRoutes(mux)
+
+	body := bytes.NewBufferString(`{"status":"accepted"}`)
+	req := httptest.NewRequest(http.MethodPut, "/events/"+uuid.NewString()+"/attendees/ana@example.com/rsvp", body)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if mockSvc.rsvpStatus != structures.RSVPAccepted {
+		t.Fatalf("service called with wrong status: %q", mockSvc.rsvpStatus)
+	}
+}
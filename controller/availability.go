@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// maxAvailabilityRange bounds how far apart from and to may be, so a single
+// request cannot expand working hours over years of days.
+const maxAvailabilityRange = 90 * 24 * time.Hour
+
+type AvailabilityController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type availabilityController struct {
+	svc services.AvailabilityService
+}
+
+func NewAvailabilityController(svc services.AvailabilityService) AvailabilityController {
+	return &availabilityController{svc: svc}
+}
+
+func (c *availabilityController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /availability", c.handleGetAvailability)
+}
+
+func (c *availabilityController) handleGetAvailability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	params := r.URL.Query()
+
+	var q structures.AvailabilityQuery
+	for _, raw := range splitList(params["resources"]) {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "resources must be a comma-separated list of UUIDs", http.StatusBadRequest)
+			return
+		}
+		q.ResourceIDs = append(q.ResourceIDs, id)
+	}
+	q.Attendees = splitList(params["attendees"])
+	if len(q.ResourceIDs) == 0 && len(q.Attendees) == 0 {
+		http.Error(w, "at least one of resources or attendees is required", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if q.From, err = time.Parse(time.RFC3339, params.Get("from")); err != nil {
+		http.Error(w, "from must be an RFC 3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	if q.To, err = time.Parse(time.RFC3339, params.Get("to")); err != nil {
+		http.Error(w, "to must be an RFC 3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	if !q.From.Before(q.To) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+	if q.To.Sub(q.From) > maxAvailabilityRange {
+		http.Error(w, "from and to must be at most 90 days apart", http.StatusBadRequest)
+		return
+	}
+	if q.Duration, err = time.ParseDuration(params.Get("duration")); err != nil || q.Duration <= 0 {
+		http.Error(w, "duration must be a positive duration such as 30m or 1h", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	a, err := c.svc.GetAvailability(ctx, q)
+	if errors.Is(err, structures.ErrResourceNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Availability error: %v", err)
+		http.Error(w, "failed to compute availability", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+// splitList flattens repeated and comma-separated query values, dropping
+// blanks.
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
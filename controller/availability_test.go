@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockAvailabilityService struct {
+	called bool
+	query  structures.AvailabilityQuery
+	resp   *structures.Availability
+	err    error
+}
+
+func (m *mockAvailabilityService) GetAvailability(ctx context.Context, q structures.AvailabilityQuery) (*structures.Availability, error) {
+	m.called = true
+	m.query = q
+	return m.resp, m.err
+}
+
+func TestHandleGetAvailability_Success(t *testing.T) {
+	roomID := uuid.New()
+	mockSvc := &mockAvailabilityService{resp: &structures.Availability{}}
+	mux := http.NewServeMux()
+	NewAvailabilityController(mockSvc).RegisterRoutes(mux)
+
+	url := "/availability?resources=" + roomID.String() +
+		"&attendees=ana@example.com,bo@example.com&from=2025-12-10T09:00:00Z&to=2025-12-10T17:00:00Z&duration=30m"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(mockSvc.query.ResourceIDs) != 1 || mockSvc.query.ResourceIDs[0] != roomID {
+		t.Fatalf("unexpected resources: %v", mockSvc.query.ResourceIDs)
+	}
+	if len(mockSvc.query.Attendees) != 2 {
+		t.Fatalf("unexpected attendees: %v", mockSvc.query.Attendees)
+	}
+	if mockSvc.query.Duration != 30*time.Minute {
+		t.Fatalf("unexpected duration: %v", mockSvc.query.Duration)
+	}
+}
+
+func TestHandleGetAvailability_Validation(t *testing.T) {
+	cases := map[string]string{
+		"no targets":     "/availability?from=2025-12-10T09:00:00Z&to=2025-12-10T17:00:00Z&duration=30m",
+		"bad resource":   "/availability?resources=nope&from=2025-12-10T09:00:00Z&to=2025-12-10T17:00:00Z&duration=30m",
+		"inverted range": "/availability?attendees=a@b.c&from=2025-12-10T17:00:00Z&to=2025-12-10T09:00:00Z&duration=30m",
+		"range too long": "/availability?attendees=a@b.c&from=2025-01-01T00:00:00Z&to=2025-12-31T00:00:00Z&duration=30m",
+		"bad duration":   "/availability?attendees=a@b.c&from=2025-12-10T09:00:00Z&to=2025-12-10T17:00:00Z&duration=-5m",
+	}
+	for name, url := range cases {
+		mockSvc := &mockAvailabilityService{}
+		mux := http.NewServeMux()
+		NewAvailabilityController(mockSvc).RegisterRoutes(mux)
+
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", name, http.StatusBadRequest, w.Code)
+		}
+		if mockSvc.called {
+			t.Fatalf("%s: service should not be called", name)
+		}
+	}
+}
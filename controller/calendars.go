@@ -0,0 +1,374 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type CalendarController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type calendarController struct {
+	svc    services.CalendarService
+	events services.EventService
+}
+
+// NewCalendarController serves calendars, their sharing and the events in
+// them; events is used for the nested /calendars/{id}/events routes.
+func NewCalendarController(svc services.CalendarService, events services.EventService) CalendarController {
+	return &calendarController{svc: svc, events: events}
+}
+
+func (c *calendarController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /calendars", c.handleCreateCalendar)
+	mux.HandleFunc("GET /calendars", c.handleListCalendars)
+	mux.HandleFunc("GET /calendars/{id}", c.handleGetCalendar)
+	mux.HandleFunc("PUT /calendars/{id}", c.handleUpdateCalendar)
+	mux.HandleFunc("DELETE /calendars/{id}", c.handleDeleteCalendar)
+	mux.HandleFunc("GET /calendars/{id}/events", c.handleListCalendarEvents)
+	mux.HandleFunc("POST /calendars/{id}/events", c.handleCreateCalendarEvent)
+	mux.HandleFunc("GET /calendars/{id}/shares", c.handleListCalendarShares)
+	mux.HandleFunc("PUT /calendars/{id}/shares/{actor}", c.handleShareCalendar)
+	mux.HandleFunc("DELETE /calendars/{id}/shares/{actor}", c.handleUnshareCalendar)
+}
+
+func (c *calendarController) handleCreateCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	cal, ok := calendarFromBody(w, r)
+	if !ok {
+		return
+	}
+	cal.ID = uuid.New()
+	cal.CreatedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	created, err := c.svc.CreateCalendar(ctx, cal)
+	if err != nil {
+		writeCalendarError(w, "Create", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (c *calendarController) handleListCalendars(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	calendars, err := c.svc.ListCalendars(ctx)
+	if err != nil {
+		writeCalendarError(w, "List", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, calendars)
+}
+
+func (c *calendarController) handleGetCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cal, ok := c.calendarFor(ctx, w, id, structures.PermissionRead)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, cal)
+}
+
+func (c *calendarController) handleUpdateCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	cal, ok := calendarFromBody(w, r)
+	if !ok {
+		return
+	}
+	cal.ID = id
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	updated, err := c.svc.UpdateCalendar(ctx, cal)
+	if err != nil {
+		writeCalendarError(w, "Update", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (c *calendarController) handleDeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.DeleteCalendar(ctx, id); err != nil {
+		writeCalendarError(w, "Delete", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListCalendarEvents pages through the events of a calendar with the
+// same filters as GET /events.
+func (c *calendarController) handleListCalendarEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	loc, ok := parseTZ(w, r)
+	if !ok {
+		return
+	}
+	q, _, ok := parseEventQuery(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if _, ok := c.calendarFor(ctx, w, id, structures.PermissionRead); !ok {
+		return
+	}
+	q.CalendarID = &id
+	writeEventList(w, r, c.events, q, true, loc)
+}
+
+// handleCreateCalendarEvent creates an event in the calendar, in the
+// calendar's time zone unless the request names another.
+func (c *calendarController) handleCreateCalendarEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	var req structures.CreateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cal, ok := c.calendarFor(ctx, w, id, structures.PermissionWrite)
+	if !ok {
+		return
+	}
+	if req.TimeZone == "" {
+		req.TimeZone = cal.TimeZone
+	}
+	ev, err := eventFromRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ev.ID = uuid.New()
+	ev.CalendarID = cal.ID
+	ev.CreatedAt = time.Now()
+
+	e, err := c.events.CreateEvent(ctx, ev)
+	if err != nil {
+		writeEventError(w, "Create", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, e.In(nil))
+}
+
+func (c *calendarController) handleListCalendarShares(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	shares, err := c.svc.ListCalendarShares(ctx, id)
+	if err != nil {
+		writeCalendarError(w, "List shares of", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, shares)
+}
+
+// handleShareCalendar grants {actor} a permission, or changes the one it
+// has. The actor "*" stands for everyone.
+func (c *calendarController) handleShareCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	actor, ok := parseShareActor(w, r)
+	if !ok {
+		return
+	}
+	var req structures.ShareCalendarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if !structures.ValidPermission(req.Permission) {
+		http.Error(w, "permission must be one of "+strings.Join(structures.Permissions, ", "), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	share, err := c.svc.ShareCalendar(ctx, &structures.CalendarShare{CalendarID: id, Actor: actor, Permission: req.Permission})
+	if err != nil {
+		writeCalendarError(w, "Share", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, share)
+}
+
+func (c *calendarController) handleUnshareCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+	actor, ok := parseShareActor(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.UnshareCalendar(ctx, id, actor); err != nil {
+		writeCalendarError(w, "Unshare", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// calendarFor loads a calendar and checks that the caller has at least
+// want on it, writing a 404 or 403 response when they do not.
+func (c *calendarController) calendarFor(ctx context.Context, w http.ResponseWriter, id uuid.UUID, want string) (*structures.Calendar, bool) {
+	cal, err := c.svc.GetCalendar(ctx, id)
+	if err != nil {
+		writeCalendarError(w, "Get", err)
+		return nil, false
+	}
+	if cal == nil {
+		writeCalendarError(w, "Get", structures.ErrCalendarNotFound)
+		return nil, false
+	}
+	if !structures.PermissionAllows(cal.Permission, want) {
+		writeCalendarError(w, "Get", structures.ErrForbidden)
+		return nil, false
+	}
+	return cal, true
+}
+
+// calendarFromBody validates a create/replace payload and builds the
+// calendar it describes, writing a 400 response when it is invalid.
+func calendarFromBody(w http.ResponseWriter, r *http.Request) (*structures.Calendar, bool) {
+	var req structures.CalendarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(req.Name) > 100 {
+		http.Error(w, "name must be at most 100 characters", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Color == "" {
+		req.Color = structures.DefaultCalendarColor
+	}
+	if !structures.ValidCalendarColor(req.Color) {
+		http.Error(w, "color must be a hex color such as #3b82f6", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if _, err := structures.LoadTimeZone(req.TimeZone); err != nil {
+		http.Error(w, "time_zone must be an IANA time zone such as Europe/Madrid", http.StatusBadRequest)
+		return nil, false
+	}
+	return &structures.Calendar{Name: req.Name, Color: req.Color, TimeZone: req.TimeZone}, true
+}
+
+func parseShareActor(w http.ResponseWriter, r *http.Request) (string, bool) {
+	actor := r.PathValue("actor")
+	if actor == "" || len(actor) > 100 {
+		http.Error(w, "actor must be 1 to 100 characters", http.StatusBadRequest)
+		return "", false
+	}
+	return actor, true
+}
+
+func writeCalendarError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, structures.ErrCalendarNotFound), errors.Is(err, structures.ErrShareNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, structures.ErrCalendarNotEmpty):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s calendar error: %v", op, err)
+		http.Error(w, "failed to "+strings.ToLower(op)+" calendar", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockCalendarService struct {
+	calendar *structures.Calendar
+
+	createReq *structures.Calendar
+	shareReq  *structures.CalendarShare
+	deleteErr error
+}
+
+func (m *mockCalendarService) CreateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	m.createReq = c
+	return c, nil
+}
+
+func (m *mockCalendarService) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	return nil, nil
+}
+
+func (m *mockCalendarService) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	return m.calendar, nil
+}
+
+func (m *mockCalendarService) UpdateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	return c, nil
+}
+
+func (m *mockCalendarService) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	return m.deleteErr
+}
+
+func (m *mockCalendarService) ShareCalendar(ctx context.Context, s *structures.CalendarShare) (*structures.CalendarShare, error) {
+	m.shareReq = s
+	return s, nil
+}
+
+func (m *mockCalendarService) ListCalendarShares(ctx context.Context, calendarID uuid.UUID) ([]structures.CalendarShare, error) {
+	return nil, nil
+}
+
+func (m *mockCalendarService) UnshareCalendar(ctx context.Context, calendarID uuid.UUID, actor string) error {
+	return nil
+}
+
+// --- tests ---
+
+func newCalendarMux(cals *mockCalendarService, events *mockEventService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewCalendarController(cals, events).RegisterRoutes(mux)
+	return mux
+}
+
+func TestHandleCreateCalendar_Validation(t *testing.T) {
+	cals := &mockCalendarService{}
+	mux := newCalendarMux(cals, &mockEventService{})
+
+	for _, body := range []string{`{}`, `{"name": "Team", "color": "blue"}`, `{"name": "Team", "time_zone": "Mars/Olympus"}`} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/calendars", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/calendars", strings.NewReader(`{"name": "Team"}`)))
+	if w.Code != http.StatusCreated || cals.createReq.Color != structures.DefaultCalendarColor || cals.createReq.TimeZone != "UTC" {
+		t.Fatalf("expected defaults to be filled in, got %d %+v", w.Code, cals.createReq)
+	}
+}
+
+func TestHandleCreateCalendarEvent(t *testing.T) {
+	id := uuid.New()
+	body := `{"title": "Retro", "start_time": "2026-05-01T10:00:00Z", "end_time": "2026-05-01T11:00:00Z"}`
+
+	cals := &mockCalendarService{calendar: &structures.Calendar{ID: id, TimeZone: "Europe/Madrid", Permission: structures.PermissionRead}}
+	events := &mockEventService{createResp: &structures.Event{ID: uuid.New(), TimeZone: "Europe/Madrid"}}
+	mux := newCalendarMux(cals, events)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/calendars/"+id.String()+"/events", strings.NewReader(body)))
+	if w.Code != http.StatusForbidden || events.createCalled {
+		t.Fatalf("expected 403 for a reader, got %d", w.Code)
+	}
+
+	cals.calendar.Permission = structures.PermissionWrite
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/calendars/"+id.String()+"/events", strings.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if events.createReq.CalendarID != id || events.createReq.TimeZone != "Europe/Madrid" {
+		t.Fatalf("expected the event in the calendar and its zone, got %+v", events.createReq)
+	}
+
+	cals.calendar = nil
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/calendars/"+id.String()+"/events", strings.NewReader(body)))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown calendar, got %d", w.Code)
+	}
+}
+
+func TestHandleListCalendarEvents_ScopesQuery(t *testing.T) {
+	id := uuid.New()
+	cals := &mockCalendarService{calendar: &structures.Calendar{ID: id, Permission: structures.PermissionRead}}
+	events := &mockEventService{queryResp: &structures.EventPage{Events: []structures.Event{}}}
+	w := httptest.NewRecorder()
+	newCalendarMux(cals, events).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendars/"+id.String()+"/events?tag=team:core", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if events.queryReq.CalendarID == nil || *events.queryReq.CalendarID != id || len(events.queryReq.Tags) != 1 {
+		t.Fatalf("expected a query scoped to the calendar, got %+v", events.queryReq)
+	}
+}
+
+func TestHandleShareCalendar(t *testing.T) {
+	id := uuid.New()
+	cals := &mockCalendarService{}
+	mux := newCalendarMux(cals, &mockEventService{})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/calendars/"+id.String()+"/shares/token:abc", strings.NewReader(`{"permission": "owner"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown permission, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/calendars/"+id.String()+"/shares/*", strings.NewReader(`{"permission": "read"}`)))
+	if w.Code != http.StatusOK || cals.shareReq.Actor != structures.Everyone || cals.shareReq.CalendarID != id {
+		t.Fatalf("expected a share with everyone, got %d %+v", w.Code, cals.shareReq)
+	}
+}
+
+func TestHandleDeleteCalendar_ErrorMapping(t *testing.T) {
+	for err, want := range map[error]int{
+		structures.ErrCalendarNotFound: http.StatusNotFound,
+		structures.ErrForbidden:        http.StatusForbidden,
+		structures.ErrCalendarNotEmpty: http.StatusConflict,
+	} {
+		w := httptest.NewRecorder()
+		newCalendarMux(&mockCalendarService{deleteErr: err}, &mockEventService{}).
+			ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/calendars/"+uuid.NewString(), nil))
+		if w.Code != want {
+			t.Errorf("%v: expected %d, got %d", err, want, w.Code)
+		}
+	}
+}
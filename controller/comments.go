@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type CommentController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type commentController struct {
+	svc services.CommentService
+}
+
+func NewCommentController(svc services.CommentService) CommentController {
+	return &commentController{svc: svc}
+}
+
+func (c *commentController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /events/{id}/comments", c.handleCreateComment)
+	mux.HandleFunc("GET /events/{id}/comments", c.handleListComments)
+	mux.HandleFunc("GET /events/{id}/comments/{comment_id}", c.handleGetComment)
+	mux.HandleFunc("PUT /events/{id}/comments/{comment_id}", c.handleUpdateComment)
+	mux.HandleFunc("DELETE /events/{id}/comments/{comment_id}", c.handleDeleteComment)
+	mux.HandleFunc("GET /events/{id}/comments/{comment_id}/replies", c.handleListReplies)
+	mux.HandleFunc("GET /events/{id}/comments/{comment_id}/revisions", c.handleListCommentRevisions)
+}
+
+func (c *commentController) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	var req structures.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if err := structures.ValidateCommentBody(req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	comment, err := c.svc.CreateComment(ctx, &structures.Comment{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		ParentID:  req.ParentID,
+		Body:      req.Body,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		writeCommentError(w, "Create comment", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// handleListComments returns one page of the top-level comments of an
+// event, oldest first, with a Link header when another page follows.
+func (c *commentController) handleListComments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	c.writeCommentPage(w, r, eventID, nil)
+}
+
+// handleListReplies pages through the direct replies to a comment the same
+// way.
+func (c *commentController) handleListReplies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "comment_id")
+	if !ok {
+		return
+	}
+	c.writeCommentPage(w, r, eventID, &id)
+}
+
+func (c *commentController) writeCommentPage(w http.ResponseWriter, r *http.Request, eventID uuid.UUID, parentID *uuid.UUID) {
+	q := structures.CommentQuery{EventID: eventID, ParentID: parentID, Limit: structures.DefaultCommentPageSize}
+	values := r.URL.Query()
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > structures.MaxCommentPageSize {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(structures.MaxCommentPageSize), http.StatusBadRequest)
+			return
+		}
+		q.Limit = n
+	}
+	if v := values.Get("cursor"); v != "" {
+		cursor, err := structures.DecodeCommentCursor(v)
+		if err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		q.After = cursor
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := c.svc.ListComments(ctx, q)
+	if err != nil {
+		writeCommentError(w, "List comments", err)
+		return
+	}
+	setNextLink(w, r, page.NextCursor)
+	writeJSON(w, http.StatusOK, page.Comments)
+}
+
+func (c *commentController) handleGetComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "comment_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	comment, err := c.svc.GetComment(ctx, eventID, id)
+	if err != nil {
+		writeCommentError(w, "Get comment", err)
+		return
+	}
+	if comment == nil {
+		http.Error(w, structures.ErrCommentNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, comment)
+}
+
+// handleUpdateComment replaces the body; the previous one is kept in the
+// comment's revisions.
+func (c *commentController) handleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "comment_id")
+	if !ok {
+		return
+	}
+
+	var req structures.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if err := structures.ValidateCommentBody(req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	comment, err := c.svc.UpdateComment(ctx, eventID, id, req.Body)
+	if err != nil {
+		writeCommentError(w, "Update comment", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comment)
+}
+
+func (c *commentController) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "comment_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.DeleteComment(ctx, eventID, id); err != nil {
+		writeCommentError(w, "Delete comment", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *commentController) handleListCommentRevisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "comment_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	revisions, err := c.svc.ListCommentRevisions(ctx, eventID, id)
+	if err != nil {
+		writeCommentError(w, "List comment revisions", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, revisions)
+}
+
+func writeCommentError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, structures.ErrEventNotFound), errors.Is(err, structures.ErrCommentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrParentCommentNotFound):
+		http.Error(w, "parent_id must be a comment of this event that is not deleted", http.StatusBadRequest)
+	case errors.Is(err, structures.ErrNotCommentAuthor), errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to process comment request", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockCommentService struct {
+	page *structures.CommentPage
+	err  error
+
+	createReq *structures.Comment
+	query     structures.CommentQuery
+}
+
+func (m *mockCommentService) CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error) {
+	m.createReq = c
+	return c, m.err
+}
+
+func (m *mockCommentService) ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error) {
+	m.query = q
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.page, nil
+}
+
+func (m *mockCommentService) GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error) {
+	return nil, m.err
+}
+
+func (m *mockCommentService) UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string) (*structures.Comment, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &structures.Comment{ID: id, EventID: eventID, Body: body}, nil
+}
+
+func (m *mockCommentService) ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error) {
+	return nil, m.err
+}
+
+func (m *mockCommentService) DeleteComment(ctx context.Context, eventID, id uuid.UUID) error {
+	return m.err
+}
+
+// --- tests ---
+
+func newCommentMux(svc *mockCommentService) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewCommentController(svc).RegisterRoutes(mux)
+	return mux
+}
+
+func TestHandleCreateComment(t *testing.T) {
+	svc := &mockCommentService{}
+	eventID, parentID := uuid.New(), uuid.New()
+	body := `{"body": "I can bring it", "parent_id": "` + parentID.String() + `"}`
+	w := httptest.NewRecorder()
+	newCommentMux(svc).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/comments", strings.NewReader(body)))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	c := svc.createReq
+	if c.EventID != eventID || c.ParentID == nil || *c.ParentID != parentID || c.Body != "I can bring it" || c.ID == uuid.Nil {
+		t.Errorf("unexpected comment: %+v", c)
+	}
+}
+
+func TestHandleCreateComment_InvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not JSON", `{`},
+		{"blank", `{"body": "   "}`},
+		{"too long", `{"body": "` + strings.Repeat("a", structures.MaxCommentLength+1) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newCommentMux(&mockCommentService{}).ServeHTTP(w,
+				httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/comments", strings.NewReader(tt.body)))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleListReplies_Paging(t *testing.T) {
+	next := structures.CommentCursor{ID: uuid.New()}.Encode()
+	svc := &mockCommentService{page: &structures.CommentPage{Comments: []structures.Comment{}, NextCursor: next}}
+	eventID, parentID := uuid.New(), uuid.New()
+	after := structures.CommentCursor{ID: uuid.New()}.Encode()
+	path := "/events/" + eventID.String() + "/comments/" + parentID.String() + "/replies"
+
+	w := httptest.NewRecorder()
+	newCommentMux(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?limit=5&cursor="+after, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	q := svc.query
+	if q.EventID != eventID || q.ParentID == nil || *q.ParentID != parentID || q.Limit != 5 || q.After == nil {
+		t.Errorf("unexpected query: %+v", q)
+	}
+	if link := w.Header().Get("Link"); !strings.Contains(link, "cursor="+next) || !strings.Contains(link, `rel="next"`) {
+		t.Errorf("unexpected Link header %q", link)
+	}
+}
+
+func TestHandleListComments_InvalidPaging(t *testing.T) {
+	for _, query := range []string{"limit=0", "limit=101", "limit=x", "cursor=bad"} {
+		w := httptest.NewRecorder()
+		newCommentMux(&mockCommentService{}).ServeHTTP(w,
+			httptest.NewRequest(http.MethodGet, "/events/"+uuid.NewString()+"/comments?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestWriteCommentError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{structures.ErrEventNotFound, http.StatusNotFound},
+		{structures.ErrCommentNotFound, http.StatusNotFound},
+		{structures.ErrParentCommentNotFound, http.StatusBadRequest},
+		{structures.ErrNotCommentAuthor, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		path := "/events/" + uuid.NewString() + "/comments/" + uuid.NewString()
+		newCommentMux(&mockCommentService{err: tt.err}).ServeHTTP(w,
+			httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"body": "edit"}`)))
+		if w.Code != tt.want {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.want, w.Code)
+		}
+	}
+}
@@ -3,8 +3,13 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"events/services"
@@ -28,7 +33,12 @@ func NewEventController(svc services.EventService) EventController {
 func (c *eventController) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /events", c.handleCreateEvent)
 	mux.HandleFunc("GET /events", c.handleListEvents)
-	mux.HandleFunc("GET /events/", c.handleGetEventByID)
+	mux.HandleFunc("GET /events/{id}", c.handleGetEventByID)
+	mux.HandleFunc("PUT /events/{id}", c.handleUpdateEvent)
+	mux.HandleFunc("DELETE /events/{id}", c.handleDeleteEvent)
+	mux.HandleFunc("POST /events/{id}", c.handleEventAction)
+	mux.HandleFunc("GET /events/{id}/history", c.handleListEventHistory)
+	mux.HandleFunc("GET /events/stats", c.handleEventStats)
 }
 
 func (c *eventController) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
@@ -46,79 +56,247 @@ func (c *eventController) handleCreateEvent(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Validation
-	if req.Title == "" {
-		http.Error(w, "title is required", http.StatusBadRequest)
-		return
-	}
-	if len(req.Title) > 100 {
-		http.Error(w, "title must be at most 100 characters", http.StatusBadRequest)
-		return
-	}
-	if req.StartTime.IsZero() || req.EndTime.IsZero() {
-		http.Error(w, "start_time and end_time are required", http.StatusBadRequest)
+	ev, err := eventFromRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if !req.StartTime.Before(req.EndTime) {
-		http.Error(w, "start_time must be before end_time", http.StatusBadRequest)
+	ev.ID = uuid.New()
+	ev.CreatedAt = time.Now()
+
+	e, err := c.svc.CreateEvent(ctx, ev)
+	if errors.Is(err, structures.ErrForbidden) || errors.Is(err, structures.ErrCalendarNotFound) {
+		writeEventError(w, "Create", err)
 		return
 	}
-
-	e, err := c.svc.CreateEvent(ctx, &structures.Event{
-		ID:          uuid.New(),
-		Title:       req.Title,
-		Description: req.Description,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
-		CreatedAt:   time.Now(),
-	})
-
 	if err != nil {
 		log.Printf("Create error: %v", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, e)
+	writeJSON(w, http.StatusCreated, e.In(nil))
 }
 
+// handleListEvents returns every event, or one page of them when any filter
+// or paging parameter is given. A page that is not the last
+// has a Link header pointing at the next one.
 func (c *eventController) handleListEvents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	loc, ok := parseTZ(w, r)
+	if !ok {
+		return
+	}
+	q, paged, ok := parseEventQuery(w, r)
+	if !ok {
+		return
+	}
+	writeEventList(w, r, c.svc, q, paged, loc)
+}
+
+// writeEventList answers a listing of events: one page of q, with a Link
+// header when another page follows, or every event when paged is false.
+func writeEventList(w http.ResponseWriter, r *http.Request, svc services.EventService, q structures.EventQuery, paged bool, loc *time.Location) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	events, err := c.svc.ListEvents(ctx)
-	if err != nil {
-		log.Printf("List error: %v", err)
-		http.Error(w, "failed to list events", http.StatusInternalServerError)
-		return
+	var events []structures.Event
+	if paged {
+		page, err := svc.QueryEvents(ctx, q)
+		if err != nil {
+			log.Printf("List error: %v", err)
+			http.Error(w, "failed to list events", http.StatusInternalServerError)
+			return
+		}
+		setNextLink(w, r, page.NextCursor)
+		events = page.Events
+	} else {
+		var err error
+		events, err = svc.ListEvents(ctx)
+		if err != nil {
+			log.Printf("List error: %v", err)
+			http.Error(w, "failed to list events", http.StatusInternalServerError)
+			return
+		}
+	}
+	for i := range events {
+		events[i] = events[i].In(loc)
 	}
 	writeJSON(w, http.StatusOK, events)
 }
 
+// setNextLink points a Link header at the page after cursor: the same
+// request with cursor replaced. It does nothing on the last page.
+func setNextLink(w http.ResponseWriter, r *http.Request, cursor string) {
+	if cursor == "" {
+		return
+	}
+	next := *r.URL
+	values := next.Query()
+	values.Set("cursor", cursor)
+	next.RawQuery = values.Encode()
+	w.Header().Set("Link", "<"+next.RequestURI()+`>; rel="next"`)
+}
+
+// parseEventQuery reads the paging and filter parameters of GET /events.
+// paged is false when none of them is present.
+func parseEventQuery(w http.ResponseWriter, r *http.Request) (q structures.EventQuery, paged bool, ok bool) {
+	values := r.URL.Query()
+	for name := range values {
+		switch name {
+		case "limit", "cursor", "from", "to", "title", "include_deleted", "status", "tag", "near", "radius_km":
+			paged = true
+		default:
+			if strings.HasPrefix(name, "metadata.") {
+				paged = true
+			}
+		}
+	}
+	if !paged {
+		return q, false, true
+	}
+
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > structures.MaxEventPageSize {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(structures.MaxEventPageSize), http.StatusBadRequest)
+			return q, true, false
+		}
+		q.Limit = n
+	}
+	if v := values.Get("cursor"); v != "" {
+		cursor, err := structures.DecodeEventCursor(v)
+		if err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return q, true, false
+		}
+		q.After = cursor
+	}
+	for name, dst := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		if v := values.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, name+" must be an RFC 3339 timestamp", http.StatusBadRequest)
+				return q, true, false
+			}
+			*dst = &t
+		}
+	}
+	if q.From != nil && q.To != nil && !q.To.After(*q.From) {
+		http.Error(w, "to must be after from", http.StatusBadRequest)
+		return q, true, false
+	}
+	q.TitleContains = values.Get("title")
+	if v := values.Get("include_deleted"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "include_deleted must be true or false", http.StatusBadRequest)
+			return q, true, false
+		}
+		q.IncludeDeleted = include
+	}
+	for _, v := range values["status"] {
+		for _, status := range strings.Split(v, ",") {
+			if !structures.ValidEventStatus(status) {
+				http.Error(w, "status must be one of "+strings.Join(structures.EventStatuses, ", "), http.StatusBadRequest)
+				return q, true, false
+			}
+			q.Statuses = append(q.Statuses, status)
+		}
+	}
+	var tags []string
+	for _, v := range values["tag"] {
+		tags = append(tags, strings.Split(v, ",")...)
+	}
+	if tags != nil {
+		var err error
+		if q.Tags, err = structures.NormalizeTags(tags); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return q, true, false
+		}
+	}
+	for name := range values {
+		if path, ok := strings.CutPrefix(name, "metadata."); ok {
+			if q.Metadata == nil {
+				q.Metadata = make(map[string]string)
+			}
+			q.Metadata[path] = values.Get(name)
+		}
+	}
+	if _, err := structures.MetadataContainment(q.Metadata); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return q, true, false
+	}
+	if !parseNear(w, values, &q) {
+		return q, true, false
+	}
+	return q, true, true
+}
+
+// parseNear reads near=lat,lng and radius_km, which only make sense
+// together.
+func parseNear(w http.ResponseWriter, values url.Values, q *structures.EventQuery) bool {
+	near, radius := values.Get("near"), values.Get("radius_km")
+	if near == "" && radius == "" {
+		return true
+	}
+	if near == "" || radius == "" {
+		http.Error(w, "near and radius_km must be given together", http.StatusBadRequest)
+		return false
+	}
+	latStr, lngStr, found := strings.Cut(near, ",")
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	lng, lngErr := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	point := structures.GeoPoint{Lat: lat, Lng: lng}
+	if !found || latErr != nil || lngErr != nil || !point.Valid() {
+		http.Error(w, "near must be lat,lng in decimal degrees", http.StatusBadRequest)
+		return false
+	}
+	km, err := strconv.ParseFloat(radius, 64)
+	if err != nil || !(km > 0 && km <= structures.MaxSearchRadiusKm) {
+		http.Error(w, "radius_km must be greater than 0 and at most "+strconv.Itoa(structures.MaxSearchRadiusKm), http.StatusBadRequest)
+		return false
+	}
+	q.Near, q.RadiusKm = &point, km
+	return true
+}
+
 func (c *eventController) handleGetEventByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	idStr := r.URL.Path[len("/events/"):]
-	if idStr == "" {
-		http.Error(w, "missing event id", http.StatusBadRequest)
+	id, ok := parseEventID(w, r)
+	if !ok {
 		return
 	}
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		http.Error(w, "invalid UUID", http.StatusNotFound)
+	loc, ok := parseTZ(w, r)
+	if !ok {
 		return
 	}
+	var asOf *time.Time
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "as_of must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		asOf = &t
+	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	e, err := c.svc.GetEvent(ctx, id)
+	var e *structures.Event
+	var err error
+	if asOf != nil {
+		e, err = c.svc.GetEventAsOf(ctx, id, *asOf)
+	} else {
+		e, err = c.svc.GetEvent(ctx, id)
+	}
 	if err != nil {
 		log.Printf("Get error: %v", err)
 		http.Error(w, "failed to get event", http.StatusInternalServerError)
@@ -128,7 +306,360 @@ func (c *eventController) handleGetEventByID(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "event not found", http.StatusNotFound)
 		return
 	}
-	writeJSON(w, http.StatusOK, e)
+	writeJSON(w, http.StatusOK, e.In(loc))
+}
+
+func (c *eventController) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	var req structures.CreateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	ev, err := eventFromRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ev.ID = id
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	e, err := c.svc.UpdateEvent(ctx, ev)
+	if err != nil {
+		writeEventError(w, "Update", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, e.In(nil))
+}
+
+func (c *eventController) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.DeleteEvent(ctx, id); err != nil {
+		writeEventError(w, "Delete", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEventAction serves the custom methods POST /events/{id}:<action>.
+// Mux wildcards match whole segments, so the action is split off the id
+// here.
+func (c *eventController) handleEventAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	idStr, action, ok := strings.Cut(r.PathValue("id"), ":")
+	if !ok {
+		http.Error(w, "404 page not found", http.StatusNotFound)
+		return
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid UUID", http.StatusBadRequest)
+		return
+	}
+
+	if action == "restore" {
+		c.restoreEvent(w, r, id)
+		return
+	}
+	if to, ok := statusActions[action]; ok {
+		c.transitionEvent(w, r, id, to)
+		return
+	}
+	http.Error(w, "unknown action "+strconv.Quote(action), http.StatusNotFound)
+}
+
+// statusActions maps the transition endpoints to the status they set.
+var statusActions = map[string]string{
+	"schedule": structures.StatusScheduled,
+	"postpone": structures.StatusPostponed,
+	"cancel":   structures.StatusCancelled,
+	"complete": structures.StatusCompleted,
+}
+
+// transitionEvent changes the event's status. The body, with an optional
+// reason, may be left out.
+func (c *eventController) transitionEvent(w http.ResponseWriter, r *http.Request, id uuid.UUID, to string) {
+	loc, ok := parseTZ(w, r)
+	if !ok {
+		return
+	}
+	var req structures.StatusChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Reason) > structures.MaxStatusReason {
+		http.Error(w, "reason must be at most "+strconv.Itoa(structures.MaxStatusReason)+" characters", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	e, err := c.svc.TransitionEvent(ctx, structures.StatusChange{EventID: id, To: to, Reason: req.Reason})
+	if err != nil {
+		writeEventError(w, "Transition", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, e.In(loc))
+}
+
+func (c *eventController) restoreEvent(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	loc, ok := parseTZ(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	e, err := c.svc.RestoreEvent(ctx, id)
+	if err != nil {
+		writeEventError(w, "Restore", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, e.In(loc))
+}
+
+func (c *eventController) handleListEventHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	entries, err := c.svc.ListEventHistory(ctx, id)
+	if err != nil {
+		writeEventError(w, "List history of", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// handleEventStats counts the events starting in [from, to) per interval
+// and, with group_by, per status or tag. Answers may be up to
+// services.StatsCacheTTL old, and clients may keep them as long.
+func (c *eventController) handleEventStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	values := r.URL.Query()
+	q := structures.StatsQuery{
+		Interval: values.Get("interval"),
+		TimeZone: values.Get("tz"),
+		GroupBy:  values.Get("group_by"),
+	}
+	for name, dst := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		t, err := time.Parse(time.RFC3339, values.Get(name))
+		if err != nil {
+			http.Error(w, name+" is required as an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		*dst = t
+	}
+	if !q.To.After(q.From) {
+		http.Error(w, "to must be after from", http.StatusBadRequest)
+		return
+	}
+	if q.Interval == "" {
+		q.Interval = structures.StatsDay
+	}
+	if !structures.ValidStatsInterval(q.Interval) {
+		http.Error(w, "interval must be one of "+strings.Join(structures.StatsIntervals, ", "), http.StatusBadRequest)
+		return
+	}
+	if q.To.Sub(q.From) > structures.MaxStatsRange(q.Interval) {
+		http.Error(w, "from and to must be at most "+strconv.Itoa(structures.MaxStatsBuckets)+" "+q.Interval+"s apart", http.StatusBadRequest)
+		return
+	}
+	if q.TimeZone == "" {
+		q.TimeZone = "UTC"
+	}
+	if _, err := structures.LoadTimeZone(q.TimeZone); err != nil {
+		http.Error(w, "tz must be an IANA time zone such as Europe/Madrid", http.StatusBadRequest)
+		return
+	}
+	if q.GroupBy != "" && !structures.ValidStatsGrouping(q.GroupBy) {
+		http.Error(w, "group_by must be one of "+strings.Join(structures.StatsGroupings, ", "), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.svc.EventStats(ctx, q)
+	if err != nil {
+		log.Printf("Stats error: %v", err)
+		http.Error(w, "failed to compute event stats", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(services.StatsCacheTTL.Seconds())))
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// eventFromRequest validates a create/replace payload and builds the event
+// it describes. Errors are meant to be shown to the client as-is.
+func eventFromRequest(req structures.CreateEventRequest) (*structures.Event, error) {
+	if req.Title == "" {
+		return nil, errors.New("title is required")
+	}
+	if len(req.Title) > 100 {
+		return nil, errors.New("title must be at most 100 characters")
+	}
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	loc, err := structures.LoadTimeZone(req.TimeZone)
+	if err != nil {
+		return nil, errors.New("time_zone must be an IANA time zone such as Europe/Madrid")
+	}
+	if req.AllDay {
+		if req.StartDate == "" || req.EndDate == "" {
+			return nil, errors.New("start_date and end_date are required for all-day events")
+		}
+		req.StartTime, req.EndTime, err = structures.AllDayBounds(req.StartDate, req.EndDate, loc)
+		if err != nil {
+			return nil, errors.New("start_date and end_date must be YYYY-MM-DD")
+		}
+	}
+	if req.StartTime.IsZero() || req.EndTime.IsZero() {
+		return nil, errors.New("start_time and end_time are required")
+	}
+	if !req.StartTime.Before(req.EndTime) {
+		return nil, errors.New("start_time must be before end_time")
+	}
+	if req.Capacity != nil && *req.Capacity < 1 {
+		return nil, errors.New("capacity must be at least 1")
+	}
+	if req.Status != "" && req.Status != structures.StatusDraft && req.Status != structures.StatusScheduled {
+		return nil, errors.New("status must be draft or scheduled; change it later through the transition endpoints")
+	}
+	var tags []string
+	if req.Tags != nil {
+		if tags, err = structures.NormalizeTags(req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := structures.ValidateMetadata(req.Metadata); err != nil {
+		return nil, err
+	}
+	if req.Location != nil {
+		if err := req.Location.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if req.Language == "" {
+		req.Language = structures.DefaultSearchLanguage
+	}
+	if !structures.ValidSearchLanguage(req.Language) {
+		return nil, errors.New("language must be one of " + strings.Join(structures.SearchLanguages, ", "))
+	}
+
+	return &structures.Event{
+		Title:       req.Title,
+		Description: req.Description,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		TimeZone:    req.TimeZone,
+		AllDay:      req.AllDay,
+		Capacity:    req.Capacity,
+		Status:      req.Status,
+		CalendarID:  req.CalendarID,
+		Tags:        tags,
+		Metadata:    req.Metadata,
+		Location:    req.Location,
+		Language:    req.Language,
+	}, nil
+}
+
+func writeEventError(w http.ResponseWriter, op string, err error) {
+	var conflict *structures.BookingConflictError
+	var transition *structures.InvalidTransitionError
+	switch {
+	case errors.Is(err, structures.ErrEventNotFound), errors.Is(err, structures.ErrCalendarNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, structures.ErrEventNotDeleted), errors.Is(err, structures.ErrStatusChanged):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &transition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, structures.BookingConflictResponse{
+			Error:               conflict.Error(),
+			ResourceID:          conflict.ResourceID,
+			ConflictingEventIDs: conflict.EventIDs,
+		})
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to "+strings.ToLower(op)+" event", http.StatusInternalServerError)
+	}
+}
+
+// parseEventID reads the {id} path value, writing a 400 response when it is
+// missing or not a UUID.
+func parseEventID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	return parseUUIDPathValue(w, r, "id")
+}
+
+func parseUUIDPathValue(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	idStr := r.PathValue(name)
+	if idStr == "" {
+		http.Error(w, "missing "+name, http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, "invalid UUID", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseTZ reads the optional tz query parameter used to render event times
+// in the caller's zone. A nil location means "the event's own zone".
+func parseTZ(w http.ResponseWriter, r *http.Request) (*time.Location, bool) {
+	name := r.URL.Query().Get("tz")
+	if name == "" {
+		return nil, true
+	}
+	loc, err := structures.LoadTimeZone(name)
+	if err != nil {
+		http.Error(w, "tz must be an IANA time zone such as Europe/Madrid", http.StatusBadRequest)
+		return nil, false
+	}
+	return loc, true
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
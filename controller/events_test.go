@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,10 +30,48 @@ type mockEventService struct {
 	listResp   []structures.Event
 	listErr    error
 
+	queryReq  structures.EventQuery
+	queryResp *structures.EventPage
+	queryErr  error
+
+	getManyCalls int
+	getManyIDs   []uuid.UUID
+	getManyResp  []structures.Event
+
 	getCalled bool
 	getID     uuid.UUID
 	getResp   *structures.Event
 	getErr    error
+
+	updateCalled bool
+	updateReq    *structures.Event
+	updateResp   *structures.Event
+	updateErr    error
+
+	deleteCalled bool
+	deleteID     uuid.UUID
+	deleteErr    error
+
+	restoreID   uuid.UUID
+	restoreResp *structures.Event
+	restoreErr  error
+
+	transitionReq  structures.StatusChange
+	transitionResp *structures.Event
+	transitionErr  error
+
+	historyResp []structures.EventHistoryEntry
+	historyErr  error
+
+	asOfAt   time.Time
+	asOfResp *structures.Event
+	asOfErr  error
+
+	searchReq  structures.SearchQuery
+	searchResp *structures.SearchPage
+	searchErr  error
+
+	statsReq structures.StatsQuery
 }
 
 func (m *mockEventService) CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
@@ -44,12 +85,64 @@ func (m *mockEventService) ListEvents(ctx context.Context) ([]structures.Event,
 	return m.listResp, m.listErr
 }
 
+func (m *mockEventService) QueryEvents(ctx context.Context, q structures.EventQuery) (*structures.EventPage, error) {
+	m.queryReq = q
+	return m.queryResp, m.queryErr
+}
+
+func (m *mockEventService) GetEvents(ctx context.Context, ids []uuid.UUID) ([]structures.Event, error) {
+	m.getManyCalls++
+	m.getManyIDs = append(m.getManyIDs, ids...)
+	return m.getManyResp, nil
+}
+
 func (m *mockEventService) GetEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
 	m.getCalled = true
 	m.getID = id
 	return m.getResp, m.getErr
 }
 
+func (m *mockEventService) UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	m.updateCalled = true
+	m.updateReq = e
+	return m.updateResp, m.updateErr
+}
+
+func (m *mockEventService) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	m.deleteCalled = true
+	m.deleteID = id
+	return m.deleteErr
+}
+
+func (m *mockEventService) RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	m.restoreID = id
+	return m.restoreResp, m.restoreErr
+}
+
+func (m *mockEventService) TransitionEvent(ctx context.Context, c structures.StatusChange) (*structures.Event, error) {
+	m.transitionReq = c
+	return m.transitionResp, m.transitionErr
+}
+
+func (m *mockEventService) ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error) {
+	return m.historyResp, m.historyErr
+}
+
+func (m *mockEventService) GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*structures.Event, error) {
+	m.asOfAt = at
+	return m.asOfResp, m.asOfErr
+}
+
+func (m *mockEventService) SearchEvents(ctx context.Context, q structures.SearchQuery) (*structures.SearchPage, error) {
+	m.searchReq = q
+	return m.searchResp, m.searchErr
+}
+
+func (m *mockEventService) EventStats(ctx context.Context, q structures.StatsQuery) (*structures.EventStats, error) {
+	m.statsReq = q
+	return &structures.EventStats{Interval: q.Interval, TimeZone: q.TimeZone, Buckets: []structures.StatsBucket{}}, nil
+}
+
 // --- tests ---
 
 func TestHandleCreateEvent_Success(t *testing.T) {
@@ -183,6 +276,43 @@ func TestHandleListEvents_Success(t *testing.T) {
 	}
 }
 
+func TestHandleListEvents_Paged(t *testing.T) {
+	mockSvc := &mockEventService{
+		queryResp: &structures.EventPage{Events: []structures.Event{{ID: uuid.New(), Title: "A"}}, NextCursor: "abc"},
+	}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?limit=1&title=A&from=2026-05-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	ctrl.handleListEvents(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if mockSvc.listCalled || mockSvc.queryReq.Limit != 1 || mockSvc.queryReq.TitleContains != "A" || mockSvc.queryReq.From == nil {
+		t.Fatalf("expected a paged query, got %+v", mockSvc.queryReq)
+	}
+	if link := w.Header().Get("Link"); link != `</events?cursor=abc&from=2026-05-01T00%3A00%3A00Z&limit=1&title=A>; rel="next"` {
+		t.Fatalf("unexpected Link header: %q", link)
+	}
+}
+
+func TestHandleListEvents_InvalidPaging(t *testing.T) {
+	for _, query := range []string{"limit=0", "limit=101", "cursor=nope", "from=yesterday", "from=2026-05-02T00:00:00Z&to=2026-05-01T00:00:00Z", "include_deleted=maybe", "tag=has%20space", "metadata.room=1&metadata.room.floor=3",
+		"near=52.52,13.405", "radius_km=5", "near=52.52&radius_km=5", "near=91,0&radius_km=5", "near=0,0&radius_km=0", "near=0,0&radius_km=30000"} {
+		ctrl := NewEventController(&mockEventService{}).(*eventController)
+		req := httptest.NewRequest(http.MethodGet, "/events?"+query, nil)
+		w := httptest.NewRecorder()
+
+		ctrl.handleListEvents(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
 func TestHandleGetEventByID_Success(t *testing.T) {
 	id := uuid.New()
 	mockSvc := &mockEventService{
@@ -191,6 +321,7 @@ func TestHandleGetEventByID_Success(t *testing.T) {
 	ctrl := NewEventController(mockSvc).(*eventController)
 
 	req := httptest.NewRequest(http.MethodGet, "/events/"+id.String(), nil)
+	req.SetPathValue("id", id.String())
 	w := httptest.NewRecorder()
 
 	ctrl.handleGetEventByID(w, req)
@@ -218,6 +349,7 @@ func TestHandleGetEventByID_NotFound(t *testing.T) {
 	ctrl := NewEventController(mockSvc).(*eventController)
 
 	req := httptest.NewRequest(http.MethodGet, "/events/"+id.String(), nil)
+	req.SetPathValue("id", id.String())
 	w := httptest.NewRecorder()
 
 	ctrl.handleGetEventByID(w, req)
@@ -235,6 +367,7 @@ func TestHandleGetEventByID_InvalidUUID(t *testing.T) {
 	ctrl := NewEventController(mockSvc).(*eventController)
 
 	req := httptest.NewRequest(http.MethodGet, "/events/not-a-uuid", nil)
+	req.SetPathValue("id", "not-a-uuid")
 	w := httptest.NewRecorder()
 
 	ctrl.handleGetEventByID(w, req)
@@ -249,3 +382,528 @@ func TestHandleGetEventByID_InvalidUUID(t *testing.T) {
 		t.Fatalf("service should not be called on invalid UUID")
 	}
 }
+
+func TestHandleCreateEvent_AllDay(t *testing.T) {
+	mockSvc := &mockEventService{}
+	mockSvc.createResp = &structures.Event{}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	body, _ := json.Marshal(structures.CreateEventRequest{
+		Title:     "Offsite",
+		TimeZone:  "Europe/Madrid",
+		AllDay:    true,
+		StartDate: "2025-03-29",
+		EndDate:   "2025-03-30",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	ctrl.handleCreateEvent(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	got := mockSvc.createReq
+	if !got.AllDay || got.TimeZone != "Europe/Madrid" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	if want := time.Date(2025, 3, 29, 0, 0, 0, 0, madrid); !got.StartTime.Equal(want) {
+		t.Fatalf("unexpected start: got %v, want %v", got.StartTime, want)
+	}
+	// The DST switch on 2025-03-30 makes the second day 23 hours long.
+	if want := time.Date(2025, 3, 31, 0, 0, 0, 0, madrid); !got.EndTime.Equal(want) {
+		t.Fatalf("unexpected end: got %v, want %v", got.EndTime, want)
+	}
+}
+
+func TestHandleCreateEvent_InvalidTimeZone(t *testing.T) {
+	now := time.Now().UTC()
+	mockSvc := &mockEventService{}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	body, _ := json.Marshal(structures.CreateEventRequest{
+		Title:     "Test",
+		StartTime: now,
+		EndTime:   now.Add(time.Hour),
+		TimeZone:  "Mars/Olympus_Mons",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	ctrl.handleCreateEvent(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if mockSvc.createCalled {
+		t.Fatalf("service should not be called on invalid time zone")
+	}
+}
+
+func TestHandleGetEventByID_RendersInRequestedTZ(t *testing.T) {
+	id := uuid.New()
+	start := time.Date(2025, 12, 10, 18, 0, 0, 0, time.UTC)
+	mockSvc := &mockEventService{
+		getResp: &structures.Event{ID: id, StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"},
+	}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+id.String()+"?tz=America/New_York", nil)
+	req.SetPathValue("id", id.String())
+	w := httptest.NewRecorder()
+
+	ctrl.handleGetEventByID(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var got struct {
+		StartTime string `json:"start_time"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.StartTime != "2025-12-10T13:00:00-05:00" {
+		t.Fatalf("unexpected rendered start_time %q", got.StartTime)
+	}
+}
+
+func TestHandleUpdateEvent_Success(t *testing.T) {
+	id := uuid.New()
+	start := time.Now().Add(time.Hour).UTC()
+	mockSvc := &mockEventService{
+		updateResp: &structures.Event{ID: id, Title: "Moved", StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"},
+	}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	body, _ := json.Marshal(structures.CreateEventRequest{
+		Title:     "Moved",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+	})
+	req := httptest.NewRequest(http.MethodPut, "/events/"+id.String(), bytes.NewReader(body))
+	req.SetPathValue("id", id.String())
+	w := httptest.NewRecorder()
+
+	ctrl.handleUpdateEvent(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, res.StatusCode)
+	}
+	if !mockSvc.updateCalled || mockSvc.updateReq.ID != id {
+		t.Fatalf("expected UpdateEvent to be called for %v, got %+v", id, mockSvc.updateReq)
+	}
+}
+
+func TestHandleUpdateEvent_NotFound(t *testing.T) {
+	id := uuid.New()
+	start := time.Now().Add(time.Hour).UTC()
+	mockSvc := &mockEventService{updateErr: structures.ErrEventNotFound}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	body, _ := json.Marshal(structures.CreateEventRequest{
+		Title:     "Moved",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+	})
+	req := httptest.NewRequest(http.MethodPut, "/events/"+id.String(), bytes.NewReader(body))
+	req.SetPathValue("id", id.String())
+	w := httptest.NewRecorder()
+
+	ctrl.handleUpdateEvent(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHandleDeleteEvent(t *testing.T) {
+	id := uuid.New()
+	mockSvc := &mockEventService{}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+id.String(), nil)
+	req.SetPathValue("id", id.String())
+	w := httptest.NewRecorder()
+
+	ctrl.handleDeleteEvent(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if mockSvc.deleteID != id {
+		t.Fatalf("service called with wrong ID: got %v, want %v", mockSvc.deleteID, id)
+	}
+}
+
+func TestHandleGetEventByID_AsOf(t *testing.T) {
+	id := uuid.New()
+	mockSvc := &mockEventService{asOfResp: &structures.Event{ID: id, Title: "Then"}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+id.String()+"?as_of=2026-03-01T10:00:00Z", nil)
+	req.SetPathValue("id", id.String())
+	w := httptest.NewRecorder()
+	ctrl.handleGetEventByID(w, req)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Then") {
+		t.Fatalf("expected the past version, got %d %s", w.Code, w.Body.String())
+	}
+	if mockSvc.getCalled || !mockSvc.asOfAt.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected GetEventAsOf at the requested instant, got %v", mockSvc.asOfAt)
+	}
+
+	mockSvc.asOfResp = nil
+	w = httptest.NewRecorder()
+	ctrl.handleGetEventByID(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when the event did not exist then, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/events/"+id.String()+"?as_of=yesterday", nil)
+	req.SetPathValue("id", id.String())
+	w = httptest.NewRecorder()
+	ctrl.handleGetEventByID(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid as_of, got %d", w.Code)
+	}
+}
+
+func TestHandleListEventHistory(t *testing.T) {
+	id := uuid.New()
+	mockSvc := &mockEventService{historyResp: []structures.EventHistoryEntry{
+		{ID: 1, EventID: id, Operation: structures.HistoryCreate, Actor: "token:abc"},
+	}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+id.String()+"/history", nil)
+	req.SetPathValue("id", id.String())
+	w := httptest.NewRecorder()
+	ctrl.handleListEventHistory(w, req)
+
+	var got []structures.EventHistoryEntry
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("expected 200 with JSON, got %d (%v)", w.Code, err)
+	}
+	if len(got) != 1 || got[0].Actor != "token:abc" {
+		t.Fatalf("unexpected history %+v", got)
+	}
+
+	mockSvc.historyErr = structures.ErrEventNotFound
+	w = httptest.NewRecorder()
+	ctrl.handleListEventHistory(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown event, got %d", w.Code)
+	}
+}
+
+func TestHandleListEvents_IncludeDeleted(t *testing.T) {
+	mockSvc := &mockEventService{queryResp: &structures.EventPage{Events: []structures.Event{}}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?include_deleted=true", nil)
+	w := httptest.NewRecorder()
+	ctrl.handleListEvents(w, req)
+
+	if w.Code != http.StatusOK || mockSvc.listCalled || !mockSvc.queryReq.IncludeDeleted {
+		t.Fatalf("expected a query including deleted events, got %d %+v", w.Code, mockSvc.queryReq)
+	}
+}
+
+func TestHandleEventAction_Restore(t *testing.T) {
+	id := uuid.New()
+	mockSvc := &mockEventService{restoreResp: &structures.Event{ID: id, Title: "Back", TimeZone: "UTC"}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+	mux := http.NewServeMux()
+	ctrl.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+id.String()+":restore", nil))
+	if w.Code != http.StatusOK || mockSvc.restoreID != id {
+		t.Fatalf("expected the event to be restored, got %d (id %v)", w.Code, mockSvc.restoreID)
+	}
+
+	mockSvc.restoreErr = structures.ErrEventNotDeleted
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+id.String()+":restore", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a live event, got %d", w.Code)
+	}
+
+	cases := map[string]int{
+		"/events/" + id.String():              http.StatusNotFound,
+		"/events/" + id.String() + ":archive": http.StatusNotFound,
+		"/events/not-a-uuid:restore":          http.StatusBadRequest,
+	}
+	for path, want := range cases {
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		if w.Code != want {
+			t.Errorf("POST %s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestHandleEventAction_Transitions(t *testing.T) {
+	id := uuid.New()
+	mockSvc := &mockEventService{transitionResp: &structures.Event{ID: id, Status: structures.StatusCancelled, TimeZone: "UTC"}}
+	mux := http.NewServeMux()
+	NewEventController(mockSvc).RegisterRoutes(mux)
+
+	body := strings.NewReader(`{"reason": "speaker ill"}`)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+id.String()+":cancel", body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := structures.StatusChange{EventID: id, To: structures.StatusCancelled, Reason: "speaker ill"}
+	if mockSvc.transitionReq != want {
+		t.Fatalf("service called with %+v, want %+v", mockSvc.transitionReq, want)
+	}
+
+	// The body is optional.
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+id.String()+":complete", nil))
+	if w.Code != http.StatusOK || mockSvc.transitionReq.To != structures.StatusCompleted {
+		t.Fatalf("expected a transition to completed, got %d %+v", w.Code, mockSvc.transitionReq)
+	}
+
+	mockSvc.transitionErr = &structures.InvalidTransitionError{From: structures.StatusCompleted, To: structures.StatusScheduled}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+id.String()+":schedule", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a forbidden transition, got %d", w.Code)
+	}
+
+	long := `{"reason": "` + strings.Repeat("x", structures.MaxStatusReason+1) + `"}`
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/"+id.String()+":postpone", strings.NewReader(long)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a long reason, got %d", w.Code)
+	}
+}
+
+func TestHandleListEvents_StatusFilter(t *testing.T) {
+	mockSvc := &mockEventService{queryResp: &structures.EventPage{Events: []structures.Event{}}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?status=draft,postponed&status=cancelled", nil)
+	w := httptest.NewRecorder()
+	ctrl.handleListEvents(w, req)
+	if w.Code != http.StatusOK || strings.Join(mockSvc.queryReq.Statuses, ",") != "draft,postponed,cancelled" {
+		t.Fatalf("expected three statuses, got %d %v", w.Code, mockSvc.queryReq.Statuses)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/events?status=archived", nil)
+	w = httptest.NewRecorder()
+	ctrl.handleListEvents(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown status, got %d", w.Code)
+	}
+}
+
+func TestHandleCreateEvent_RejectsLaterStatuses(t *testing.T) {
+	ctrl := NewEventController(&mockEventService{}).(*eventController)
+	body := `{"title": "T", "start_time": "2026-05-01T10:00:00Z", "end_time": "2026-05-01T11:00:00Z", "status": "completed"}`
+	w := httptest.NewRecorder()
+	ctrl.handleCreateEvent(w, httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestHandleListEvents_TagAndMetadataFilters(t *testing.T) {
+	mockSvc := &mockEventService{queryResp: &structures.EventPage{Events: []structures.Event{}}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?tag=Team:Payments,project:atlas&tag=team:payments&metadata.room.floor=3&metadata.owner=ana", nil)
+	w := httptest.NewRecorder()
+	ctrl.handleListEvents(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := strings.Join(mockSvc.queryReq.Tags, ","); got != "project:atlas,team:payments" {
+		t.Errorf("expected normalized tags, got %q", got)
+	}
+	want := map[string]string{"room.floor": "3", "owner": "ana"}
+	if !maps.Equal(mockSvc.queryReq.Metadata, want) {
+		t.Errorf("expected metadata filters %v, got %v", want, mockSvc.queryReq.Metadata)
+	}
+}
+
+func TestEventFromRequest_TagsAndMetadata(t *testing.T) {
+	base := structures.CreateEventRequest{
+		Title:     "T",
+		StartTime: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	req := base
+	req.Tags = []string{" Type:Workshop ", "type:workshop"}
+	req.Metadata = map[string]any{"room": map[string]any{"floor": 3}}
+	ev, err := eventFromRequest(req)
+	if err != nil || len(ev.Tags) != 1 || ev.Tags[0] != "type:workshop" || ev.Metadata["room"] == nil {
+		t.Fatalf("eventFromRequest = %+v, %v", ev, err)
+	}
+
+	tooMany := make([]string, structures.MaxEventTags+1)
+	for i := range tooMany {
+		tooMany[i] = "t" + strconv.Itoa(i)
+	}
+	for name, mutate := range map[string]func(*structures.CreateEventRequest){
+		"bad tag":       func(r *structures.CreateEventRequest) { r.Tags = []string{"-nope"} },
+		"too many tags": func(r *structures.CreateEventRequest) { r.Tags = tooMany },
+		"dotted key":    func(r *structures.CreateEventRequest) { r.Metadata = map[string]any{"a.b": 1} },
+		"too large": func(r *structures.CreateEventRequest) {
+			r.Metadata = map[string]any{"notes": strings.Repeat("x", structures.MaxMetadataBytes)}
+		},
+		"nested too deep": func(r *structures.CreateEventRequest) {
+			r.Metadata = map[string]any{"a": []any{[]any{[]any{[]any{[]any{1}}}}}}
+		},
+	} {
+		req := base
+		mutate(&req)
+		if _, err := eventFromRequest(req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestHandleListEvents_Near(t *testing.T) {
+	mockSvc := &mockEventService{queryResp: &structures.EventPage{Events: []structures.Event{}}}
+	ctrl := NewEventController(mockSvc).(*eventController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events?near=52.52,%2013.405&radius_km=2.5", nil)
+	w := httptest.NewRecorder()
+	ctrl.handleListEvents(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	q := mockSvc.queryReq
+	if q.Near == nil || *q.Near != (structures.GeoPoint{Lat: 52.52, Lng: 13.405}) || q.RadiusKm != 2.5 {
+		t.Fatalf("unexpected near search: %+v %v", q.Near, q.RadiusKm)
+	}
+}
+
+func TestEventFromRequest_Location(t *testing.T) {
+	lat, lng, bad := 52.52, 13.405, 200.0
+	base := structures.CreateEventRequest{
+		Title:     "T",
+		StartTime: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	req := base
+	req.Location = &structures.Location{Name: "HQ", Latitude: &lat, Longitude: &lng}
+	ev, err := eventFromRequest(req)
+	if err != nil || ev.Location == nil || ev.Location.Name != "HQ" {
+		t.Fatalf("eventFromRequest = %+v, %v", ev, err)
+	}
+
+	for name, loc := range map[string]*structures.Location{
+		"empty":        {},
+		"lat only":     {Latitude: &lat},
+		"out of range": {Latitude: &lat, Longitude: &bad},
+		"long name":    {Name: strings.Repeat("x", 201)},
+		"long address": {Address: strings.Repeat("x", 501)},
+	} {
+		req := base
+		req.Location = loc
+		if _, err := eventFromRequest(req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestHandleSearch(t *testing.T) {
+	id := uuid.New()
+	next := structures.SearchCursor{Rank: 0.5, ID: id}.Encode()
+	mockSvc := &mockEventService{searchResp: &structures.SearchPage{
+		Results: []structures.SearchResult{{
+			Event:         structures.Event{ID: id, TimeZone: "Europe/Madrid", StartTime: time.Now().UTC()},
+			Rank:          0.5,
+			TitleHeadline: "<mark>planning</mark>",
+		}},
+		NextCursor: next,
+	}}
+	mux := http.NewServeMux()
+	NewSearchController(mockSvc).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=+planning+notes+&lang=english&limit=5", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	q := mockSvc.searchReq
+	if q.Text != "planning notes" || q.Language != "english" || q.Limit != 5 || q.After != nil {
+		t.Errorf("unexpected query: %+v", q)
+	}
+	var got []structures.SearchResult
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil || len(got) != 1 {
+		t.Fatalf("unexpected body: %v", err)
+	}
+	if got[0].Event.StartTime.Location().String() == "UTC" {
+		t.Errorf("expected the event's own zone, got %v", got[0].Event.StartTime)
+	}
+	if link := w.Header().Get("Link"); !strings.Contains(link, "cursor="+next) {
+		t.Errorf("unexpected Link header %q", link)
+	}
+}
+
+func TestHandleSearch_InvalidQuery(t *testing.T) {
+	for _, query := range []string{"", "q=+", "q=" + strings.Repeat("a", structures.MaxSearchTextLength+1),
+		"q=a&lang=klingon", "q=a&limit=0", "q=a&cursor=bad", "q=a&tz=Mars/Olympus"} {
+		mockSvc := &mockEventService{}
+		mux := http.NewServeMux()
+		NewSearchController(mockSvc).RegisterRoutes(mux)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%.20s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestHandleEventStats(t *testing.T) {
+	mockSvc := &mockEventService{}
+	mux := http.NewServeMux()
+	NewEventController(mockSvc).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/events/stats?from=2026-01-01T00:00:00Z&to=2026-04-01T00:00:00Z&interval=week&tz=Europe/Madrid&group_by=tag", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	q := mockSvc.statsReq
+	if q.Interval != structures.StatsWeek || q.TimeZone != "Europe/Madrid" || q.GroupBy != structures.StatsByTag ||
+		q.From.Month() != time.January || q.To.Month() != time.April {
+		t.Errorf("unexpected query: %+v", q)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "private, max-age=30" {
+		t.Errorf("unexpected Cache-Control %q", cc)
+	}
+}
+
+func TestHandleEventStats_InvalidQuery(t *testing.T) {
+	const span = "from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z"
+	for _, query := range []string{
+		"", "from=2026-01-01T00:00:00Z", "from=2026-02-01T00:00:00Z&to=2026-01-01T00:00:00Z",
+		span + "&interval=year", span + "&tz=Mars/Olympus", span + "&group_by=calendar",
+		"from=2020-01-01T00:00:00Z&to=2026-01-01T00:00:00Z",
+	} {
+		mux := http.NewServeMux()
+		NewEventController(&mockEventService{}).RegisterRoutes(mux)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/stats?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
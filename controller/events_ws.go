@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = (wsPongWait * 9) / 10
+	wsMaxCommand   = 64 << 10
+)
+
+type WSController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type wsController struct {
+	hub      services.ChangeHub
+	access   services.EventAccess
+	upgrader websocket.Upgrader
+	ping     time.Duration
+}
+
+func NewWSController(hub services.ChangeHub, access services.EventAccess) WSController {
+	return &wsController{hub: hub, access: access, ping: wsPingInterval}
+}
+
+func (c *wsController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /events/ws", c.handleWS)
+}
+
+// wsCommand is a message from the client: {"action": "subscribe",
+// "filter": {...}} replaces its filter, {"action": "unsubscribe"} clears it.
+type wsCommand struct {
+	Action string                   `json:"action"`
+	Filter *structures.ChangeFilter `json:"filter"`
+}
+
+// wsMessage is a message to the client. Type is "change", "subscribed",
+// "unsubscribed" or "error".
+type wsMessage struct {
+	Type    string                   `json:"type"`
+	ID      int64                    `json:"id,omitempty"`
+	Event   string                   `json:"event,omitempty"`
+	EventID *uuid.UUID               `json:"event_id,omitempty"`
+	Data    json.RawMessage          `json:"data,omitempty"`
+	Filter  *structures.ChangeFilter `json:"filter,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+func (c *wsController) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := c.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written the error response.
+		return
+	}
+	client := c.hub.Register()
+	replies := make(chan wsMessage, 8)
+	done := make(chan struct{})
+
+	go c.writePump(conn, client, services.NewReadableChanges(r.Context(), c.access), replies, done)
+	c.readPump(conn, client, replies, done)
+	c.hub.Unregister(client)
+}
+
+// readPump applies the client's commands until the connection fails or the
+// write side gives up. It is the only reader of conn.
+func (c *wsController) readPump(conn *websocket.Conn, client *services.HubClient, replies chan<- wsMessage, done <-chan struct{}) {
+	conn.SetReadLimit(wsMaxCommand)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var cmd wsCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				if !reply(replies, done, wsMessage{Type: "error", Error: "invalid JSON command"}) {
+					return
+				}
+				continue
+			}
+			return
+		}
+
+		var msg wsMessage
+		switch cmd.Action {
+		case "subscribe":
+			if cmd.Filter == nil {
+				cmd.Filter = &structures.ChangeFilter{}
+			}
+			if err := cmd.Filter.Validate(); err != nil {
+				msg = wsMessage{Type: "error", Error: err.Error()}
+				break
+			}
+			client.SetFilter(cmd.Filter)
+			msg = wsMessage{Type: "subscribed", Filter: cmd.Filter}
+		case "unsubscribe":
+			client.SetFilter(nil)
+			msg = wsMessage{Type: "unsubscribed"}
+		default:
+			msg = wsMessage{Type: "error", Error: "action must be subscribe or unsubscribe"}
+		}
+		if !reply(replies, done, msg) {
+			return
+		}
+	}
+}
+
+func reply(replies chan<- wsMessage, done <-chan struct{}, msg wsMessage) bool {
+	select {
+	case replies <- msg:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// writePump is the only writer of conn: it forwards the changes of events
+// the caller can read and replies, and pings the client so dead
+// connections are noticed.
+func (c *wsController) writePump(conn *websocket.Conn, client *services.HubClient, readable *services.ReadableChanges, replies <-chan wsMessage, done chan<- struct{}) {
+	ticker := time.NewTicker(c.ping)
+	defer func() {
+		ticker.Stop()
+		close(done)
+		conn.Close()
+	}()
+
+	for {
+		var msg wsMessage
+		select {
+		case m, ok := <-client.Messages():
+			if !ok {
+				// Dropped by the hub for falling behind, or shutting down.
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow or server shutting down"))
+				return
+			}
+			allowed, err := readable.Allows(m)
+			if err != nil {
+				log.Printf("WebSocket access error: %v", err)
+				return
+			}
+			if !allowed {
+				continue
+			}
+			msg = wsMessage{Type: "change", ID: m.ID, Event: m.Type, EventID: &m.EventID, Data: m.Payload}
+		case msg = <-replies:
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			if !errors.Is(err, websocket.ErrCloseSent) {
+				log.Printf("WebSocket write error: %v", err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+func TestHandleWS_SubscribeAndReceiveChanges(t *testing.T) {
+	feed := &fakeFeed{sub: &services.FeedSubscription{C: make(chan structures.OutboxMessage, 4)}}
+	hub := services.NewHub(feed, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	mux := http.NewServeMux()
+	NewWSController(hub, fakeAccess{}).RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/events/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	watched := uuid.New()
+	if err := conn.WriteJSON(map[string]any{
+		"action": "subscribe",
+		"filter": map[string]any{"event_ids": []uuid.UUID{watched}},
+	}); err != nil {
+		t.Fatalf("write subscribe: %v", err)
+	}
+	var ack wsMessage
+	if err := conn.ReadJSON(&ack); err != nil || ack.Type != "subscribed" {
+		t.Fatalf("expected subscribed ack, got %+v (%v)", ack, err)
+	}
+
+	feed.sub.C <- structures.OutboxMessage{ID: 1, EventID: uuid.New(), Type: structures.EventUpdated, Payload: json.RawMessage(`{}`)}
+	feed.sub.C <- structures.OutboxMessage{ID: 2, EventID: watched, Type: structures.EventDeleted, Payload: json.RawMessage(`{}`)}
+
+	var change wsMessage
+	if err := conn.ReadJSON(&change); err != nil {
+		t.Fatalf("read change: %v", err)
+	}
+	if change.Type != "change" || change.ID != 2 || change.Event != structures.EventDeleted || *change.EventID != watched {
+		t.Fatalf("unexpected change: %+v", change)
+	}
+}
+
+func TestHandleWS_InvalidFilter(t *testing.T) {
+	hub := services.NewHub(&fakeFeed{}, 8)
+
+	mux := http.NewServeMux()
+	NewWSController(hub, fakeAccess{}).RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/events/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := conn.WriteJSON(map[string]any{
+		"action": "subscribe",
+		"filter": map[string]any{"from": "2025-12-10T09:00:00Z"},
+	}); err != nil {
+		t.Fatalf("write subscribe: %v", err)
+	}
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "error" {
+		t.Fatalf("expected error reply, got %+v (%v)", msg, err)
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"events/services"
+	"events/structures"
+	"events/utils"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed graphql.graphql
+var graphqlSchema string
+
+const (
+	graphqlMaxDepth     = 8
+	graphqlMaxBody      = 1 << 20
+	graphqlLoaderWait   = 2 * time.Millisecond
+	graphqlLoaderBatch  = 500
+	graphqlQueryTimeout = 5 * time.Second
+)
+
+type GraphQLController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type graphqlController struct {
+	schema    *graphql.Schema
+	events    services.EventService
+	attendees services.AttendeeService
+	resources services.ResourceService
+}
+
+func NewGraphQLController(events services.EventService, attendees services.AttendeeService, resources services.ResourceService) GraphQLController {
+	schema := graphql.MustParseSchema(graphqlSchema, &graphqlResolver{events: events},
+		graphql.MaxDepth(graphqlMaxDepth),
+	)
+	return &graphqlController{schema: schema, events: events, attendees: attendees, resources: resources}
+}
+
+func (c *graphqlController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /graphql", c.handleGraphQL)
+}
+
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// handleGraphQL executes one query or mutation. Per the GraphQL over HTTP
+// convention, errors inside a well-formed request come back with 200 in the
+// "errors" array.
+func (c *graphqlController) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req graphqlRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphqlMaxBody)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), graphqlQueryTimeout)
+	defer cancel()
+	ctx = withGraphQLLoaders(ctx, c.newLoaders())
+
+	resp := c.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// graphqlLoaders batch the lookups that would otherwise run once per item
+// of a list: the events behind attendees and aliased event(id:) fields, and
+// the attendees and resources of every event on a page.
+type graphqlLoaders struct {
+	events    *utils.Loader[uuid.UUID, *structures.Event]
+	attendees *utils.Loader[uuid.UUID, []structures.Attendee]
+	resources *utils.Loader[uuid.UUID, []structures.Resource]
+}
+
+func (c *graphqlController) newLoaders() *graphqlLoaders {
+	return &graphqlLoaders{
+		events: utils.NewLoader(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*structures.Event, error) {
+			events, err := c.events.GetEvents(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uuid.UUID]*structures.Event, len(events))
+			for i := range events {
+				byID[events[i].ID] = &events[i]
+			}
+			return byID, nil
+		}, graphqlLoaderWait, graphqlLoaderBatch),
+		attendees: utils.NewLoader(c.attendees.ListAttendeesForEvents, graphqlLoaderWait, graphqlLoaderBatch),
+		resources: utils.NewLoader(c.resources.ListResourcesForEvents, graphqlLoaderWait, graphqlLoaderBatch),
+	}
+}
+
+type graphqlLoadersKey struct{}
+
+func withGraphQLLoaders(ctx context.Context, l *graphqlLoaders) context.Context {
+	return context.WithValue(ctx, graphqlLoadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+}
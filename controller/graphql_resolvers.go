@@ -0,0 +1,442 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// graphqlResolver is the root of the GraphQL schema in graphql.graphql.
+// Validation and error messages are shared with the REST handlers.
+type graphqlResolver struct {
+	events services.EventService
+}
+
+func (r *graphqlResolver) Event(ctx context.Context, args struct {
+	ID graphql.ID
+	Tz *string
+}) (*eventResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, errors.New("invalid UUID")
+	}
+	loc, err := graphqlTZ(args.Tz)
+	if err != nil {
+		return nil, err
+	}
+	e, err := loadersFrom(ctx).events.Load(ctx, id)
+	if err != nil {
+		return nil, graphqlInternal("Get", err)
+	}
+	if e == nil {
+		return nil, nil
+	}
+	return &eventResolver{e: e.In(loc), loc: loc}, nil
+}
+
+type eventFilterInput struct {
+	From          *graphql.Time
+	To            *graphql.Time
+	TitleContains *string
+	Statuses      *[]string
+}
+
+func (r *graphqlResolver) Events(ctx context.Context, args struct {
+	Filter *eventFilterInput
+	First  *int32
+	After  *string
+	Tz     *string
+}) (*eventConnectionResolver, error) {
+	loc, err := graphqlTZ(args.Tz)
+	if err != nil {
+		return nil, err
+	}
+
+	var q structures.EventQuery
+	if args.First != nil {
+		if *args.First < 1 {
+			return nil, errors.New("first must be at least 1")
+		}
+		q.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		if q.After, err = structures.DecodeEventCursor(*args.After); err != nil {
+			return nil, err
+		}
+	}
+	if f := args.Filter; f != nil {
+		if f.From != nil {
+			q.From = &f.From.Time
+		}
+		if f.To != nil {
+			q.To = &f.To.Time
+		}
+		if q.From != nil && q.To != nil && !q.To.After(*q.From) {
+			return nil, errors.New("to must be after from")
+		}
+		if f.TitleContains != nil {
+			q.TitleContains = *f.TitleContains
+		}
+		if f.Statuses != nil {
+			for _, st := range *f.Statuses {
+				if !structures.ValidEventStatus(st) {
+					return nil, errors.New("statuses must be one of " + strings.Join(structures.EventStatuses, ", "))
+				}
+			}
+			q.Statuses = *f.Statuses
+		}
+	}
+
+	page, err := r.events.QueryEvents(ctx, q)
+	if err != nil {
+		return nil, graphqlInternal("List", err)
+	}
+	return &eventConnectionResolver{page: page, loc: loc}, nil
+}
+
+type eventInput struct {
+	Title       string
+	Description *string
+	StartTime   *graphql.Time
+	EndTime     *graphql.Time
+	TimeZone    *string
+	AllDay      *bool
+	StartDate   *string
+	EndDate     *string
+	Capacity    *int32
+	Tags        *[]string
+	Metadata    *jsonObject
+	Location    *locationInput
+	Language    *string
+	Status      *string
+	CalendarID  *graphql.ID
+}
+
+type locationInput struct {
+	Name    *string
+	Address *string
+	Lat     *float64
+	Lng     *float64
+}
+
+func (in eventInput) request() (structures.CreateEventRequest, error) {
+	req := structures.CreateEventRequest{Title: in.Title}
+	if in.Description != nil {
+		req.Description = *in.Description
+	}
+	if in.StartTime != nil {
+		req.StartTime = in.StartTime.Time
+	}
+	if in.EndTime != nil {
+		req.EndTime = in.EndTime.Time
+	}
+	if in.TimeZone != nil {
+		req.TimeZone = *in.TimeZone
+	}
+	if in.AllDay != nil {
+		req.AllDay = *in.AllDay
+	}
+	if in.StartDate != nil {
+		req.StartDate = *in.StartDate
+	}
+	if in.EndDate != nil {
+		req.EndDate = *in.EndDate
+	}
+	if in.Capacity != nil {
+		c := int(*in.Capacity)
+		req.Capacity = &c
+	}
+	if in.Tags != nil {
+		req.Tags = *in.Tags
+	}
+	if in.Metadata != nil {
+		req.Metadata = *in.Metadata
+	}
+	if l := in.Location; l != nil {
+		req.Location = &structures.Location{Latitude: l.Lat, Longitude: l.Lng}
+		if l.Name != nil {
+			req.Location.Name = *l.Name
+		}
+		if l.Address != nil {
+			req.Location.Address = *l.Address
+		}
+	}
+	if in.Language != nil {
+		req.Language = *in.Language
+	}
+	if in.Status != nil {
+		req.Status = *in.Status
+	}
+	if in.CalendarID != nil {
+		var err error
+		if req.CalendarID, err = uuid.Parse(string(*in.CalendarID)); err != nil {
+			return req, errors.New("calendarId must be a UUID")
+		}
+	}
+	return req, nil
+}
+
+// jsonObject is the JSON scalar. Inputs must be objects.
+type jsonObject map[string]any
+
+func (jsonObject) ImplementsGraphQLType(name string) bool { return name == "JSON" }
+
+func (o *jsonObject) UnmarshalGraphQL(input any) error {
+	m, ok := input.(map[string]any)
+	if !ok {
+		return errors.New("JSON must be an object")
+	}
+	*o = m
+	return nil
+}
+
+func (r *graphqlResolver) CreateEvent(ctx context.Context, args struct{ Input eventInput }) (*eventResolver, error) {
+	in, err := args.Input.request()
+	if err != nil {
+		return nil, err
+	}
+	ev, err := eventFromRequest(in)
+	if err != nil {
+		return nil, err
+	}
+	ev.ID = uuid.New()
+	ev.CreatedAt = time.Now()
+
+	e, err := r.events.CreateEvent(ctx, ev)
+	if err != nil {
+		return nil, graphqlEventError("Create", err)
+	}
+	return &eventResolver{e: e.In(nil)}, nil
+}
+
+func (r *graphqlResolver) UpdateEvent(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input eventInput
+}) (*eventResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, errors.New("invalid UUID")
+	}
+	in, err := args.Input.request()
+	if err != nil {
+		return nil, err
+	}
+	ev, err := eventFromRequest(in)
+	if err != nil {
+		return nil, err
+	}
+	ev.ID = id
+
+	e, err := r.events.UpdateEvent(ctx, ev)
+	if err != nil {
+		return nil, graphqlEventError("Update", err)
+	}
+	return &eventResolver{e: e.In(nil)}, nil
+}
+
+type eventConnectionResolver struct {
+	page *structures.EventPage
+	loc  *time.Location
+}
+
+func (r *eventConnectionResolver) Nodes() []*eventResolver {
+	nodes := make([]*eventResolver, len(r.page.Events))
+	for i, e := range r.page.Events {
+		nodes[i] = &eventResolver{e: e.In(r.loc), loc: r.loc}
+	}
+	return nodes
+}
+
+func (r *eventConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{next: r.page.NextCursor}
+}
+
+type pageInfoResolver struct {
+	next string
+}
+
+func (r *pageInfoResolver) EndCursor() *string {
+	if r.next == "" {
+		return nil
+	}
+	return &r.next
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.next != ""
+}
+
+// eventResolver resolves an event already rendered in loc; loc is passed on
+// to the events reached through it.
+type eventResolver struct {
+	e   structures.Event
+	loc *time.Location
+}
+
+func (r *eventResolver) ID() graphql.ID          { return graphql.ID(r.e.ID.String()) }
+func (r *eventResolver) CalendarID() graphql.ID  { return graphql.ID(r.e.CalendarID.String()) }
+func (r *eventResolver) Title() string           { return r.e.Title }
+func (r *eventResolver) Description() string     { return r.e.Description }
+func (r *eventResolver) StartTime() graphql.Time { return graphql.Time{Time: r.e.StartTime} }
+func (r *eventResolver) EndTime() graphql.Time   { return graphql.Time{Time: r.e.EndTime} }
+func (r *eventResolver) TimeZone() string        { return r.e.TimeZone }
+func (r *eventResolver) AllDay() bool            { return r.e.AllDay }
+func (r *eventResolver) StartDate() *string      { return optionalString(r.e.StartDate) }
+func (r *eventResolver) EndDate() *string        { return optionalString(r.e.EndDate) }
+func (r *eventResolver) Language() string        { return r.e.Language }
+func (r *eventResolver) Status() string          { return r.e.Status }
+func (r *eventResolver) StatusReason() string    { return r.e.StatusReason }
+func (r *eventResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.e.CreatedAt} }
+
+func (r *eventResolver) Tags() []string {
+	if r.e.Tags == nil {
+		return []string{}
+	}
+	return r.e.Tags
+}
+
+func (r *eventResolver) Metadata() *jsonObject {
+	if r.e.Metadata == nil {
+		return nil
+	}
+	m := jsonObject(r.e.Metadata)
+	return &m
+}
+
+func (r *eventResolver) Location() *locationResolver {
+	if r.e.Location == nil {
+		return nil
+	}
+	return &locationResolver{l: r.e.Location}
+}
+
+func (r *eventResolver) Capacity() *int32 {
+	if r.e.Capacity == nil {
+		return nil
+	}
+	c := int32(*r.e.Capacity)
+	return &c
+}
+
+func (r *eventResolver) AttendeeCounts() *attendeeCountsResolver {
+	return &attendeeCountsResolver{c: r.e.Attendees}
+}
+
+func (r *eventResolver) Attendees(ctx context.Context) ([]*attendeeResolver, error) {
+	attendees, err := loadersFrom(ctx).attendees.Load(ctx, r.e.ID)
+	if err != nil {
+		return nil, graphqlInternal("List attendees", err)
+	}
+	out := make([]*attendeeResolver, len(attendees))
+	for i, a := range attendees {
+		out[i] = &attendeeResolver{a: a, loc: r.loc}
+	}
+	return out, nil
+}
+
+func (r *eventResolver) Resources(ctx context.Context) ([]*resourceResolver, error) {
+	resources, err := loadersFrom(ctx).resources.Load(ctx, r.e.ID)
+	if err != nil {
+		return nil, graphqlInternal("List resources", err)
+	}
+	out := make([]*resourceResolver, len(resources))
+	for i, res := range resources {
+		out[i] = &resourceResolver{r: res}
+	}
+	return out, nil
+}
+
+type attendeeCountsResolver struct {
+	c structures.AttendeeCounts
+}
+
+func (r *attendeeCountsResolver) Total() int32      { return int32(r.c.Total) }
+func (r *attendeeCountsResolver) Accepted() int32   { return int32(r.c.Accepted) }
+func (r *attendeeCountsResolver) Declined() int32   { return int32(r.c.Declined) }
+func (r *attendeeCountsResolver) Tentative() int32  { return int32(r.c.Tentative) }
+func (r *attendeeCountsResolver) Pending() int32    { return int32(r.c.Pending) }
+func (r *attendeeCountsResolver) Waitlisted() int32 { return int32(r.c.Waitlisted) }
+
+type locationResolver struct {
+	l *structures.Location
+}
+
+func (r *locationResolver) Name() *string    { return optionalString(r.l.Name) }
+func (r *locationResolver) Address() *string { return optionalString(r.l.Address) }
+func (r *locationResolver) Lat() *float64    { return r.l.Latitude }
+func (r *locationResolver) Lng() *float64    { return r.l.Longitude }
+
+type attendeeResolver struct {
+	a   structures.Attendee
+	loc *time.Location
+}
+
+func (r *attendeeResolver) Email() string           { return r.a.Email }
+func (r *attendeeResolver) DisplayName() string     { return r.a.DisplayName }
+func (r *attendeeResolver) Role() string            { return r.a.Role }
+func (r *attendeeResolver) RsvpStatus() string      { return r.a.RSVPStatus }
+func (r *attendeeResolver) Waitlisted() bool        { return r.a.Waitlisted }
+func (r *attendeeResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.a.CreatedAt} }
+
+func (r *attendeeResolver) Event(ctx context.Context) (*eventResolver, error) {
+	e, err := loadersFrom(ctx).events.Load(ctx, r.a.EventID)
+	if err != nil {
+		return nil, graphqlInternal("Get", err)
+	}
+	if e == nil {
+		return nil, structures.ErrEventNotFound
+	}
+	return &eventResolver{e: e.In(r.loc), loc: r.loc}, nil
+}
+
+type resourceResolver struct {
+	r structures.Resource
+}
+
+func (r *resourceResolver) ID() graphql.ID          { return graphql.ID(r.r.ID.String()) }
+func (r *resourceResolver) Name() string            { return r.r.Name }
+func (r *resourceResolver) Kind() string            { return r.r.Kind }
+func (r *resourceResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.r.CreatedAt} }
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func graphqlTZ(name *string) (*time.Location, error) {
+	if name == nil || *name == "" {
+		return nil, nil
+	}
+	loc, err := structures.LoadTimeZone(*name)
+	if err != nil {
+		return nil, errors.New("tz must be an IANA time zone such as Europe/Madrid")
+	}
+	return loc, nil
+}
+
+// graphqlEventError is the GraphQL counterpart of writeEventError: client
+// errors keep their message, anything else is logged and hidden.
+func graphqlEventError(op string, err error) error {
+	var conflict *structures.BookingConflictError
+	var transition *structures.InvalidTransitionError
+	if errors.Is(err, structures.ErrEventNotFound) || errors.Is(err, structures.ErrCalendarNotFound) ||
+		errors.Is(err, structures.ErrForbidden) || errors.As(err, &conflict) || errors.As(err, &transition) {
+		return err
+	}
+	return graphqlInternal(op, err)
+}
+
+func graphqlInternal(op string, err error) error {
+	log.Printf("GraphQL %s error: %v", op, err)
+	return errors.New("internal error")
+}
@@ -0,0 +1,295 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- batch-recording mocks ---
+
+type batchAttendeeService struct {
+	services.AttendeeService
+
+	mu    sync.Mutex
+	calls [][]uuid.UUID
+}
+
+func (m *batchAttendeeService) ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, eventIDs)
+	out := make(map[uuid.UUID][]structures.Attendee)
+	for _, id := range eventIDs {
+		out[id] = []structures.Attendee{{EventID: id, Email: "ana@example.com", Role: "required", RSVPStatus: "accepted"}}
+	}
+	return out, nil
+}
+
+type batchResourceService struct {
+	services.ResourceService
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (m *batchResourceService) ListResourcesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Resource, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	return nil, nil
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func execGraphQL(t *testing.T, c GraphQLController, query string, vars map[string]any) graphqlResponse {
+	t.Helper()
+	mux := http.NewServeMux()
+	c.RegisterRoutes(mux)
+
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": vars})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp graphqlResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return resp
+}
+
+// --- tests ---
+
+func TestGraphQLEvents_BatchesRelatedData(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	page := &structures.EventPage{NextCursor: "next"}
+	for i := 0; i < 3; i++ {
+		page.Events = append(page.Events, structures.Event{ID: uuid.New(), Title: "e", StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"})
+	}
+	events := &mockEventService{queryResp: page, getManyResp: page.Events}
+	attendees := &batchAttendeeService{}
+	resources := &batchResourceService{}
+	c := NewGraphQLController(events, attendees, resources)
+
+	resp := execGraphQL(t, c, `query($after: String) {
+		events(first: 3, after: $after, filter: {titleContains: "e"}) {
+			nodes { id attendees { email event { id } } resources { name } }
+			pageInfo { endCursor hasNextPage }
+		}
+	}`, map[string]any{"after": structures.CursorOf(page.Events[0]).Encode()})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+
+	if events.queryReq.Limit != 3 || events.queryReq.TitleContains != "e" || events.queryReq.After == nil {
+		t.Fatalf("unexpected query: %+v", events.queryReq)
+	}
+	if len(attendees.calls) != 1 || len(attendees.calls[0]) != 3 {
+		t.Fatalf("expected one attendee batch of 3 events, got %v", attendees.calls)
+	}
+	if resources.calls != 1 {
+		t.Fatalf("expected one resource batch, got %d", resources.calls)
+	}
+	if events.getManyCalls != 1 || len(events.getManyIDs) != 3 {
+		t.Fatalf("expected one event batch for the attendees' events, got %d calls with %v", events.getManyCalls, events.getManyIDs)
+	}
+
+	var data struct {
+		Events struct {
+			Nodes    []json.RawMessage
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+		}
+	}
+	json.Unmarshal(resp.Data, &data)
+	if len(data.Events.Nodes) != 3 || !data.Events.PageInfo.HasNextPage || data.Events.PageInfo.EndCursor != "next" {
+		t.Fatalf("unexpected data: %s", resp.Data)
+	}
+}
+
+func TestGraphQLEvent_AliasesShareOneLookup(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	events := &mockEventService{getManyResp: []structures.Event{{ID: a, Title: "A", TimeZone: "UTC"}}}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `query($a: ID!, $b: ID!) { first: event(id: $a) { title } second: event(id: $b) { title } }`,
+		map[string]any{"a": a.String(), "b": b.String()})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if events.getManyCalls != 1 {
+		t.Fatalf("expected one GetEvents call, got %d", events.getManyCalls)
+	}
+	if string(resp.Data) != `{"first":{"title":"A"},"second":null}` {
+		t.Fatalf("unexpected data: %s", resp.Data)
+	}
+}
+
+func TestGraphQLCreateEvent_SharesRESTValidation(t *testing.T) {
+	events := &mockEventService{}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `mutation {
+		createEvent(input: {title: "Launch", startTime: "2026-05-01T10:00:00Z", endTime: "2026-05-01T09:00:00Z"}) { id }
+	}`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != "start_time must be before end_time" {
+		t.Fatalf("expected the REST validation error, got %v", resp.Errors)
+	}
+	if events.createCalled {
+		t.Fatal("expected CreateEvent not to be called")
+	}
+}
+
+func TestGraphQLUpdateEvent_NotFound(t *testing.T) {
+	// Events in unreadable calendars are not found; read-only ones are
+	// forbidden.
+	for _, want := range []error{structures.ErrEventNotFound, structures.ErrForbidden} {
+		events := &mockEventService{updateErr: want}
+		c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+		resp := execGraphQL(t, c, `mutation($id: ID!) {
+			updateEvent(id: $id, input: {title: "Launch", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z"}) { id }
+		}`, map[string]any{"id": uuid.NewString()})
+		if len(resp.Errors) != 1 || resp.Errors[0].Message != want.Error() {
+			t.Fatalf("expected %q, got %v", want, resp.Errors)
+		}
+	}
+}
+
+func TestGraphQLUpdateEvent_KeepsTagsAndMetadata(t *testing.T) {
+	events := &echoEventService{}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `mutation($id: ID!, $meta: JSON) {
+		updateEvent(id: $id, input: {title: "Launch", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z",
+			tags: ["Planning", "q2"], metadata: $meta}) { tags metadata }
+	}`, map[string]any{"id": uuid.NewString(), "meta": map[string]any{"room": "4B", "seats": 12}})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if got := events.updateReq; len(got.Tags) != 2 || got.Tags[0] != "planning" || got.Metadata["room"] != "4B" {
+		t.Fatalf("expected tags and metadata in the replacement, got %+v", got)
+	}
+	if string(resp.Data) != `{"updateEvent":{"tags":["planning","q2"],"metadata":{"room":"4B","seats":12}}}` {
+		t.Fatalf("unexpected data: %s", resp.Data)
+	}
+}
+
+func TestGraphQLUpdateEvent_KeepsLocation(t *testing.T) {
+	events := &echoEventService{}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `mutation($id: ID!) {
+		updateEvent(id: $id, input: {title: "Launch", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z",
+			location: {name: "HQ", lat: 52.52, lng: 13.405}}) { location { name address lat lng } }
+	}`, map[string]any{"id": uuid.NewString()})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if l := events.updateReq.Location; l == nil || l.Name != "HQ" || l.Longitude == nil || *l.Longitude != 13.405 {
+		t.Fatalf("expected the location in the replacement, got %+v", l)
+	}
+	if string(resp.Data) != `{"updateEvent":{"location":{"name":"HQ","address":null,"lat":52.52,"lng":13.405}}}` {
+		t.Fatalf("unexpected data: %s", resp.Data)
+	}
+}
+
+func TestGraphQLUpdateEvent_KeepsLanguage(t *testing.T) {
+	events := &echoEventService{}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `mutation($id: ID!) {
+		updateEvent(id: $id, input: {title: "Auftakt", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z",
+			language: "german"}) { language }
+	}`, map[string]any{"id": uuid.NewString()})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if events.updateReq.Language != "german" || string(resp.Data) != `{"updateEvent":{"language":"german"}}` {
+		t.Fatalf("expected language german, got %q and %s", events.updateReq.Language, resp.Data)
+	}
+}
+
+func TestGraphQLEvents_FiltersByStatus(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	page := &structures.EventPage{Events: []structures.Event{{ID: uuid.New(), Title: "Offsite", StartTime: start, EndTime: start.Add(time.Hour),
+		TimeZone: "UTC", Status: structures.StatusCancelled, StatusReason: "venue closed"}}}
+	events := &mockEventService{queryResp: page}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `{ events(filter: {statuses: ["cancelled", "postponed"]}) { nodes { status statusReason } } }`, nil)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors)
+	}
+	if got := events.queryReq.Statuses; len(got) != 2 || got[0] != structures.StatusCancelled || got[1] != structures.StatusPostponed {
+		t.Fatalf("expected both statuses in the query, got %v", got)
+	}
+	if string(resp.Data) != `{"events":{"nodes":[{"status":"cancelled","statusReason":"venue closed"}]}}` {
+		t.Fatalf("unexpected data: %s", resp.Data)
+	}
+
+	resp = execGraphQL(t, c, `{ events(filter: {statuses: ["archived"]}) { nodes { id } } }`, nil)
+	if len(resp.Errors) != 1 {
+		t.Fatalf("expected an error for an unknown status, got %s", resp.Data)
+	}
+}
+
+func TestGraphQLCreateEvent_InvalidTransition(t *testing.T) {
+	transition := &structures.InvalidTransitionError{From: "new", To: structures.StatusDraft}
+	events := &mockEventService{createErr: transition}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `mutation {
+		createEvent(input: {title: "Launch", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z", status: "draft"}) { id }
+	}`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != transition.Error() {
+		t.Fatalf("expected %q, got %v", transition, resp.Errors)
+	}
+	if events.createReq.Status != structures.StatusDraft {
+		t.Fatalf("expected the draft status to be passed on, got %q", events.createReq.Status)
+	}
+}
+
+func TestGraphQLCreateEvent_InCalendar(t *testing.T) {
+	calendarID := uuid.New()
+	events := &mockEventService{createErr: structures.ErrForbidden}
+	c := NewGraphQLController(events, &batchAttendeeService{}, &batchResourceService{})
+
+	resp := execGraphQL(t, c, `mutation($cal: ID) {
+		createEvent(input: {title: "Retro", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z", calendarId: $cal}) { id }
+	}`, map[string]any{"cal": calendarID.String()})
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != structures.ErrForbidden.Error() {
+		t.Fatalf("expected %q, got %v", structures.ErrForbidden, resp.Errors)
+	}
+	if events.createReq.CalendarID != calendarID {
+		t.Fatalf("expected calendar %s in the request, got %s", calendarID, events.createReq.CalendarID)
+	}
+
+	resp = execGraphQL(t, c, `mutation {
+		createEvent(input: {title: "Retro", startTime: "2026-05-01T09:00:00Z", endTime: "2026-05-01T10:00:00Z", calendarId: "team"}) { id }
+	}`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != "calendarId must be a UUID" {
+		t.Fatalf("expected an invalid calendarId error, got %v", resp.Errors)
+	}
+}
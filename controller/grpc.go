@@ -0,0 +1,423 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"events/eventspb"
+	"events/services"
+	"events/structures"
+	"events/utils"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventsGRPCServer implements eventspb.EventsService on the same service,
+// validation and change feed as the REST handlers.
+type eventsGRPCServer struct {
+	eventspb.UnimplementedEventsServiceServer
+	svc    services.EventService
+	feed   services.ChangeFeedService
+	access services.EventAccess
+}
+
+func NewEventsGRPCServer(svc services.EventService, feed services.ChangeFeedService, access services.EventAccess) eventspb.EventsServiceServer {
+	return &eventsGRPCServer{svc: svc, feed: feed, access: access}
+}
+
+// NewGRPCServer returns a gRPC server exposing EventsService behind auth.
+func NewGRPCServer(auth *utils.TokenAuth, svc services.EventService, feed services.ChangeFeedService, access services.EventAccess) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcUnaryAuth(auth)),
+		grpc.StreamInterceptor(grpcStreamAuth(auth)),
+	)
+	eventspb.RegisterEventsServiceServer(s, NewEventsGRPCServer(svc, feed, access))
+	return s
+}
+
+func (s *eventsGRPCServer) CreateEvent(ctx context.Context, req *eventspb.CreateEventRequest) (*eventspb.Event, error) {
+	in, err := eventRequestFromProto(req.GetEvent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ev, err := eventFromRequest(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ev.ID = uuid.New()
+	ev.CreatedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	e, err := s.svc.CreateEvent(ctx, ev)
+	if err != nil {
+		return nil, grpcEventError("Create", err)
+	}
+	return eventToProto(e.In(nil)), nil
+}
+
+func (s *eventsGRPCServer) GetEvent(ctx context.Context, req *eventspb.GetEventRequest) (*eventspb.Event, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UUID")
+	}
+	loc, err := grpcTZ(req.GetTz())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	e, err := s.svc.GetEvent(ctx, id)
+	if err != nil {
+		return nil, grpcEventError("Get", err)
+	}
+	if e == nil {
+		return nil, status.Error(codes.NotFound, "event not found")
+	}
+	return eventToProto(e.In(loc)), nil
+}
+
+func (s *eventsGRPCServer) ListEvents(ctx context.Context, req *eventspb.ListEventsRequest) (*eventspb.ListEventsResponse, error) {
+	loc, err := grpcTZ(req.GetTz())
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range req.GetStatuses() {
+		if !structures.ValidEventStatus(st) {
+			return nil, status.Error(codes.InvalidArgument, "statuses must be one of "+strings.Join(structures.EventStatuses, ", "))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	events, err := s.svc.ListEvents(ctx)
+	if err != nil {
+		return nil, grpcEventError("List", err)
+	}
+	resp := &eventspb.ListEventsResponse{Events: make([]*eventspb.Event, 0, len(events))}
+	for _, e := range events {
+		if len(req.GetStatuses()) > 0 && !slices.Contains(req.GetStatuses(), e.Status) {
+			continue
+		}
+		resp.Events = append(resp.Events, eventToProto(e.In(loc)))
+	}
+	return resp, nil
+}
+
+func (s *eventsGRPCServer) UpdateEvent(ctx context.Context, req *eventspb.UpdateEventRequest) (*eventspb.Event, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UUID")
+	}
+	in, err := eventRequestFromProto(req.GetEvent())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ev, err := eventFromRequest(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ev.ID = id
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	e, err := s.svc.UpdateEvent(ctx, ev)
+	if err != nil {
+		return nil, grpcEventError("Update", err)
+	}
+	return eventToProto(e.In(nil)), nil
+}
+
+func (s *eventsGRPCServer) DeleteEvent(ctx context.Context, req *eventspb.DeleteEventRequest) (*eventspb.DeleteEventResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UUID")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.svc.DeleteEvent(ctx, id); err != nil {
+		return nil, grpcEventError("Delete", err)
+	}
+	return &eventspb.DeleteEventResponse{}, nil
+}
+
+// Watch follows the same replay-then-live protocol as GET /events/stream,
+// with after_id in place of Last-Event-ID.
+func (s *eventsGRPCServer) Watch(req *eventspb.WatchRequest, stream eventspb.EventsService_WatchServer) error {
+	filter, err := watchFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, watermark := s.feed.Subscribe(streamBuffer)
+	defer s.feed.Unsubscribe(sub)
+
+	ctx := stream.Context()
+	readable := services.NewReadableChanges(ctx, s.access)
+	send := func(m structures.OutboxMessage) error {
+		if !streamedType(m.Type) {
+			return nil
+		}
+		allowed, err := readable.Allows(m)
+		if err != nil {
+			log.Printf("Watch access error: %v", err)
+			return status.Error(codes.Internal, "failed to check access")
+		}
+		if !allowed {
+			return nil
+		}
+		var e structures.Event
+		if err := json.Unmarshal(m.Payload, &e); err != nil {
+			return err
+		}
+		if !filter.Matches(m.EventID, &e.StartTime, &e.EndTime) {
+			return nil
+		}
+		return stream.Send(&eventspb.Change{
+			Id:      m.ID,
+			Type:    m.Type,
+			EventId: m.EventID.String(),
+			Event:   eventToProto(e.In(nil)),
+		})
+	}
+
+	after := req.GetAfterId()
+	if req.AfterId != nil && after < watermark {
+		if err := s.feed.Replay(ctx, after, watermark, send); err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			log.Printf("Watch replay error: %v", err)
+			return status.Error(codes.Internal, "failed to replay changes")
+		}
+	}
+	last := max(after, watermark)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case m, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch fell behind or server is shutting down; resume with after_id")
+			}
+			if m.ID <= last {
+				continue
+			}
+			last = m.ID
+			if err := send(m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func watchFilter(req *eventspb.WatchRequest) (*structures.ChangeFilter, error) {
+	f := &structures.ChangeFilter{}
+	for _, s := range req.GetEventIds() {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.New("event_ids must be UUIDs")
+		}
+		f.EventIDs = append(f.EventIDs, id)
+	}
+	f.From = protoTime(req.GetFrom())
+	f.To = protoTime(req.GetTo())
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func grpcTZ(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := structures.LoadTimeZone(name)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "tz must be an IANA time zone such as Europe/Madrid")
+	}
+	return loc, nil
+}
+
+// grpcEventError is the gRPC counterpart of writeEventError.
+func grpcEventError(op string, err error) error {
+	var conflict *structures.BookingConflictError
+	var transition *structures.InvalidTransitionError
+	switch {
+	case errors.Is(err, structures.ErrEventNotFound), errors.Is(err, structures.ErrCalendarNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, structures.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.As(err, &transition):
+		return status.Error(codes.FailedPrecondition, transition.Error())
+	case errors.As(err, &conflict):
+		return status.Error(codes.Aborted, conflict.Error())
+	default:
+		log.Printf("%s error: %v", op, err)
+		return status.Error(codes.Internal, "failed to "+strings.ToLower(op)+" event")
+	}
+}
+
+func grpcUnaryAuth(auth *utils.TokenAuth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		actor, err := grpcAuthenticate(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(structures.WithActor(ctx, actor), req)
+	}
+}
+
+func grpcStreamAuth(auth *utils.TokenAuth) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		actor, err := grpcAuthenticate(ss.Context(), auth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, actorStream{ServerStream: ss, ctx: structures.WithActor(ss.Context(), actor)})
+	}
+}
+
+// actorStream carries the caller's actor in its context, for the
+// permission checks of the stream.
+type actorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s actorStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcAuthenticate applies the HTTP bearer check to the "authorization"
+// metadata, so the same tokens work on both servers, and returns the actor
+// changes are attributed to.
+func grpcAuthenticate(ctx context.Context, auth *utils.TokenAuth) (string, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			header = v[0]
+		}
+	}
+	actor, err := auth.Authenticate(header)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, err.Error())
+	}
+	return actor, nil
+}
+
+func eventRequestFromProto(in *eventspb.EventInput) (structures.CreateEventRequest, error) {
+	req := structures.CreateEventRequest{
+		Title:       in.GetTitle(),
+		Description: in.GetDescription(),
+		TimeZone:    in.GetTimeZone(),
+		AllDay:      in.GetAllDay(),
+		StartDate:   in.GetStartDate(),
+		EndDate:     in.GetEndDate(),
+		Tags:        in.GetTags(),
+		Location:    locationFromProto(in.GetLocation()),
+		Language:    in.GetLanguage(),
+		Status:      in.GetStatus(),
+	}
+	if in.Metadata != nil {
+		req.Metadata = in.Metadata.AsMap()
+	}
+	if t := protoTime(in.GetStartTime()); t != nil {
+		req.StartTime = *t
+	}
+	if t := protoTime(in.GetEndTime()); t != nil {
+		req.EndTime = *t
+	}
+	if in.Capacity != nil {
+		c := int(in.GetCapacity())
+		req.Capacity = &c
+	}
+	if id := in.GetCalendarId(); id != "" {
+		var err error
+		if req.CalendarID, err = uuid.Parse(id); err != nil {
+			return req, errors.New("calendar_id must be a UUID")
+		}
+	}
+	return req, nil
+}
+
+func eventToProto(e structures.Event) *eventspb.Event {
+	pb := &eventspb.Event{
+		Id:           e.ID.String(),
+		CalendarId:   e.CalendarID.String(),
+		Title:        e.Title,
+		Description:  e.Description,
+		StartTime:    timestamppb.New(e.StartTime),
+		EndTime:      timestamppb.New(e.EndTime),
+		TimeZone:     e.TimeZone,
+		AllDay:       e.AllDay,
+		StartDate:    e.StartDate,
+		EndDate:      e.EndDate,
+		CreatedAt:    timestamppb.New(e.CreatedAt),
+		Tags:         e.Tags,
+		Location:     locationToProto(e.Location),
+		Language:     e.Language,
+		Status:       e.Status,
+		StatusReason: e.StatusReason,
+		Attendees: &eventspb.AttendeeCounts{
+			Total:      int32(e.Attendees.Total),
+			Accepted:   int32(e.Attendees.Accepted),
+			Declined:   int32(e.Attendees.Declined),
+			Tentative:  int32(e.Attendees.Tentative),
+			Pending:    int32(e.Attendees.Pending),
+			Waitlisted: int32(e.Attendees.Waitlisted),
+		},
+	}
+	if e.Capacity != nil {
+		c := int32(*e.Capacity)
+		pb.Capacity = &c
+	}
+	// Stored metadata was decoded from JSON, so every value converts.
+	if e.Metadata != nil {
+		pb.Metadata, _ = structpb.NewStruct(e.Metadata)
+	}
+	return pb
+}
+
+func locationFromProto(in *eventspb.Location) *structures.Location {
+	if in == nil {
+		return nil
+	}
+	return &structures.Location{Name: in.GetName(), Address: in.GetAddress(), Latitude: in.Lat, Longitude: in.Lng}
+}
+
+func locationToProto(l *structures.Location) *eventspb.Location {
+	if l == nil {
+		return nil
+	}
+	return &eventspb.Location{Name: l.Name, Address: l.Address, Lat: l.Latitude, Lng: l.Longitude}
+}
+
+// protoTime converts an optional timestamp; unset stays nil rather than
+// becoming the Unix epoch.
+func protoTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,346 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"events/eventspb"
+	"events/services"
+	"events/structures"
+	"events/utils"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newGRPCClient serves a real gRPC server over an in-memory listener.
+func newGRPCClient(t *testing.T, auth *utils.TokenAuth, svc services.EventService, feed services.ChangeFeedService) eventspb.EventsServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(auth, svc, feed, fakeAccess{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return eventspb.NewEventsServiceClient(conn)
+}
+
+func TestGRPCCreateEvent_SharesRESTValidation(t *testing.T) {
+	mockSvc := &mockEventService{}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), mockSvc, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	_, err := client.CreateEvent(context.Background(), &eventspb.CreateEventRequest{Event: &eventspb.EventInput{
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+	}})
+	if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != "title is required" {
+		t.Fatalf("expected InvalidArgument \"title is required\", got %v", err)
+	}
+	if mockSvc.createCalled {
+		t.Fatal("expected CreateEvent not to be called")
+	}
+
+	capacity := int32(10)
+	mockSvc.createResp = &structures.Event{ID: uuid.New(), Title: "Launch", StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC"}
+	e, err := client.CreateEvent(context.Background(), &eventspb.CreateEventRequest{Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Capacity:  &capacity,
+	}})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	if e.GetTitle() != "Launch" || *mockSvc.createReq.Capacity != 10 || mockSvc.createReq.TimeZone != "UTC" {
+		t.Fatalf("unexpected event %v from request %+v", e, mockSvc.createReq)
+	}
+}
+
+func TestGRPCGetEvent_Errors(t *testing.T) {
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), &mockEventService{}, &fakeFeed{})
+
+	_, err := client.GetEvent(context.Background(), &eventspb.GetEventRequest{Id: "not-a-uuid"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	_, err = client.GetEvent(context.Background(), &eventspb.GetEventRequest{Id: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGRPCUpdateEvent_ReadOnlyCalendar(t *testing.T) {
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), &mockEventService{updateErr: structures.ErrForbidden}, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	_, err := client.UpdateEvent(context.Background(), &eventspb.UpdateEventRequest{Id: uuid.NewString(), Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+	}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestGRPC_RequiresToken(t *testing.T) {
+	mockSvc := &mockEventService{}
+	client := newGRPCClient(t, utils.NewTokenAuth([]string{"secret-one"}), mockSvc, &fakeFeed{})
+
+	_, err := client.ListEvents(context.Background(), &eventspb.ListEventsRequest{})
+	if status.Code(err) != codes.Unauthenticated || mockSvc.listCalled {
+		t.Fatalf("expected Unauthenticated without a token, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret-one")
+	if _, err := client.ListEvents(ctx, &eventspb.ListEventsRequest{}); err != nil {
+		t.Fatalf("ListEvents with a valid token returned error: %v", err)
+	}
+}
+
+func TestGRPCWatch_ResumesAndFilters(t *testing.T) {
+	watched := uuid.New()
+	event := func(id uuid.UUID) json.RawMessage {
+		b, _ := json.Marshal(structures.Event{ID: id, Title: "x", StartTime: time.Now(), EndTime: time.Now().Add(time.Hour), TimeZone: "UTC"})
+		return b
+	}
+	feed := &fakeFeed{
+		sub:       &services.FeedSubscription{C: make(chan structures.OutboxMessage, 4)},
+		watermark: 3,
+		history: []structures.OutboxMessage{
+			{ID: 1, Type: structures.EventCreated, EventID: watched, Payload: event(watched)},
+			{ID: 2, Type: structures.AttendeeAdded, EventID: watched, Payload: json.RawMessage(`{}`)},
+			{ID: 3, Type: structures.EventCreated, EventID: uuid.New(), Payload: event(uuid.New())},
+		},
+	}
+	feed.sub.C <- structures.OutboxMessage{ID: 3, Type: structures.EventCreated, EventID: watched, Payload: event(watched)}
+	feed.sub.C <- structures.OutboxMessage{ID: 4, Type: structures.EventDeleted, EventID: watched, Payload: event(watched)}
+
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), &mockEventService{}, feed)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	after := int64(0)
+	stream, err := client.Watch(ctx, &eventspb.WatchRequest{AfterId: &after, EventIds: []string{watched.String()}})
+	if err != nil {
+		t.Fatalf("Watch returned error: %v", err)
+	}
+	var got []int64
+	for len(got) < 2 {
+		c, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv returned error after %v: %v", got, err)
+		}
+		if c.GetEventId() != watched.String() {
+			t.Fatalf("unexpected change for event %s", c.GetEventId())
+		}
+		got = append(got, c.GetId())
+	}
+	if got[0] != 1 || got[1] != 4 {
+		t.Fatalf("expected changes [1 4], got %v", got)
+	}
+}
+
+// echoEventService returns the replacement it is given, like a store that
+// saved it.
+type echoEventService struct {
+	mockEventService
+}
+
+func (m *echoEventService) UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	m.updateReq = e
+	return e, nil
+}
+
+// echoCreateEventService returns the event it is asked to create.
+type echoCreateEventService struct {
+	mockEventService
+}
+
+func (m *echoCreateEventService) CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	m.createReq = e
+	return e, nil
+}
+
+func TestGRPCUpdateEvent_KeepsTagsAndMetadata(t *testing.T) {
+	svc := &echoEventService{}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), svc, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	meta, err := structpb.NewStruct(map[string]any{"room": "4B", "seats": 12.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := client.UpdateEvent(context.Background(), &eventspb.UpdateEventRequest{Id: uuid.NewString(), Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Tags:      []string{"Planning", "q2"},
+		Metadata:  meta,
+	}})
+	if err != nil {
+		t.Fatalf("UpdateEvent returned error: %v", err)
+	}
+	if got := svc.updateReq; !slices.Equal(got.Tags, []string{"planning", "q2"}) || got.Metadata["room"] != "4B" {
+		t.Fatalf("expected tags and metadata in the replacement, got %+v", got)
+	}
+	if !slices.Equal(e.GetTags(), []string{"planning", "q2"}) || e.GetMetadata().AsMap()["seats"] != 12.0 {
+		t.Fatalf("expected tags and metadata in the response, got %v", e)
+	}
+}
+
+func TestGRPCUpdateEvent_KeepsLocation(t *testing.T) {
+	svc := &echoEventService{}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), svc, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	lat, lng := 52.52, 13.405
+	e, err := client.UpdateEvent(context.Background(), &eventspb.UpdateEventRequest{Id: uuid.NewString(), Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Location:  &eventspb.Location{Name: "HQ", Lat: &lat, Lng: &lng},
+	}})
+	if err != nil {
+		t.Fatalf("UpdateEvent returned error: %v", err)
+	}
+	if l := svc.updateReq.Location; l == nil || l.Name != "HQ" || l.Latitude == nil || *l.Latitude != lat {
+		t.Fatalf("expected the location in the replacement, got %+v", l)
+	}
+	if l := e.GetLocation(); l.GetName() != "HQ" || l.GetLat() != lat || l.GetLng() != lng {
+		t.Fatalf("expected the location in the response, got %v", l)
+	}
+
+	_, err = client.UpdateEvent(context.Background(), &eventspb.UpdateEventRequest{Id: uuid.NewString(), Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Location:  &eventspb.Location{Lat: &lat},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a latitude without longitude, got %v", err)
+	}
+}
+
+func TestGRPCUpdateEvent_KeepsLanguage(t *testing.T) {
+	svc := &echoEventService{}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), svc, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	e, err := client.UpdateEvent(context.Background(), &eventspb.UpdateEventRequest{Id: uuid.NewString(), Event: &eventspb.EventInput{
+		Title:     "Auftakt",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Language:  "german",
+	}})
+	if err != nil {
+		t.Fatalf("UpdateEvent returned error: %v", err)
+	}
+	if svc.updateReq.Language != "german" || e.GetLanguage() != "german" {
+		t.Fatalf("expected language german, got %q in the replacement and %q in the response", svc.updateReq.Language, e.GetLanguage())
+	}
+}
+
+func TestGRPCListEvents_FiltersByStatus(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	cancelled := structures.Event{ID: uuid.New(), Title: "Offsite", StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC",
+		Status: structures.StatusCancelled, StatusReason: "venue closed"}
+	svc := &mockEventService{listResp: []structures.Event{
+		{ID: uuid.New(), Title: "Launch", StartTime: start, EndTime: start.Add(time.Hour), TimeZone: "UTC", Status: structures.StatusScheduled},
+		cancelled,
+	}}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), svc, &fakeFeed{})
+
+	resp, err := client.ListEvents(context.Background(), &eventspb.ListEventsRequest{Statuses: []string{structures.StatusCancelled}})
+	if err != nil {
+		t.Fatalf("ListEvents returned error: %v", err)
+	}
+	if len(resp.GetEvents()) != 1 {
+		t.Fatalf("expected only the cancelled event, got %v", resp.GetEvents())
+	}
+	if e := resp.GetEvents()[0]; e.GetId() != cancelled.ID.String() || e.GetStatus() != structures.StatusCancelled || e.GetStatusReason() != "venue closed" {
+		t.Fatalf("expected the status and its reason, got %v", e)
+	}
+
+	_, err = client.ListEvents(context.Background(), &eventspb.ListEventsRequest{Statuses: []string{"archived"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an unknown status, got %v", err)
+	}
+}
+
+func TestGRPCCreateEvent_InvalidTransition(t *testing.T) {
+	svc := &mockEventService{createErr: &structures.InvalidTransitionError{From: "new", To: structures.StatusDraft}}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), svc, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	_, err := client.CreateEvent(context.Background(), &eventspb.CreateEventRequest{Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Status:    structures.StatusDraft,
+	}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+	if svc.createReq.Status != structures.StatusDraft {
+		t.Fatalf("expected the draft status to be passed on, got %q", svc.createReq.Status)
+	}
+
+	_, err = client.CreateEvent(context.Background(), &eventspb.CreateEventRequest{Event: &eventspb.EventInput{
+		Title:     "Launch",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+		Status:    structures.StatusCompleted,
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a later status, got %v", err)
+	}
+}
+
+func TestGRPCCreateEvent_InCalendar(t *testing.T) {
+	calendarID := uuid.New()
+	svc := &echoCreateEventService{}
+	client := newGRPCClient(t, utils.NewTokenAuth(nil), svc, &fakeFeed{})
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	e, err := client.CreateEvent(context.Background(), &eventspb.CreateEventRequest{Event: &eventspb.EventInput{
+		Title:      "Retro",
+		StartTime:  timestamppb.New(start),
+		EndTime:    timestamppb.New(start.Add(time.Hour)),
+		CalendarId: calendarID.String(),
+	}})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	if svc.createReq.CalendarID != calendarID || e.GetCalendarId() != calendarID.String() {
+		t.Fatalf("expected calendar %s, got %s in the request and %q in the response", calendarID, svc.createReq.CalendarID, e.GetCalendarId())
+	}
+
+	_, err = client.CreateEvent(context.Background(), &eventspb.CreateEventRequest{Event: &eventspb.EventInput{
+		Title:      "Retro",
+		StartTime:  timestamppb.New(start),
+		EndTime:    timestamppb.New(start.Add(time.Hour)),
+		CalendarId: "team",
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a calendar_id that is not a UUID, got %v", err)
+	}
+}
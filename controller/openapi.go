@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+)
+
+type OpenAPIController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type openAPIController struct {
+	spec []byte
+}
+
+// NewOpenAPIController serves spec, the API description in YAML.
+func NewOpenAPIController(spec []byte) OpenAPIController {
+	return &openAPIController{spec: spec}
+}
+
+func (c *openAPIController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /openapi.yaml", c.handleSpec)
+}
+
+func (c *openAPIController) handleSpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(c.spec)
+}
@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"events/docs"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestOpenAPIController_ServesSpec(t *testing.T) {
+	mux := http.NewServeMux()
+	NewOpenAPIController(docs.OpenAPI).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/yaml") {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+	if w.Body.String() != string(docs.OpenAPI) {
+		t.Errorf("body is not docs/openapi.yaml")
+	}
+}
+
+// TestOpenAPISpec_MatchesRoutes reads the controllers' source and fails
+// when a registered route, or a status code its handler can write, is
+// missing from docs/openapi.yaml, or when the spec documents a route that
+// is not registered.
+func TestOpenAPISpec_MatchesRoutes(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(docs.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	// Custom methods such as /events/{id}:restore share one registered
+	// route, so a route may stand for several operations.
+	specOps := make(map[string][]*openapi3.Operation)
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			key := method + " " + normalizeRoutePath(path)
+			specOps[key] = append(specOps[key], op)
+		}
+	}
+
+	routes := scanRoutes(t)
+	if len(routes) == 0 {
+		t.Fatal("no routes found in the controllers")
+	}
+	for _, rt := range routes {
+		key := rt.method + " " + normalizeRoutePath(rt.path)
+		ops, ok := specOps[key]
+		if !ok {
+			t.Errorf("%s %s is registered but not in the spec", rt.method, rt.path)
+			continue
+		}
+		delete(specOps, key)
+		for _, code := range rt.statuses {
+			if !slices.ContainsFunc(ops, func(op *openapi3.Operation) bool { return op.Responses.Get(code) != nil }) {
+				t.Errorf("%s %s can respond %d, which the spec does not list", rt.method, rt.path, code)
+			}
+		}
+	}
+	for key := range specOps {
+		t.Errorf("%s is in the spec but not registered", key)
+	}
+}
+
+var (
+	pathParam   = regexp.MustCompile(`\{[^}]*\}(:\w+)?`)
+	errorWriter = regexp.MustCompile(`^write\w*Error$`)
+)
+
+// normalizeRoutePath drops parameter names, which differ between the mux
+// patterns and the spec, and custom method suffixes, which the mux cannot
+// match.
+func normalizeRoutePath(p string) string {
+	return pathParam.ReplaceAllString(p, "{}")
+}
+
+type scannedRoute struct {
+	method, path string
+	statuses     []int
+}
+
+// scanRoutes finds every mux.HandleFunc("METHOD /path", c.handler) call in
+// a RegisterRoutes method, and the http.Status constants reachable from
+// the handler through calls to functions and methods of this package.
+// The write*Error helpers are not followed: which of their cases a handler
+// can hit depends on the errors its service returns, which the handler
+// tests cover. 405 is left out too: the method in the pattern already
+// keeps other methods away from the handler.
+func scanRoutes(t *testing.T) []scannedRoute {
+	t.Helper()
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs := make(map[string]*ast.FuncDecl)
+	var registrars []*ast.FuncDecl
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			funcs[funcKey(recvType(fn), fn.Name.Name)] = fn
+			if fn.Name.Name == "RegisterRoutes" {
+				registrars = append(registrars, fn)
+			}
+		}
+	}
+
+	statusCodes := make(map[string]int)
+	for code := 100; code < 600; code++ {
+		if text := http.StatusText(code); text != "" {
+			statusCodes["Status"+strings.NewReplacer(" ", "", "-", "", "'", "").Replace(text)] = code
+		}
+	}
+
+	var collect func(recv string, fn *ast.FuncDecl, seen map[*ast.FuncDecl]bool, out map[int]bool)
+	collect = func(recv string, fn *ast.FuncDecl, seen map[*ast.FuncDecl]bool, out map[int]bool) {
+		if seen[fn] {
+			return
+		}
+		seen[fn] = true
+		recvName := ""
+		if fn.Recv != nil && len(fn.Recv.List[0].Names) > 0 {
+			recvName = fn.Recv.List[0].Names[0].Name
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if pkg, ok := n.X.(*ast.Ident); ok && pkg.Name == "http" && strings.HasPrefix(n.Sel.Name, "Status") {
+					code, ok := statusCodes[n.Sel.Name]
+					if !ok {
+						t.Fatalf("unknown status constant http.%s", n.Sel.Name)
+					}
+					out[code] = true
+				}
+			case *ast.CallExpr:
+				switch fun := n.Fun.(type) {
+				case *ast.Ident:
+					if errorWriter.MatchString(fun.Name) {
+						return true
+					}
+					if callee, ok := funcs[funcKey("", fun.Name)]; ok {
+						collect("", callee, seen, out)
+					}
+				case *ast.SelectorExpr:
+					if x, ok := fun.X.(*ast.Ident); ok && recvName != "" && x.Name == recvName {
+						if callee, ok := funcs[funcKey(recv, fun.Sel.Name)]; ok {
+							collect(recv, callee, seen, out)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	var routes []scannedRoute
+	for _, reg := range registrars {
+		recv := recvType(reg)
+		ast.Inspect(reg.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "HandleFunc" {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok {
+				return true
+			}
+			pattern, _ := strconv.Unquote(lit.Value)
+			method, path, _ := strings.Cut(pattern, " ")
+			handler, ok := call.Args[1].(*ast.SelectorExpr)
+			if !ok {
+				t.Fatalf("%s: handler is not a method value", pattern)
+			}
+			fn, ok := funcs[funcKey(recv, handler.Sel.Name)]
+			if !ok {
+				t.Fatalf("%s: handler %s not found", pattern, handler.Sel.Name)
+			}
+
+			codes := make(map[int]bool)
+			collect(recv, fn, make(map[*ast.FuncDecl]bool), codes)
+			delete(codes, http.StatusMethodNotAllowed)
+			rt := scannedRoute{method: method, path: path}
+			for code := range codes {
+				rt.statuses = append(rt.statuses, code)
+			}
+			slices.Sort(rt.statuses)
+			routes = append(routes, rt)
+			return true
+		})
+	}
+	return routes
+}
+
+func recvType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil {
+		return ""
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func funcKey(recv, name string) string {
+	return recv + "." + name
+}
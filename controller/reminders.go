@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type ReminderController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type reminderController struct {
+	svc services.ReminderService
+}
+
+func NewReminderController(svc services.ReminderService) ReminderController {
+	return &reminderController{svc: svc}
+}
+
+func (c *reminderController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /events/{id}/reminders", c.handleCreateReminder)
+	mux.HandleFunc("GET /events/{id}/reminders", c.handleListReminders)
+	mux.HandleFunc("DELETE /events/{id}/reminders/{reminder_id}", c.handleDeleteReminder)
+}
+
+func (c *reminderController) handleCreateReminder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	var req structures.CreateReminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	// Validation
+	if req.MinutesBefore < 0 || req.MinutesBefore > structures.MaxReminderMinutes {
+		http.Error(w, "minutes_before must be between 0 and "+strconv.Itoa(structures.MaxReminderMinutes), http.StatusBadRequest)
+		return
+	}
+	switch req.Channel {
+	case structures.ReminderEmail:
+		if _, err := mail.ParseAddress(req.Target); err != nil || req.Target == "" {
+			http.Error(w, "target must be a valid email for the email channel", http.StatusBadRequest)
+			return
+		}
+	case structures.ReminderWebhook:
+		u, err := url.Parse(req.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			http.Error(w, "target must be an absolute http or https URL for the webhook channel", http.StatusBadRequest)
+			return
+		}
+	case structures.ReminderLog:
+		if req.Target != "" {
+			http.Error(w, "target is not used by the log channel", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "channel must be one of email, webhook, log", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	reminder, err := c.svc.CreateReminder(ctx, &structures.Reminder{
+		ID:            uuid.New(),
+		EventID:       eventID,
+		MinutesBefore: req.MinutesBefore,
+		Channel:       req.Channel,
+		Target:        req.Target,
+		CreatedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		writeReminderError(w, "Create reminder", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, reminder)
+}
+
+func (c *reminderController) handleListReminders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	reminders, err := c.svc.ListReminders(ctx, eventID)
+	if err != nil {
+		writeReminderError(w, "List reminders", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reminders)
+}
+
+func (c *reminderController) handleDeleteReminder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "reminder_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.DeleteReminder(ctx, eventID, id); err != nil {
+		writeReminderError(w, "Delete reminder", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeReminderError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, structures.ErrEventNotFound), errors.Is(err, structures.ErrReminderNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to process reminder request", http.StatusInternalServerError)
+	}
+}
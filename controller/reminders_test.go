@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockReminderService struct {
+	createCalled bool
+	createReq    *structures.Reminder
+	createErr    error
+
+	listResp []structures.Reminder
+	listErr  error
+
+	deleteID  uuid.UUID
+	deleteErr error
+}
+
+func (m *mockReminderService) CreateReminder(ctx context.Context, r *structures.Reminder) (*structures.Reminder, error) {
+	m.createCalled = true
+	m.createReq = r
+	return r, m.createErr
+}
+
+func (m *mockReminderService) ListReminders(ctx context.Context, eventID uuid.UUID) ([]structures.Reminder, error) {
+	return m.listResp, m.listErr
+}
+
+func (m *mockReminderService) DeleteReminder(ctx context.Context, eventID, id uuid.UUID) error {
+	m.deleteID = id
+	return m.deleteErr
+}
+
+// --- tests ---
+
+func TestHandleCreateReminder_Success(t *testing.T) {
+	eventID := uuid.New()
+	mockSvc := &mockReminderService{}
+	mux := http.NewServeMux()
+	NewReminderController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.CreateReminderRequest{MinutesBefore: 30, Channel: "email", Target: "ana@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/reminders", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if !mockSvc.createCalled || mockSvc.createReq.EventID != eventID || mockSvc.createReq.MinutesBefore != 30 {
+		t.Fatalf("expected CreateReminder to be called for event %v, got %+v", eventID, mockSvc.createReq)
+	}
+}
+
+func TestHandleCreateReminder_ValidationErrors(t *testing.T) {
+	cases := map[string]structures.CreateReminderRequest{
+		"negative minutes": {MinutesBefore: -1, Channel: "log"},
+		"too far ahead":    {MinutesBefore: structures.MaxReminderMinutes + 1, Channel: "log"},
+		"unknown channel":  {MinutesBefore: 10, Channel: "sms", Target: "+15550100"},
+		"bad email":        {MinutesBefore: 10, Channel: "email", Target: "not-an-email"},
+		"bad webhook url":  {MinutesBefore: 10, Channel: "webhook", Target: "ftp://example.com"},
+		"log with target":  {MinutesBefore: 10, Channel: "log", Target: "ana@example.com"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockSvc := &mockReminderService{}
+			mux := http.NewServeMux()
+			NewReminderController(mockSvc).RegisterRoutes(mux)
+
+			body, _ := json.Marshal(tc)
+			req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/reminders", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if mockSvc.createCalled {
+				t.Fatal("expected CreateReminder not to be called")
+			}
+		})
+	}
+}
+
+func TestHandleListReminders_EventNotFound(t *testing.T) {
+	mockSvc := &mockReminderService{listErr: structures.ErrEventNotFound}
+	mux := http.NewServeMux()
+	NewReminderController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/"+uuid.NewString()+"/reminders", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHandleDeleteReminder_NotFound(t *testing.T) {
+	id := uuid.New()
+	mockSvc := &mockReminderService{deleteErr: structures.ErrReminderNotFound}
+	mux := http.NewServeMux()
+	NewReminderController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+uuid.NewString()+"/reminders/"+id.String(), nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if mockSvc.deleteID != id {
+		t.Fatalf("expected DeleteReminder for %v, got %v", id, mockSvc.deleteID)
+	}
+}
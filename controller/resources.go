@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type ResourceController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type resourceController struct {
+	svc services.ResourceService
+}
+
+func NewResourceController(svc services.ResourceService) ResourceController {
+	return &resourceController{svc: svc}
+}
+
+func (c *resourceController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /resources", c.handleCreateResource)
+	mux.HandleFunc("GET /resources", c.handleListResources)
+	mux.HandleFunc("GET /resources/{id}", c.handleGetResource)
+	mux.HandleFunc("POST /events/{id}/resources", c.handleBookResource)
+	mux.HandleFunc("GET /events/{id}/resources", c.handleListEventResources)
+	mux.HandleFunc("DELETE /events/{id}/resources/{resource_id}", c.handleReleaseResource)
+}
+
+func (c *resourceController) handleCreateResource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req structures.CreateResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	// Validation
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Name) > 100 {
+		http.Error(w, "name must be at most 100 characters", http.StatusBadRequest)
+		return
+	}
+	if !structures.ValidResourceKind(req.Kind) {
+		http.Error(w, "kind must be one of room, equipment", http.StatusBadRequest)
+		return
+	}
+	if req.WorkingHours != nil {
+		if err := req.WorkingHours.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.svc.CreateResource(ctx, &structures.Resource{
+		ID:           uuid.New(),
+		Name:         req.Name,
+		Kind:         req.Kind,
+		WorkingHours: req.WorkingHours,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		writeResourceError(w, "Create resource", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func (c *resourceController) handleListResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resources, err := c.svc.ListResources(ctx)
+	if err != nil {
+		writeResourceError(w, "List resources", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resources)
+}
+
+func (c *resourceController) handleGetResource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.svc.GetResource(ctx, id)
+	if err != nil {
+		writeResourceError(w, "Get resource", err)
+		return
+	}
+	if res == nil {
+		http.Error(w, "resource not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (c *resourceController) handleBookResource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	var req structures.BookResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if req.ResourceID == uuid.Nil {
+		http.Error(w, "resource_id is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	b, err := c.svc.BookResource(ctx, eventID, req.ResourceID)
+	if err != nil {
+		writeResourceError(w, "Book resource", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, b)
+}
+
+func (c *resourceController) handleListEventResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resources, err := c.svc.ListEventResources(ctx, eventID)
+	if err != nil {
+		writeResourceError(w, "List event resources", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resources)
+}
+
+func (c *resourceController) handleReleaseResource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	eventID, ok := parseEventID(w, r)
+	if !ok {
+		return
+	}
+	resourceID, ok := parseUUIDPathValue(w, r, "resource_id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.ReleaseResource(ctx, eventID, resourceID); err != nil {
+		writeResourceError(w, "Release resource", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeResourceError(w http.ResponseWriter, op string, err error) {
+	var conflict *structures.BookingConflictError
+	switch {
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, structures.BookingConflictResponse{
+			Error:               conflict.Error(),
+			ResourceID:          conflict.ResourceID,
+			ConflictingEventIDs: conflict.EventIDs,
+		})
+	case errors.Is(err, structures.ErrEventNotFound),
+		errors.Is(err, structures.ErrResourceNotFound),
+		errors.Is(err, structures.ErrBookingNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, structures.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, structures.ErrResourceExists), errors.Is(err, structures.ErrBookingExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to process resource request", http.StatusInternalServerError)
+	}
+}
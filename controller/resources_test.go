@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockResourceService struct {
+	createCalled bool
+	createResp   *structures.Resource
+	createErr    error
+
+	bookEventID    uuid.UUID
+	bookResourceID uuid.UUID
+	bookResp       *structures.Booking
+	bookErr        error
+
+	releaseErr error
+}
+
+func (m *mockResourceService) CreateResource(ctx context.Context, r *structures.Resource) (*structures.Resource, error) {
+	m.createCalled = true
+	if m.createResp == nil {
+		return r, m.createErr
+	}
+	return m.createResp, m.createErr
+}
+
+func (m *mockResourceService) ListResources(ctx context.Context) ([]structures.Resource, error) {
+	return nil, nil
+}
+
+func (m *mockResourceService) GetResource(ctx context.Context, id uuid.UUID) (*structures.Resource, error) {
+	return nil, nil
+}
+
+func (m *mockResourceService) BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error) {
+	m.bookEventID = eventID
+	m.bookResourceID = resourceID
+	return m.bookResp, m.bookErr
+}
+
+func (m *mockResourceService) ListEventResources(ctx context.Context, eventID uuid.UUID) ([]structures.Resource, error) {
+	return nil, nil
+}
+
+func (m *mockResourceService) ListResourcesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Resource, error) {
+	return nil, nil
+}
+
+func (m *mockResourceService) ReleaseResource(ctx context.Context, eventID, resourceID uuid.UUID) error {
+	return m.releaseErr
+}
+
+// --- tests ---
+
+func TestHandleCreateResource_InvalidKind(t *testing.T) {
+	mockSvc := &mockResourceService{}
+	mux := http.NewServeMux()
+	NewResourceController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.CreateResourceRequest{Name: "Room A", Kind: "spaceship"})
+	req := httptest.NewRequest(http.MethodPost, "/resources", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if mockSvc.createCalled {
+		t.Fatalf("service should not be called on invalid kind")
+	}
+}
+
+func TestHandleCreateResource_Success(t *testing.T) {
+	mockSvc := &mockResourceService{}
+	mux := http.NewServeMux()
+	NewResourceController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.CreateResourceRequest{Name: "Room A", Kind: structures.ResourceRoom})
+	req := httptest.NewRequest(http.MethodPost, "/resources", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	var got structures.Resource
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.ID == uuid.Nil || got.Name != "Room A" {
+		t.Fatalf("unexpected resource: %+v", got)
+	}
+}
+
+func TestHandleBookResource_Success(t *testing.T) {
+	eventID, resourceID := uuid.New(), uuid.New()
+	mockSvc := &mockResourceService{
+		bookResp: &structures.Booking{EventID: eventID, ResourceID: resourceID},
+	}
+	mux := http.NewServeMux()
+	NewResourceController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.BookResourceRequest{ResourceID: resourceID})
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID.String()+"/resources", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if mockSvc.bookEventID != eventID || mockSvc.bookResourceID != resourceID {
+		t.Fatalf("service called with wrong ids: %v %v", mockSvc.bookEventID, mockSvc.bookResourceID)
+	}
+}
+
+func TestHandleBookResource_Conflict(t *testing.T) {
+	resourceID := uuid.New()
+	clashing := []uuid.UUID{uuid.New(), uuid.New()}
+	mockSvc := &mockResourceService{
+		bookErr: &structures.BookingConflictError{ResourceID: resourceID, EventIDs: clashing},
+	}
+	mux := http.NewServeMux()
+	NewResourceController(mockSvc).RegisterRoutes(mux)
+
+	body, _ := json.Marshal(structures.BookResourceRequest{ResourceID: resourceID})
+	req := httptest.NewRequest(http.MethodPost, "/events/"+uuid.NewString()+"/resources", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	var got structures.BookingConflictResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got.ConflictingEventIDs) != 2 || got.ConflictingEventIDs[0] != clashing[0] {
+		t.Fatalf("unexpected conflicting ids: %v", got.ConflictingEventIDs)
+	}
+}
+
+func TestHandleReleaseResource_NotFound(t *testing.T) {
+	mockSvc := &mockResourceService{releaseErr: structures.ErrBookingNotFound}
+	mux := http.NewServeMux()
+	NewResourceController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/events/"+uuid.NewString()+"/resources/"+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
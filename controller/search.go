@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"events/services"
+	"events/structures"
+)
+
+type SearchController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type searchController struct {
+	svc services.EventService
+}
+
+func NewSearchController(svc services.EventService) SearchController {
+	return &searchController{svc: svc}
+}
+
+func (c *searchController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /search", c.handleSearch)
+}
+
+// handleSearch returns one page of the events matching q, best match
+// first, with a Link header when another page follows. lang restricts the
+// search to events in that language.
+func (c *searchController) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	loc, ok := parseTZ(w, r)
+	if !ok {
+		return
+	}
+	values := r.URL.Query()
+	q := structures.SearchQuery{
+		Text:     strings.TrimSpace(values.Get("q")),
+		Language: values.Get("lang"),
+		Limit:    structures.DefaultEventPageSize,
+	}
+	if q.Text == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+	if len(q.Text) > structures.MaxSearchTextLength {
+		http.Error(w, "q must be at most "+strconv.Itoa(structures.MaxSearchTextLength)+" characters", http.StatusBadRequest)
+		return
+	}
+	if q.Language != "" && !structures.ValidSearchLanguage(q.Language) {
+		http.Error(w, "lang must be one of "+strings.Join(structures.SearchLanguages, ", "), http.StatusBadRequest)
+		return
+	}
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > structures.MaxEventPageSize {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(structures.MaxEventPageSize), http.StatusBadRequest)
+			return
+		}
+		q.Limit = n
+	}
+	if v := values.Get("cursor"); v != "" {
+		cursor, err := structures.DecodeSearchCursor(v)
+		if err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		q.After = cursor
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := c.svc.SearchEvents(ctx, q)
+	if err != nil {
+		log.Printf("Search error: %v", err)
+		http.Error(w, "failed to search events", http.StatusInternalServerError)
+		return
+	}
+	for i := range page.Results {
+		page.Results[i].Event = page.Results[i].Event.In(loc)
+	}
+	setNextLink(w, r, page.NextCursor)
+	writeJSON(w, http.StatusOK, page.Results)
+}
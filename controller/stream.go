@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"events/services"
+	"events/structures"
+)
+
+const (
+	streamBuffer    = 256
+	streamHeartbeat = 15 * time.Second
+	streamRetryMS   = 3000
+)
+
+type StreamController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type streamController struct {
+	feed      services.ChangeFeedService
+	access    services.EventAccess
+	heartbeat time.Duration
+}
+
+func NewStreamController(feed services.ChangeFeedService, access services.EventAccess) StreamController {
+	return &streamController{feed: feed, access: access, heartbeat: streamHeartbeat}
+}
+
+func (c *streamController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /events/stream", c.handleStream)
+}
+
+// handleStream sends event.created, event.updated and event.deleted changes
+// of the events the caller can read as Server-Sent Events. The SSE id is
+// the outbox message id, so a client reconnecting with Last-Event-ID gets
+// every change it missed.
+func (c *streamController) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query parameter
+	// lets a fresh page resume from an id it stored itself.
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var after int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			http.Error(w, "Last-Event-ID must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		after = id
+	}
+
+	sub, watermark := c.feed.Subscribe(streamBuffer)
+	defer c.feed.Unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	// The server's WriteTimeout would otherwise cut the stream off.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMS)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ctx := r.Context()
+	readable := services.NewReadableChanges(ctx, c.access)
+	send := func(m structures.OutboxMessage) error {
+		if !streamedType(m.Type) {
+			return nil
+		}
+		allowed, err := readable.Allows(m)
+		if err != nil {
+			log.Printf("Stream access error: %v", err)
+			return err
+		}
+		if !allowed {
+			return nil
+		}
+		if err := writeSSE(w, m); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if lastEventID != "" && after < watermark {
+		if err := c.feed.Replay(ctx, after, watermark, send); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Stream replay error: %v", err)
+			}
+			return
+		}
+	}
+	last := max(after, watermark)
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case m, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind, or shutting down; the
+				// client reconnects with Last-Event-ID.
+				return
+			}
+			if m.ID <= last {
+				continue
+			}
+			last = m.ID
+			if err := send(m); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func streamedType(t string) bool {
+	switch t {
+	case structures.EventCreated, structures.EventUpdated, structures.EventDeleted:
+		return true
+	}
+	return false
+}
+
+func writeSSE(w http.ResponseWriter, m structures.OutboxMessage) error {
+	var data bytes.Buffer
+	if err := json.Compact(&data, m.Payload); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", m.ID, m.Type, data.Bytes())
+	return err
+}
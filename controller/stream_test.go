@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- fake feed ---
+
+type fakeFeed struct {
+	sub       *services.FeedSubscription
+	watermark int64
+	history   []structures.OutboxMessage
+}
+
+func (f *fakeFeed) Subscribe(buffer int) (*services.FeedSubscription, int64) {
+	return f.sub, f.watermark
+}
+
+func (f *fakeFeed) Unsubscribe(sub *services.FeedSubscription) {}
+
+func (f *fakeFeed) Replay(ctx context.Context, afterID, upTo int64, fn func(structures.OutboxMessage) error) error {
+	for _, m := range f.history {
+		if m.ID > afterID && m.ID <= upTo {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fakeAccess lets the caller read the calendars in readable, or only the
+// default one when it is nil. Events it has not seen are in the default
+// calendar.
+type fakeAccess struct {
+	readable []uuid.UUID
+}
+
+func (f fakeAccess) AuthorizeCalendar(ctx context.Context, calendarID uuid.UUID, want string) error {
+	return nil
+}
+
+func (f fakeAccess) AuthorizeEvent(ctx context.Context, eventID uuid.UUID, want string) error {
+	return nil
+}
+
+func (f fakeAccess) ReadableCalendars(ctx context.Context) ([]uuid.UUID, error) {
+	if f.readable == nil {
+		return []uuid.UUID{structures.DefaultCalendarID}, nil
+	}
+	return f.readable, nil
+}
+
+func (f fakeAccess) EventCalendarID(ctx context.Context, eventID uuid.UUID) (uuid.UUID, error) {
+	return structures.DefaultCalendarID, nil
+}
+
+// --- tests ---
+
+func TestHandleStream_ResumesFromLastEventID(t *testing.T) {
+	payload := json.RawMessage(`{"id": "x"}`)
+	hiddenID := uuid.New()
+	hidden := json.RawMessage(`{"id": "y", "calendar_id": "` + uuid.NewString() + `"}`)
+	feed := &fakeFeed{
+		sub:       &services.FeedSubscription{C: make(chan structures.OutboxMessage, 4)},
+		watermark: 4,
+		history: []structures.OutboxMessage{
+			{ID: 1, Type: structures.EventCreated, Payload: payload},
+			{ID: 2, Type: structures.AttendeeAdded, Payload: payload},
+			{ID: 3, Type: structures.EventUpdated, Payload: payload},
+			{ID: 4, EventID: hiddenID, Type: structures.EventUpdated, Payload: hidden},
+		},
+	}
+	// Live messages: 4 was already replayed, 5 is new.
+	feed.sub.C <- structures.OutboxMessage{ID: 4, EventID: hiddenID, Type: structures.EventUpdated, Payload: hidden}
+	feed.sub.C <- structures.OutboxMessage{ID: 5, Type: structures.EventDeleted, Payload: payload}
+
+	mux := http.NewServeMux()
+	NewStreamController(feed, fakeAccess{}).RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events/stream: %v", err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type: %q", ct)
+	}
+
+	var ids, types []string
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() && len(types) < 2 {
+		line := sc.Text()
+		if v, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, v)
+		}
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			types = append(types, v)
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok && v != `{"id":"x"}` {
+			t.Fatalf("unexpected data line: %q", v)
+		}
+	}
+	if strings.Join(ids, ",") != "3,5" {
+		t.Fatalf("expected ids 3,5 (attendee change and unreadable calendar filtered, no duplicates), got %v", ids)
+	}
+	if types[0] != structures.EventUpdated || types[1] != structures.EventDeleted {
+		t.Fatalf("unexpected event types: %v", types)
+	}
+}
+
+func TestHandleStream_InvalidLastEventID(t *testing.T) {
+	ctrl := NewStreamController(&fakeFeed{}, fakeAccess{}).(*streamController)
+
+	req := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	w := httptest.NewRecorder()
+
+	ctrl.handleStream(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"events/services"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+type WebhookController interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+type webhookController struct {
+	svc services.WebhookService
+}
+
+func NewWebhookController(svc services.WebhookService) WebhookController {
+	return &webhookController{svc: svc}
+}
+
+func (c *webhookController) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /webhooks", c.handleCreateWebhook)
+	mux.HandleFunc("GET /webhooks", c.handleListWebhooks)
+	mux.HandleFunc("GET /webhooks/{id}", c.handleGetWebhook)
+	mux.HandleFunc("DELETE /webhooks/{id}", c.handleDeleteWebhook)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", c.handleListDeliveries)
+}
+
+func (c *webhookController) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req structures.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	// Validation
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		http.Error(w, "url must be an absolute http or https URL", http.StatusBadRequest)
+		return
+	}
+	for _, t := range req.EventTypes {
+		if !structures.ValidMessageType(t) {
+			http.Error(w, "event_types must be any of "+strings.Join(structures.MessageTypes, ", "), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Secret != "" && len(req.Secret) < 16 {
+		http.Error(w, "secret must be at least 16 characters", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	hook, err := c.svc.CreateWebhook(ctx, &structures.Webhook{
+		ID:         uuid.New(),
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		writeWebhookError(w, "Create webhook", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, hook)
+}
+
+func (c *webhookController) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	hooks, err := c.svc.ListWebhooks(ctx)
+	if err != nil {
+		writeWebhookError(w, "List webhooks", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, hooks)
+}
+
+func (c *webhookController) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	hook, err := c.svc.GetWebhook(ctx, id)
+	if err != nil {
+		writeWebhookError(w, "Get webhook", err)
+		return
+	}
+	if hook == nil {
+		http.Error(w, "webhook not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (c *webhookController) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := c.svc.DeleteWebhook(ctx, id); err != nil {
+		writeWebhookError(w, "Delete webhook", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *webhookController) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, ok := parseUUIDPathValue(w, r, "id")
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", structures.DeliveryPending, structures.DeliveryDelivered, structures.DeliveryFailed:
+	default:
+		http.Error(w, "status must be one of pending, delivered, failed", http.StatusBadRequest)
+		return
+	}
+	limit := defaultDeliveryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDeliveryLimit {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	deliveries, err := c.svc.ListDeliveries(ctx, id, status, limit)
+	if err != nil {
+		writeWebhookError(w, "List deliveries", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func writeWebhookError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, structures.ErrWebhookNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		log.Printf("%s error: %v", op, err)
+		http.Error(w, "failed to process webhook request", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// --- mock service ---
+
+type mockWebhookService struct {
+	createCalled bool
+	createReq    *structures.Webhook
+
+	deleteErr error
+
+	deliveriesStatus string
+	deliveriesLimit  int
+	deliveriesErr    error
+}
+
+func (m *mockWebhookService) CreateWebhook(ctx context.Context, w *structures.Webhook) (*structures.Webhook, error) {
+	m.createCalled = true
+	m.createReq = w
+	return w, nil
+}
+
+func (m *mockWebhookService) ListWebhooks(ctx context.Context) ([]structures.Webhook, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookService) GetWebhook(ctx context.Context, id uuid.UUID) (*structures.Webhook, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return m.deleteErr
+}
+
+func (m *mockWebhookService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit int) ([]structures.WebhookDelivery, error) {
+	m.deliveriesStatus = status
+	m.deliveriesLimit = limit
+	return []structures.WebhookDelivery{}, m.deliveriesErr
+}
+
+// --- tests ---
+
+func TestHandleCreateWebhook_Success(t *testing.T) {
+	mockSvc := &mockWebhookService{}
+	ctrl := NewWebhookController(mockSvc).(*webhookController)
+
+	body := `{"url": "https://example.com/hook", "event_types": ["event.created", "event.deleted"]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	ctrl.handleCreateWebhook(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if !mockSvc.createCalled || len(mockSvc.createReq.EventTypes) != 2 {
+		t.Fatalf("unexpected webhook passed to service: %+v", mockSvc.createReq)
+	}
+}
+
+func TestHandleCreateWebhook_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"relative url", `{"url": "/hook"}`},
+		{"bad scheme", `{"url": "ftp://example.com/hook"}`},
+		{"unknown event type", `{"url": "https://example.com/hook", "event_types": ["event.exploded"]}`},
+		{"short secret", `{"url": "https://example.com/hook", "secret": "abc"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := &mockWebhookService{}
+			ctrl := NewWebhookController(mockSvc).(*webhookController)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			ctrl.handleCreateWebhook(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if mockSvc.createCalled {
+				t.Fatalf("service should not be called on validation error")
+			}
+		})
+	}
+}
+
+func TestHandleDeleteWebhook_NotFound(t *testing.T) {
+	mockSvc := &mockWebhookService{deleteErr: structures.ErrWebhookNotFound}
+	mux := http.NewServeMux()
+	NewWebhookController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+uuid.NewString(), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestHandleListDeliveries_Filters(t *testing.T) {
+	mockSvc := &mockWebhookService{}
+	mux := http.NewServeMux()
+	NewWebhookController(mockSvc).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/"+uuid.NewString()+"/deliveries?status=failed&limit=10", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if mockSvc.deliveriesStatus != structures.DeliveryFailed || mockSvc.deliveriesLimit != 10 {
+		t.Fatalf("unexpected filters: status=%q limit=%d", mockSvc.deliveriesStatus, mockSvc.deliveriesLimit)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/webhooks/"+uuid.NewString()+"/deliveries?limit=1000", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
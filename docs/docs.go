@@ -0,0 +1,10 @@
+// Package docs embeds the API description, so the server can publish and
+// validate against the same file the client is checked against.
+package docs
+
+import _ "embed"
+
+// OpenAPI is docs/openapi.yaml.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,5 @@
+// Package eventspb holds the protobuf definition of the gRPC API and the
+// code generated from it.
+package eventspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative events.proto
@@ -7,4 +7,295 @@ CREATE TABLE IF NOT EXISTS events (
     start_time  TIMESTAMPTZ NOT NULL,
     end_time    TIMESTAMPTZ NOT NULL,
     created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
-);
\ No newline at end of file
+);
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity INTEGER CHECK (capacity > 0);
+
+CREATE TABLE IF NOT EXISTS attendees (
+    event_id     UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    email        VARCHAR(320) NOT NULL,
+    display_name VARCHAR(100),
+    role         VARCHAR(20) NOT NULL DEFAULT 'required',
+    rsvp_status  VARCHAR(20) NOT NULL DEFAULT 'pending',
+    waitlisted   BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS attendees_waitlist_idx
+    ON attendees (event_id, created_at)
+    WHERE waitlisted;
+
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS resources (
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL UNIQUE,
+    kind       VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- during mirrors the event's [start_time, end_time) so the exclusion
+-- constraint can reject overlapping bookings of the same resource.
+CREATE TABLE IF NOT EXISTS event_resources (
+    event_id    UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+    during      TSTZRANGE NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, resource_id),
+    CONSTRAINT event_resources_no_overlap
+        EXCLUDE USING gist (resource_id WITH =, during WITH &&)
+);
+
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS working_hours JSONB;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS all_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Transactional outbox: every mutation inserts a row here in its own
+-- transaction, and the relay worker publishes unpublished rows in id order.
+CREATE TABLE IF NOT EXISTS outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        UUID NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    payload         JSONB NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx
+    ON outbox (next_attempt_at, id)
+    WHERE published_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id          UUID PRIMARY KEY,
+    url         TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    secret      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Webhooks created before owners existed belong to the system actor.
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS owner VARCHAR(100) NOT NULL DEFAULT 'system';
+
+CREATE INDEX IF NOT EXISTS webhooks_owner_idx ON webhooks (owner);
+
+-- One row per outbox message per matching webhook; it doubles as the
+-- delivery log returned by GET /webhooks/{id}/deliveries.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    webhook_id       UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    message_id       BIGINT NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    event_type       VARCHAR(50) NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER,
+    last_error       TEXT,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (webhook_id, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx
+    ON webhook_deliveries (next_attempt_at, id)
+    WHERE status = 'pending';
+
+-- Looked up when outbox messages are purged, and by the cascade.
+CREATE INDEX IF NOT EXISTS webhook_deliveries_message_idx
+    ON webhook_deliveries (message_id);
+
+-- Reminders fire minutes_before the event's start_time; the due time is
+-- computed from events so rescheduling moves it automatically.
+CREATE TABLE IF NOT EXISTS reminders (
+    id              UUID PRIMARY KEY,
+    event_id        UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    minutes_before  INTEGER NOT NULL CHECK (minutes_before >= 0),
+    channel         VARCHAR(20) NOT NULL,
+    target          TEXT NOT NULL DEFAULT '',
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reminders_pending_idx
+    ON reminders (event_id)
+    WHERE status = 'pending';
+
+-- Append-only audit log of event mutations. snapshot is the event after
+-- the change (before it, for deletes), so the event can be rebuilt as of
+-- any past time; diff holds only the fields that changed. There is no
+-- foreign key: the history outlives the event.
+CREATE TABLE IF NOT EXISTS event_history (
+    id         BIGSERIAL PRIMARY KEY,
+    event_id   UUID NOT NULL,
+    operation  VARCHAR(20) NOT NULL,
+    actor      TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    snapshot   JSONB NOT NULL,
+    diff       JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS event_history_event_idx
+    ON event_history (event_id, changed_at, id);
+
+CREATE OR REPLACE FUNCTION event_history_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'event_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS event_history_append_only ON event_history;
+CREATE TRIGGER event_history_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON event_history
+    FOR EACH STATEMENT EXECUTE FUNCTION event_history_append_only();
+
+-- Soft delete: deleted events stay in the trash until the purge job removes
+-- them for good, which cascades to their attendees and reminders.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS events_deleted_at_idx
+    ON events (deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- Lifecycle status; transitions are enforced by the event service.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'scheduled';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS events_status_start_idx
+    ON events (status, start_time);
+
+-- Tags are shared between events; free-form metadata is filtered with @>,
+-- which jsonb_path_ops indexes.
+CREATE TABLE IF NOT EXISTS tags (
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS event_tags (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    tag_id   BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (event_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS event_tags_tag_idx
+    ON event_tags (tag_id, event_id);
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS events_metadata_idx
+    ON events USING GIN (metadata jsonb_path_ops);
+
+-- Calendars own events. The default calendar holds the events created
+-- before calendars existed and those created through POST /events; it is
+-- shared with everyone ('*') for writing and owned by nobody.
+CREATE TABLE IF NOT EXISTS calendars (
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    color      VARCHAR(7) NOT NULL DEFAULT '#3b82f6',
+    owner      VARCHAR(100) NOT NULL,
+    time_zone  VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS calendars_owner_idx ON calendars (owner);
+
+CREATE TABLE IF NOT EXISTS calendar_shares (
+    calendar_id UUID NOT NULL REFERENCES calendars(id) ON DELETE CASCADE,
+    actor       VARCHAR(100) NOT NULL,
+    permission  VARCHAR(10) NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (calendar_id, actor)
+);
+
+CREATE INDEX IF NOT EXISTS calendar_shares_actor_idx ON calendar_shares (actor);
+
+INSERT INTO calendars (id, name, owner)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'system')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO calendar_shares (calendar_id, actor, permission)
+VALUES ('00000000-0000-0000-0000-000000000001', '*', 'write')
+ON CONFLICT (calendar_id, actor) DO NOTHING;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS calendar_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001'
+    REFERENCES calendars(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS events_calendar_start_idx
+    ON events (calendar_id, start_time, id);
+
+-- Locations keep their coordinates as two plain doubles rather than a
+-- PostGIS point. Near searches narrow rows down with the bounding-box
+-- index before computing the haversine distance.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS location_name    VARCHAR(200);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS location_address VARCHAR(500);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS latitude         DOUBLE PRECISION;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS longitude        DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS events_lat_lng_idx
+    ON events (latitude, longitude)
+    WHERE latitude IS NOT NULL;
+
+-- Attachment content lives in a blob store under blob_key; only the
+-- metadata is kept here. Purging an event drops its rows but not its
+-- blobs, which sit under events/<event id>/ in the store.
+CREATE TABLE IF NOT EXISTS attachments (
+    id           UUID PRIMARY KEY,
+    event_id     UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size         BIGINT NOT NULL,
+    sha256       CHAR(64) NOT NULL,
+    blob_key     TEXT NOT NULL,
+    uploaded_by  VARCHAR(100) NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS attachments_event_idx ON attachments (event_id, created_at);
+
+-- Comments form threads through parent_id. Deleting a comment that has
+-- replies keeps it as a tombstone (deleted_at set, body emptied) so the
+-- thread holds together; purging an event removes its whole discussion.
+CREATE TABLE IF NOT EXISTS comments (
+    id         UUID PRIMARY KEY,
+    event_id   UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    parent_id  UUID REFERENCES comments(id) ON DELETE CASCADE,
+    author     VARCHAR(100) NOT NULL,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    edited_at  TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS comments_thread_idx ON comments (event_id, parent_id, created_at, id);
+CREATE INDEX IF NOT EXISTS comments_parent_idx ON comments (parent_id) WHERE parent_id IS NOT NULL;
+
+-- Earlier bodies of edited comments.
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id          BIGSERIAL PRIMARY KEY,
+    comment_id  UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    body        TEXT NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS comment_revisions_comment_idx ON comment_revisions (comment_id, id);
+
+-- Full-text search. Each event is indexed in its own language; a search
+-- restricted to one language can use the GIN index, one across languages
+-- parses the query per row.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_language REGCONFIG NOT NULL DEFAULT 'english';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_language, title), 'A') ||
+        setweight(to_tsvector(search_language, COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS events_search_idx ON events USING GIN (search_vector);
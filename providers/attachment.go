@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type pgAttachmentStore struct {
+	db *sql.DB
+}
+
+func NewPGAttachmentStore(db *sql.DB) *pgAttachmentStore {
+	return &pgAttachmentStore{db: db}
+}
+
+const attachmentColumns = `a.id, a.event_id, a.filename, a.content_type, a.size, a.sha256, a.blob_key, a.uploaded_by, a.created_at`
+
+// CreateAttachment only inserts when the event is outside the trash.
+func (s *pgAttachmentStore) CreateAttachment(ctx context.Context, a *structures.Attachment) (*structures.Attachment, error) {
+	const q = `
+        INSERT INTO attachments (id, event_id, filename, content_type, size, sha256, blob_key, uploaded_by, created_at)
+        SELECT $1, e.id, $3, $4, $5, $6, $7, $8, $9
+        FROM events e
+        WHERE e.id = $2 AND e.deleted_at IS NULL
+    `
+	res, err := s.db.ExecContext(ctx, q, a.ID, a.EventID, a.Filename, a.ContentType, a.Size, a.SHA256, a.BlobKey, a.UploadedBy, a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, structures.ErrEventNotFound
+	}
+	return a, nil
+}
+
+func (s *pgAttachmentStore) ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error) {
+	const existsQ = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, existsQ, eventID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, structures.ErrEventNotFound
+	}
+
+	const q = `
+        SELECT ` + attachmentColumns + `
+        FROM attachments a
+        WHERE a.event_id = $1
+        ORDER BY a.created_at ASC, a.id ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]structures.Attachment, 0)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (s *pgAttachmentStore) GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	const q = `
+        SELECT ` + attachmentColumns + `
+        FROM attachments a
+        JOIN events e ON e.id = a.event_id
+        WHERE a.id = $1 AND a.event_id = $2 AND e.deleted_at IS NULL
+    `
+	a, err := scanAttachment(s.db.QueryRowContext(ctx, q, id, eventID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *pgAttachmentStore) DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	const q = `
+        DELETE FROM attachments a
+        USING events e
+        WHERE a.id = $1 AND a.event_id = $2 AND e.id = a.event_id AND e.deleted_at IS NULL
+        RETURNING ` + attachmentColumns
+	a, err := scanAttachment(s.db.QueryRowContext(ctx, q, id, eventID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, structures.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func scanAttachment(row rowScanner) (*structures.Attachment, error) {
+	var a structures.Attachment
+	err := row.Scan(&a.ID, &a.EventID, &a.Filename, &a.ContentType, &a.Size, &a.SHA256, &a.BlobKey, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
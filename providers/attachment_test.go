@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+var attachmentRowColumns = []string{"id", "event_id", "filename", "content_type", "size", "sha256", "blob_key", "uploaded_by", "created_at"}
+
+func TestCreateAttachment_RequiresLiveEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttachmentStore{db: db}
+	a := &structures.Attachment{
+		ID: uuid.New(), EventID: uuid.New(), Filename: "agenda.pdf", ContentType: "application/pdf",
+		Size: 8, SHA256: "abc", BlobKey: "events/x/y", UploadedBy: "token:abc", CreatedAt: time.Now(),
+	}
+	query := regexp.QuoteMeta(`WHERE e.id = $2 AND e.deleted_at IS NULL`)
+	args := []driver.Value{a.ID, a.EventID, a.Filename, a.ContentType, a.Size, a.SHA256, a.BlobKey, a.UploadedBy, a.CreatedAt}
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	// Nothing is inserted when the event is missing or in the trash.
+	mock.ExpectExec(query).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if _, err := store.CreateAttachment(context.Background(), a); err != nil {
+		t.Fatalf("CreateAttachment returned error: %v", err)
+	}
+	if _, err := store.CreateAttachment(context.Background(), a); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound for a missing or trashed event, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteAttachment_ReturnsDeletedRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttachmentStore{db: db}
+	eventID, id := uuid.New(), uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM attachments a`)).
+		WithArgs(id, eventID).
+		WillReturnRows(sqlmock.NewRows(attachmentRowColumns).
+			AddRow(id, eventID, "agenda.pdf", "application/pdf", 8, "abc", "events/x/y", "token:abc", time.Now()))
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM attachments a`)).
+		WithArgs(id, eventID).
+		WillReturnRows(sqlmock.NewRows(attachmentRowColumns))
+
+	a, err := store.DeleteAttachment(context.Background(), eventID, id)
+	if err != nil || a.BlobKey != "events/x/y" {
+		t.Fatalf("DeleteAttachment = %+v, %v", a, err)
+	}
+	if _, err := store.DeleteAttachment(context.Background(), eventID, id); !errors.Is(err, structures.ErrAttachmentNotFound) {
+		t.Fatalf("expected ErrAttachmentNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type pgAttendeeStore struct {
+	db *sql.DB
+}
+
+func NewPGAttendeeStore(db *sql.DB) *pgAttendeeStore {
+	return &pgAttendeeStore{db: db}
+}
+
+// AddAttendee registers an attendee, placing them on the waitlist when the
+// event is at capacity. The event row is locked for the duration of the
+// transaction so concurrent sign-ups are serialised and never overbook.
+func (s *pgAttendeeStore) AddAttendee(ctx context.Context, a *structures.Attendee) (*structures.Attendee, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	capacity, err := lockEventCapacity(ctx, tx, a.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if capacity != nil {
+		seated, err := countSeated(ctx, tx, a.EventID)
+		if err != nil {
+			return nil, err
+		}
+		a.Waitlisted = seated >= *capacity
+	}
+
+	const q = `
+        INSERT INTO attendees (event_id, email, display_name, role, rsvp_status, waitlisted)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING created_at, updated_at
+    `
+	err = tx.QueryRowContext(ctx, q,
+		a.EventID,
+		a.Email,
+		a.DisplayName,
+		a.Role,
+		a.RSVPStatus,
+		a.Waitlisted,
+	).Scan(&a.CreatedAt, &a.UpdatedAt)
+	if pgErrorCode(err) == pgUniqueViolation {
+		return nil, structures.ErrAttendeeExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, a.EventID, structures.AttendeeAdded, a); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *pgAttendeeStore) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error) {
+	const existsQ = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, existsQ, eventID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, structures.ErrEventNotFound
+	}
+
+	const q = `
+        SELECT ` + attendeeColumns + `
+        FROM attendees
+        WHERE event_id = $1
+        ORDER BY created_at ASC, email ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attendees := make([]structures.Attendee, 0)
+	for rows.Next() {
+		a, err := scanAttendee(rows)
+		if err != nil {
+			return nil, err
+		}
+		attendees = append(attendees, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+// ListAttendeesForEvents returns the attendees of several events in one
+// query, keyed by event id. Events without attendees are absent.
+func (s *pgAttendeeStore) ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error) {
+	const q = `
+        SELECT ` + attendeeColumns + `
+        FROM attendees
+        WHERE event_id = ANY($1::uuid[])
+        ORDER BY created_at ASC, email ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, uuidStrings(eventIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attendees := make(map[uuid.UUID][]structures.Attendee)
+	for rows.Next() {
+		a, err := scanAttendee(rows)
+		if err != nil {
+			return nil, err
+		}
+		attendees[a.EventID] = append(attendees[a.EventID], *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+// RemoveAttendee cancels a registration and promotes the next attendees
+// from the waitlist into any seat it frees.
+func (s *pgAttendeeStore) RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	capacity, err := lockEventCapacity(ctx, tx, eventID)
+	if errors.Is(err, structures.ErrEventNotFound) {
+		return structures.ErrAttendeeNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	const q = `
+        DELETE FROM attendees
+        WHERE event_id = $1 AND email = $2
+        RETURNING ` + attendeeColumns
+	removed, err := scanAttendee(tx.QueryRowContext(ctx, q, eventID, email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return structures.ErrAttendeeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, tx, eventID, structures.AttendeeRemoved, removed); err != nil {
+		return err
+	}
+
+	if capacity != nil {
+		if err := promoteWaitlist(ctx, tx, eventID, capacity); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateRSVP changes an attendee's RSVP. Declining gives up a seat (or a
+// waitlist spot); coming back from a decline re-joins the waitlist when the
+// event has filled up in the meantime.
+func (s *pgAttendeeStore) UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	capacity, err := lockEventCapacity(ctx, tx, eventID)
+	if errors.Is(err, structures.ErrEventNotFound) {
+		return nil, structures.ErrAttendeeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	const currentQ = `SELECT rsvp_status FROM attendees WHERE event_id = $1 AND email = $2`
+	var current string
+	err = tx.QueryRowContext(ctx, currentQ, eventID, email).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, structures.ErrAttendeeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	waitlisted := false
+	if current == structures.RSVPDeclined && status != structures.RSVPDeclined && capacity != nil {
+		seated, err := countSeated(ctx, tx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		waitlisted = seated >= *capacity
+	}
+
+	// Only the decline transitions move attendees on or off the waitlist;
+	// other RSVP changes keep their current place.
+	const q = `
+        UPDATE attendees
+        SET rsvp_status = $3,
+            waitlisted = CASE
+                WHEN $3 = 'declined' THEN FALSE
+                WHEN rsvp_status = 'declined' THEN $4
+                ELSE waitlisted
+            END,
+            updated_at = NOW()
+        WHERE event_id = $1 AND email = $2
+        RETURNING ` + attendeeColumns
+	a, err := scanAttendee(tx.QueryRowContext(ctx, q, eventID, email, status, waitlisted))
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, eventID, structures.AttendeeRSVPUpdated, a); err != nil {
+		return nil, err
+	}
+
+	if status == structures.RSVPDeclined && capacity != nil {
+		if err := promoteWaitlist(ctx, tx, eventID, capacity); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// lockEventCapacity takes a row lock on the event and returns its capacity,
+// nil meaning unlimited.
+func lockEventCapacity(ctx context.Context, tx *sql.Tx, eventID uuid.UUID) (*int, error) {
+	const q = `SELECT capacity FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	var capacity *int
+	err := tx.QueryRowContext(ctx, q, eventID).Scan(&capacity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, structures.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return capacity, nil
+}
+
+func countSeated(ctx context.Context, tx *sql.Tx, eventID uuid.UUID) (int, error) {
+	const q = `
+        SELECT COUNT(*)
+        FROM attendees
+        WHERE event_id = $1 AND NOT waitlisted AND rsvp_status <> 'declined'
+    `
+	var n int
+	err := tx.QueryRowContext(ctx, q, eventID).Scan(&n)
+	return n, err
+}
+
+// promoteWaitlist fills free seats from the waitlist in registration order;
+// a nil capacity promotes everyone still waiting.
+func promoteWaitlist(ctx context.Context, tx *sql.Tx, eventID uuid.UUID, capacity *int) error {
+	var limit *int
+	if capacity != nil {
+		seated, err := countSeated(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+		free := *capacity - seated
+		if free <= 0 {
+			return nil
+		}
+		limit = &free
+	}
+
+	const q = `
+        UPDATE attendees
+        SET waitlisted = FALSE, updated_at = NOW()
+        WHERE (event_id, email) IN (
+            SELECT event_id, email
+            FROM attendees
+            WHERE event_id = $1 AND waitlisted
+            ORDER BY created_at ASC, email ASC
+            LIMIT $2
+        )
+        RETURNING ` + attendeeColumns
+	rows, err := tx.QueryContext(ctx, q, eventID, limit)
+	if err != nil {
+		return err
+	}
+	var promoted []*structures.Attendee
+	for rows.Next() {
+		a, err := scanAttendee(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		promoted = append(promoted, a)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, a := range promoted {
+		if err := insertOutbox(ctx, tx, eventID, structures.AttendeePromoted, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const attendeeColumns = `event_id, email, COALESCE(display_name, ''), role, rsvp_status, waitlisted, created_at, updated_at`
+
+func scanAttendee(row rowScanner) (*structures.Attendee, error) {
+	var a structures.Attendee
+	err := row.Scan(&a.EventID, &a.Email, &a.DisplayName, &a.Role, &a.RSVPStatus, &a.Waitlisted, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
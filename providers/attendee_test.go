@@ -0,0 +1,286 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	lockEventQuery   = `SELECT capacity FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	countSeatedQuery = `SELECT COUNT(*)`
+)
+
+var attendeeRowColumns = []string{
+	"event_id", "email", "display_name", "role", "rsvp_status", "waitlisted", "created_at", "updated_at",
+}
+
+func TestAddAttendee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	now := time.Now().UTC()
+	a := &structures.Attendee{
+		EventID:    uuid.New(),
+		Email:      "ana@example.com",
+		Role:       structures.RoleRequired,
+		RSVPStatus: structures.RSVPPending,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(a.EventID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO attendees`)).
+		WithArgs(a.EventID, a.Email, a.DisplayName, a.Role, a.RSVPStatus, false).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	expectOutbox(mock, a.EventID, structures.AttendeeAdded)
+	mock.ExpectCommit()
+
+	got, err := store.AddAttendee(context.Background(), a)
+	if err != nil {
+		t.Fatalf("AddAttendee returned error: %v", err)
+	}
+	if !got.CreatedAt.Equal(now) {
+		t.Fatalf("expected created_at to be scanned, got %v", got.CreatedAt)
+	}
+	if got.Waitlisted {
+		t.Fatalf("attendee should not be waitlisted on an unlimited event")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestAddAttendee_WaitlistedWhenFull(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	now := time.Now().UTC()
+	a := &structures.Attendee{EventID: uuid.New(), Email: "late@example.com", Role: structures.RoleRequired, RSVPStatus: structures.RSVPPending}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(a.EventID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(countSeatedQuery)).
+		WithArgs(a.EventID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO attendees`)).
+		WithArgs(a.EventID, a.Email, a.DisplayName, a.Role, a.RSVPStatus, true).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	expectOutbox(mock, a.EventID, structures.AttendeeAdded)
+	mock.ExpectCommit()
+
+	got, err := store.AddAttendee(context.Background(), a)
+	if err != nil {
+		t.Fatalf("AddAttendee returned error: %v", err)
+	}
+	if !got.Waitlisted {
+		t.Fatalf("expected attendee to be waitlisted on a full event")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestAddAttendee_EventNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}))
+	mock.ExpectRollback()
+
+	_, err = store.AddAttendee(context.Background(), &structures.Attendee{EventID: uuid.New(), Email: "a@b.c"})
+	if !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestAddAttendee_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO attendees`)).
+		WillReturnError(&pgconn.PgError{Code: pgUniqueViolation})
+	mock.ExpectRollback()
+
+	_, err = store.AddAttendee(context.Background(), &structures.Attendee{EventID: uuid.New(), Email: "a@b.c"})
+	if !errors.Is(err, structures.ErrAttendeeExists) {
+		t.Fatalf("expected ErrAttendeeExists, got %v", err)
+	}
+}
+
+func TestListAttendees_EventNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	if _, err := store.ListAttendees(context.Background(), uuid.New()); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestRemoveAttendee_PromotesWaitlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+	eventID := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(eventID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM attendees`)).
+		WithArgs(eventID, "ana@example.com").
+		WillReturnRows(sqlmock.NewRows(attendeeRowColumns).
+			AddRow(eventID, "ana@example.com", "", structures.RoleRequired, structures.RSVPAccepted, false, now, now))
+	expectOutbox(mock, eventID, structures.AttendeeRemoved)
+	mock.ExpectQuery(regexp.QuoteMeta(countSeatedQuery)).
+		WithArgs(eventID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SET waitlisted = FALSE`)).
+		WithArgs(eventID, 1).
+		WillReturnRows(sqlmock.NewRows(attendeeRowColumns).
+			AddRow(eventID, "bo@example.com", "", structures.RoleRequired, structures.RSVPPending, false, now, now))
+	expectOutbox(mock, eventID, structures.AttendeePromoted)
+	mock.ExpectCommit()
+
+	if err := store.RemoveAttendee(context.Background(), eventID, "ana@example.com"); err != nil {
+		t.Fatalf("RemoveAttendee returned error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestRemoveAttendee_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM attendees`)).
+		WillReturnRows(sqlmock.NewRows(attendeeRowColumns))
+	mock.ExpectRollback()
+
+	if err := store.RemoveAttendee(context.Background(), uuid.New(), "ana@example.com"); !errors.Is(err, structures.ErrAttendeeNotFound) {
+		t.Fatalf("expected ErrAttendeeNotFound, got %v", err)
+	}
+}
+
+func TestUpdateRSVP_DeclinePromotesWaitlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+	eventID := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT rsvp_status FROM attendees`)).
+		WillReturnRows(sqlmock.NewRows([]string{"rsvp_status"}).AddRow(structures.RSVPAccepted))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE attendees`)).
+		WithArgs(eventID, "ana@example.com", structures.RSVPDeclined, false).
+		WillReturnRows(sqlmock.NewRows(attendeeRowColumns).
+			AddRow(eventID, "ana@example.com", "", structures.RoleRequired, structures.RSVPDeclined, false, now, now))
+	expectOutbox(mock, eventID, structures.AttendeeRSVPUpdated)
+	mock.ExpectQuery(regexp.QuoteMeta(countSeatedQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SET waitlisted = FALSE`)).
+		WithArgs(eventID, 1).
+		WillReturnRows(sqlmock.NewRows(attendeeRowColumns))
+	mock.ExpectCommit()
+
+	a, err := store.UpdateRSVP(context.Background(), eventID, "ana@example.com", structures.RSVPDeclined)
+	if err != nil {
+		t.Fatalf("UpdateRSVP returned error: %v", err)
+	}
+	if a.RSVPStatus != structures.RSVPDeclined {
+		t.Fatalf("unexpected rsvp status %q", a.RSVPStatus)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpdateRSVP_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgAttendeeStore{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT rsvp_status FROM attendees`)).
+		WillReturnRows(sqlmock.NewRows([]string{"rsvp_status"}))
+	mock.ExpectRollback()
+
+	if _, err := store.UpdateRSVP(context.Background(), uuid.New(), "ana@example.com", structures.RSVPAccepted); !errors.Is(err, structures.ErrAttendeeNotFound) {
+		t.Fatalf("expected ErrAttendeeNotFound, got %v", err)
+	}
+}
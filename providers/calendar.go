@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type pgCalendarStore struct {
+	db *sql.DB
+}
+
+func NewPGCalendarStore(db *sql.DB) *pgCalendarStore {
+	return &pgCalendarStore{db: db}
+}
+
+// selectCalendars reads calendars with the permission of the actor in $1:
+// manage for the owner, otherwise the highest of the actor's own share and
+// the share with everyone. Callers append their own WHERE / ORDER BY.
+const selectCalendars = `
+        SELECT c.id, c.name, c.color, c.owner, c.time_zone, c.created_at,
+               CASE WHEN c.owner = $1 THEN 'manage' ELSE COALESCE(p.permission, '') END
+        FROM calendars c
+        LEFT JOIN LATERAL (
+            SELECT s.permission
+            FROM calendar_shares s
+            WHERE s.calendar_id = c.id AND s.actor IN ($1, '*')
+            ORDER BY CASE s.permission WHEN 'manage' THEN 3 WHEN 'write' THEN 2 ELSE 1 END DESC
+            LIMIT 1
+        ) p ON TRUE`
+
+func (s *pgCalendarStore) CreateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	const q = `
+        INSERT INTO calendars (id, name, color, owner, time_zone, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := s.db.ExecContext(ctx, q, c.ID, c.Name, c.Color, c.Owner, c.TimeZone, c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Permission = structures.PermissionManage
+	return c, nil
+}
+
+// ListCalendars returns the calendars the actor in ctx can read, by name.
+func (s *pgCalendarStore) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	const q = selectCalendars + `
+        WHERE c.owner = $1 OR p.permission IS NOT NULL
+        ORDER BY c.name ASC, c.id ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, structures.ActorFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	calendars := make([]structures.Calendar, 0)
+	for rows.Next() {
+		c, err := scanCalendar(rows)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return calendars, nil
+}
+
+// GetCalendar returns the calendar with the permission of the actor in ctx,
+// or nil when it does not exist. Access is the service's job to check.
+func (s *pgCalendarStore) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	const q = selectCalendars + `
+        WHERE c.id = $2
+    `
+	c, err := scanCalendar(s.db.QueryRowContext(ctx, q, structures.ActorFromContext(ctx), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *pgCalendarStore) UpdateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	const q = `
+        UPDATE calendars
+        SET name = $2, color = $3, time_zone = $4
+        WHERE id = $1
+    `
+	res, err := s.db.ExecContext(ctx, q, c.ID, c.Name, c.Color, c.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, structures.ErrCalendarNotFound
+	}
+	return s.GetCalendar(ctx, c.ID)
+}
+
+// DeleteCalendar deletes a calendar that has no live events. Events still
+// in its trash are purged with it, and recorded as such in their history.
+// Locking the calendar row blocks events from being created in it until
+// the delete commits.
+func (s *pgCalendarStore) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const lockQ = `
+        SELECT EXISTS (SELECT 1 FROM events WHERE calendar_id = $1 AND deleted_at IS NULL)
+        FROM calendars
+        WHERE id = $1
+        FOR UPDATE
+    `
+	var hasEvents bool
+	err = tx.QueryRowContext(ctx, lockQ, id).Scan(&hasEvents)
+	if errors.Is(err, sql.ErrNoRows) {
+		return structures.ErrCalendarNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if hasEvents {
+		return structures.ErrCalendarNotEmpty
+	}
+
+	const trashQ = selectEvents + `
+        WHERE e.calendar_id = $1
+    `
+	rows, err := tx.QueryContext(ctx, trashQ, id)
+	if err != nil {
+		return err
+	}
+	var trashed []*structures.Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		trashed = append(trashed, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	const q = `DELETE FROM calendars WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, q, id); err != nil {
+		return err
+	}
+	for _, e := range trashed {
+		if err := insertHistory(ctx, tx, structures.HistoryPurge, e, nil); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ShareCalendar grants or changes the permission of sh.Actor.
+func (s *pgCalendarStore) ShareCalendar(ctx context.Context, sh *structures.CalendarShare) (*structures.CalendarShare, error) {
+	const q = `
+        INSERT INTO calendar_shares (calendar_id, actor, permission)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (calendar_id, actor) DO UPDATE SET permission = EXCLUDED.permission
+        RETURNING created_at
+    `
+	err := s.db.QueryRowContext(ctx, q, sh.CalendarID, sh.Actor, sh.Permission).Scan(&sh.CreatedAt)
+	if pgErrorCode(err) == pgForeignKeyViolation {
+		return nil, structures.ErrCalendarNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sh, nil
+}
+
+func (s *pgCalendarStore) ListCalendarShares(ctx context.Context, calendarID uuid.UUID) ([]structures.CalendarShare, error) {
+	const q = `
+        SELECT calendar_id, actor, permission, created_at
+        FROM calendar_shares
+        WHERE calendar_id = $1
+        ORDER BY actor ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := make([]structures.CalendarShare, 0)
+	for rows.Next() {
+		var sh structures.CalendarShare
+		if err := rows.Scan(&sh.CalendarID, &sh.Actor, &sh.Permission, &sh.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, sh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (s *pgCalendarStore) UnshareCalendar(ctx context.Context, calendarID uuid.UUID, actor string) error {
+	const q = `DELETE FROM calendar_shares WHERE calendar_id = $1 AND actor = $2`
+	res, err := s.db.ExecContext(ctx, q, calendarID, actor)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return structures.ErrShareNotFound
+	}
+	return nil
+}
+
+func scanCalendar(row rowScanner) (*structures.Calendar, error) {
+	var c structures.Calendar
+	err := row.Scan(&c.ID, &c.Name, &c.Color, &c.Owner, &c.TimeZone, &c.CreatedAt, &c.Permission)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
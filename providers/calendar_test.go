@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+var calendarColumns = []string{"id", "name", "color", "owner", "time_zone", "created_at", "permission"}
+
+func TestGetCalendar_ReportsCallerPermission(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCalendarStore{db: db}
+	id := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(selectCalendars+`
+        WHERE c.id = $2
+    `)).
+		WithArgs("token:abc", id).
+		WillReturnRows(sqlmock.NewRows(calendarColumns).
+			AddRow(id, "Team", "#3b82f6", "token:owner", "UTC", time.Now(), "write"))
+
+	ctx := structures.WithActor(context.Background(), "token:abc")
+	c, err := store.GetCalendar(ctx, id)
+	if err != nil || c.Permission != structures.PermissionWrite || c.Owner != "token:owner" {
+		t.Fatalf("GetCalendar = %+v, %v", c, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteCalendar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCalendarStore{db: db}
+	id, trashed := uuid.New(), uuid.New()
+	now := time.Now().UTC()
+
+	// Live events keep the calendar.
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM calendars`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+	if err := store.DeleteCalendar(context.Background(), id); !errors.Is(err, structures.ErrCalendarNotEmpty) {
+		t.Fatalf("expected ErrCalendarNotEmpty, got %v", err)
+	}
+
+	// Events in the trash are purged with it.
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM calendars`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE e.calendar_id = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(trashed, "Old", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", now, "{}", "[]", id, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM calendars WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectHistory(mock, trashed, structures.HistoryPurge, structures.SystemActor,
+		"id", "calendar_id", "title", "start_time", "end_time", "time_zone", "all_day", "created_at", "status", "deleted_at", "language")
+	mock.ExpectCommit()
+	if err := store.DeleteCalendar(context.Background(), id); err != nil {
+		t.Fatalf("DeleteCalendar: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"events/structures"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type pgCommentStore struct {
+	db *sql.DB
+}
+
+func NewPGCommentStore(db *sql.DB) *pgCommentStore {
+	return &pgCommentStore{db: db}
+}
+
+const commentColumns = `c.id, c.event_id, c.parent_id, c.author, c.body, c.created_at, c.edited_at,
+               c.deleted_at IS NOT NULL,
+               (SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id)`
+
+// CreateComment only inserts when the event is outside the trash and the
+// parent, if any, is a live comment of the same event. When nothing is
+// inserted a second query tells which of the two was missing.
+func (s *pgCommentStore) CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error) {
+	const q = `
+        INSERT INTO comments (id, event_id, parent_id, author, body, created_at)
+        SELECT $1, e.id, $3, $4, $5, $6
+        FROM events e
+        WHERE e.id = $2 AND e.deleted_at IS NULL
+          AND ($3::uuid IS NULL OR EXISTS (
+                SELECT 1 FROM comments p
+                WHERE p.id = $3 AND p.event_id = e.id AND p.deleted_at IS NULL
+              ))
+    `
+	res, err := s.db.ExecContext(ctx, q, c.ID, c.EventID, c.ParentID, c.Author, c.Body, c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		exists, err := s.eventExists(ctx, c.EventID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists || c.ParentID == nil {
+			return nil, structures.ErrEventNotFound
+		}
+		return nil, structures.ErrParentCommentNotFound
+	}
+	return c, nil
+}
+
+// ListComments returns one page of a thread in (created_at, id) order, the
+// order the cursor is based on. One extra row is read to tell whether
+// there is a next page. Tombstones are listed so replies keep their place.
+func (s *pgCommentStore) ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error) {
+	const existsQ = `
+        SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL),
+               $2::uuid IS NULL OR EXISTS (SELECT 1 FROM comments WHERE id = $2 AND event_id = $1)
+    `
+	var eventExists, parentExists bool
+	if err := s.db.QueryRowContext(ctx, existsQ, q.EventID, q.ParentID).Scan(&eventExists, &parentExists); err != nil {
+		return nil, err
+	}
+	if !eventExists {
+		return nil, structures.ErrEventNotFound
+	}
+	if !parentExists {
+		return nil, structures.ErrCommentNotFound
+	}
+
+	const query = `
+        SELECT ` + commentColumns + `
+        FROM comments c
+        WHERE c.event_id = $1
+          AND (($2::uuid IS NULL AND c.parent_id IS NULL) OR c.parent_id = $2)
+          AND ($3::timestamptz IS NULL OR (c.created_at, c.id) > ($3, $4))
+        ORDER BY c.created_at ASC, c.id ASC
+        LIMIT $5
+    `
+	var afterCreated *time.Time
+	var afterID uuid.UUID
+	if q.After != nil {
+		afterCreated, afterID = &q.After.CreatedAt, q.After.ID
+	}
+	rows, err := s.db.QueryContext(ctx, query, q.EventID, q.ParentID, afterCreated, afterID, q.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]structures.Comment, 0)
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	page := &structures.CommentPage{Comments: comments}
+	if len(comments) > q.Limit {
+		page.Comments = comments[:q.Limit]
+		page.NextCursor = structures.CommentCursorOf(page.Comments[q.Limit-1]).Encode()
+	}
+	return page, nil
+}
+
+func (s *pgCommentStore) GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error) {
+	const q = `
+        SELECT ` + commentColumns + `
+        FROM comments c
+        JOIN events e ON e.id = c.event_id
+        WHERE c.id = $1 AND c.event_id = $2 AND e.deleted_at IS NULL
+    `
+	c, err := scanComment(s.db.QueryRowContext(ctx, q, id, eventID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateComment archives the current body and replaces it in one
+// statement. The row lock makes concurrent edits archive each other's
+// bodies in turn rather than the same one twice.
+func (s *pgCommentStore) UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string, editedAt time.Time) (*structures.Comment, error) {
+	const q = `
+        WITH old AS (
+            SELECT c.id, c.body
+            FROM comments c
+            JOIN events e ON e.id = c.event_id
+            WHERE c.id = $1 AND c.event_id = $2 AND c.deleted_at IS NULL AND e.deleted_at IS NULL
+            FOR UPDATE OF c
+        ), revision AS (
+            INSERT INTO comment_revisions (comment_id, body, replaced_at)
+            SELECT id, body, $4 FROM old
+        )
+        UPDATE comments c
+        SET body = $3, edited_at = $4
+        FROM old
+        WHERE c.id = old.id
+        RETURNING ` + commentColumns
+	c, err := scanComment(s.db.QueryRowContext(ctx, q, id, eventID, body, editedAt))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, structures.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *pgCommentStore) ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error) {
+	c, err := s.GetComment(ctx, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, structures.ErrCommentNotFound
+	}
+
+	const q = `
+        SELECT id, comment_id, body, replaced_at
+        FROM comment_revisions
+        WHERE comment_id = $1
+        ORDER BY id ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]structures.CommentRevision, 0)
+	for rows.Next() {
+		var r structures.CommentRevision
+		if err := rows.Scan(&r.ID, &r.CommentID, &r.Body, &r.ReplacedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// DeleteComment locks the comment first: a reply being inserted needs a
+// key share lock on its parent, so no reply can appear between deciding
+// to remove the row and removing it.
+func (s *pgCommentStore) DeleteComment(ctx context.Context, eventID, id uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const lockQ = `
+        SELECT c.parent_id, EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id)
+        FROM comments c
+        JOIN events e ON e.id = c.event_id
+        WHERE c.id = $1 AND c.event_id = $2 AND c.deleted_at IS NULL AND e.deleted_at IS NULL
+        FOR UPDATE OF c
+    `
+	var parentID *uuid.UUID
+	var hasReplies bool
+	err = tx.QueryRowContext(ctx, lockQ, id, eventID).Scan(&parentID, &hasReplies)
+	if errors.Is(err, sql.ErrNoRows) {
+		return structures.ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if hasReplies {
+		const tombstoneQ = `UPDATE comments SET body = '', edited_at = NULL, deleted_at = NOW() WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, tombstoneQ, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM comment_revisions WHERE comment_id = $1`, id); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE id = $1`, id); err != nil {
+		return err
+	}
+	// Tombstones only exist to hold replies; remove those that no longer
+	// do, up the thread.
+	const pruneQ = `
+        DELETE FROM comments p
+        WHERE p.id = $1 AND p.deleted_at IS NOT NULL
+          AND NOT EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = p.id)
+        RETURNING p.parent_id
+    `
+	for parentID != nil {
+		err := tx.QueryRowContext(ctx, pruneQ, *parentID).Scan(&parentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *pgCommentStore) eventExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	const q = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	err := s.db.QueryRowContext(ctx, q, id).Scan(&exists)
+	return exists, err
+}
+
+func scanComment(row rowScanner) (*structures.Comment, error) {
+	var c structures.Comment
+	var parentID uuid.NullUUID
+	var editedAt sql.NullTime
+	err := row.Scan(&c.ID, &c.EventID, &parentID, &c.Author, &c.Body, &c.CreatedAt, &editedAt, &c.Deleted, &c.ReplyCount)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		c.ParentID = &parentID.UUID
+	}
+	if editedAt.Valid {
+		c.EditedAt = &editedAt.Time
+	}
+	return &c, nil
+}
@@ -0,0 +1,177 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+var commentRowColumns = []string{"id", "event_id", "parent_id", "author", "body", "created_at", "edited_at", "deleted", "reply_count"}
+
+func TestCreateComment_TellsMissingParentFromMissingEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCommentStore{db: db}
+	parentID := uuid.New()
+	c := &structures.Comment{ID: uuid.New(), EventID: uuid.New(), ParentID: &parentID, Author: "token:abc", Body: "hi", CreatedAt: time.Now()}
+	insert := regexp.QuoteMeta(`INSERT INTO comments`)
+	exists := regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`)
+
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs(c.EventID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs(c.EventID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	if _, err := store.CreateComment(context.Background(), c); !errors.Is(err, structures.ErrParentCommentNotFound) {
+		t.Errorf("expected ErrParentCommentNotFound, got %v", err)
+	}
+	if _, err := store.CreateComment(context.Background(), c); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestListComments_ReturnsNextCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCommentStore{db: db}
+	eventID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	t0 := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS`)).
+		WithArgs(eventID, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"event", "parent"}).AddRow(true, true))
+	mock.ExpectQuery(regexp.QuoteMeta(`(($2::uuid IS NULL AND c.parent_id IS NULL) OR c.parent_id = $2)`)).
+		WithArgs(eventID, nil, nil, uuid.Nil, 2).
+		WillReturnRows(sqlmock.NewRows(commentRowColumns).
+			AddRow(first, eventID, nil, "token:abc", "one", t0, nil, false, 2).
+			AddRow(second, eventID, nil, "token:abc", "", t0.Add(time.Minute), nil, true, 1))
+
+	page, err := store.ListComments(context.Background(), structures.CommentQuery{EventID: eventID, Limit: 1})
+	if err != nil {
+		t.Fatalf("ListComments returned error: %v", err)
+	}
+	if len(page.Comments) != 1 || page.Comments[0].ID != first || page.Comments[0].ReplyCount != 2 {
+		t.Fatalf("unexpected page: %+v", page.Comments)
+	}
+	cursor, err := structures.DecodeCommentCursor(page.NextCursor)
+	if err != nil || cursor.ID != first || !cursor.CreatedAt.Equal(t0) {
+		t.Errorf("unexpected cursor %+v (%v)", cursor, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestListComments_UnknownParent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCommentStore{db: db}
+	parentID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS`)).
+		WillReturnRows(sqlmock.NewRows([]string{"event", "parent"}).AddRow(true, false))
+
+	_, err = store.ListComments(context.Background(), structures.CommentQuery{EventID: uuid.New(), ParentID: &parentID, Limit: 20})
+	if !errors.Is(err, structures.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestDeleteComment_TombstonesCommentWithReplies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCommentStore{db: db}
+	eventID, id := uuid.New(), uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF c`)).
+		WithArgs(id, eventID).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id", "has_replies"}).AddRow(nil, true))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE comments SET body = '', edited_at = NULL, deleted_at = NOW()`)).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM comment_revisions`)).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	if err := store.DeleteComment(context.Background(), eventID, id); err != nil {
+		t.Fatalf("DeleteComment returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteComment_PrunesEmptyTombstones(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCommentStore{db: db}
+	eventID, id, parent, grandparent := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	prune := regexp.QuoteMeta(`DELETE FROM comments p`)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF c`)).
+		WithArgs(id, eventID).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id", "has_replies"}).AddRow(parent, false))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM comments WHERE id = $1`)).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	// The parent was a tombstone holding only this reply; the grandparent
+	// still has other replies.
+	mock.ExpectQuery(prune).WithArgs(parent).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(grandparent))
+	mock.ExpectQuery(prune).WithArgs(grandparent).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id"}))
+	mock.ExpectCommit()
+
+	if err := store.DeleteComment(context.Background(), eventID, id); err != nil {
+		t.Fatalf("DeleteComment returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteComment_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgCommentStore{db: db}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF c`)).
+		WillReturnRows(sqlmock.NewRows([]string{"parent_id", "has_replies"}))
+	mock.ExpectRollback()
+
+	if err := store.DeleteComment(context.Background(), uuid.New(), uuid.New()); !errors.Is(err, structures.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+}
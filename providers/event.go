@@ -3,8 +3,11 @@ package providers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"events/structures"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,28 +21,302 @@ func NewPGEventStore(db *sql.DB) *pgEventStore {
 }
 
 func (s *pgEventStore) CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	const q = `
-        INSERT INTO events (id, title, description, start_time, end_time, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO events (id, title, description, start_time, end_time, time_zone, all_day, capacity, created_at, status, metadata, calendar_id,
+                            location_name, location_address, latitude, longitude, search_language)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12, $13, $14, $15, $16, $17::regconfig)
     `
-	_, err := s.db.ExecContext(ctx, q,
+	metadata, err := metadataParam(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	locName, locAddress, lat, lng := locationParams(e.Location)
+	_, err = tx.ExecContext(ctx, q,
 		e.ID,
 		e.Title,
 		e.Description,
 		e.StartTime,
 		e.EndTime,
+		e.TimeZone,
+		e.AllDay,
+		e.Capacity,
 		e.CreatedAt,
+		e.Status,
+		metadata,
+		e.CalendarID,
+		locName,
+		locAddress,
+		lat,
+		lng,
+		e.Language,
 	)
-	return e, err
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Tags) > 0 {
+		if err := replaceEventTags(ctx, tx, e.ID, e.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := insertOutbox(ctx, tx, e.ID, structures.EventCreated, e); err != nil {
+		return nil, err
+	}
+	if err := insertHistory(ctx, tx, structures.HistoryCreate, nil, e); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return e, nil
 }
 
 func (s *pgEventStore) ListEvents(ctx context.Context) ([]structures.Event, error) {
-	const q = `
-        SELECT id, title, COALESCE(description, ''), start_time, end_time, created_at
-        FROM events
-        ORDER BY start_time ASC
+	const q = selectEvents + `
+        WHERE e.deleted_at IS NULL
+        ORDER BY e.start_time ASC
+    `
+	return s.queryEvents(ctx, q)
+}
+
+// QueryEvents returns one page of events in (start_time, id) order, the
+// order the cursor is based on. One extra row is read to tell whether
+// there is a next page. Events in the trash are only included on request.
+// A near search keeps the same order and reports each event's distance.
+func (s *pgEventStore) QueryEvents(ctx context.Context, q structures.EventQuery) (*structures.EventPage, error) {
+	const query = `
+        SELECT ` + selectEventColumns + `,
+               ` + haversineKm + fromEvents + `
+        WHERE ($1::timestamptz IS NULL OR e.end_time > $1)
+          AND ($2::timestamptz IS NULL OR e.start_time < $2)
+          AND ($3 = '' OR e.title ILIKE '%' || $3 || '%')
+          AND ($4::timestamptz IS NULL OR (e.start_time, e.id) > ($4, $5))
+          AND ($7 OR e.deleted_at IS NULL)
+          AND (cardinality($8::text[]) = 0 OR e.status = ANY($8::text[]))
+          AND (cardinality($9::text[]) = 0 OR e.id IN (
+                SELECT et.event_id
+                FROM event_tags et
+                JOIN tags tg ON tg.id = et.tag_id
+                WHERE tg.name = ANY($9::text[])
+                GROUP BY et.event_id
+                HAVING COUNT(*) = cardinality($9::text[])
+              ))
+          AND e.metadata @> $10::jsonb
+          AND ($11::uuid IS NULL OR e.calendar_id = $11)
+          AND ($19::uuid[] IS NULL OR e.calendar_id = ANY($19::uuid[]))
+          AND ($12::float8 IS NULL OR (
+                e.latitude BETWEEN $14 AND $15
+                AND e.longitude BETWEEN $16 AND $17
+                AND ` + haversineKm + ` <= $18
+              ))
+        ORDER BY e.start_time ASC, e.id ASC
+        LIMIT $6
+    `
+	var afterStart *time.Time
+	var afterID uuid.UUID
+	if q.After != nil {
+		afterStart, afterID = &q.After.StartTime, q.After.ID
+	}
+	contains, err := structures.MetadataContainment(q.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := metadataParam(contains)
+	if err != nil {
+		return nil, err
+	}
+	var near [7]*float64
+	if q.Near != nil {
+		minLat, maxLat, minLng, maxLng := q.Near.BoundingBox(q.RadiusKm)
+		near = [7]*float64{&q.Near.Lat, &q.Near.Lng, &minLat, &maxLat, &minLng, &maxLng, &q.RadiusKm}
+	}
+	rows, err := s.db.QueryContext(ctx, query, q.From, q.To, escapeLike(q.TitleContains), afterStart, afterID, q.Limit+1,
+		q.IncludeDeleted, nonNil(q.Statuses), nonNil(q.Tags), metadata, q.CalendarID,
+		near[0], near[1], near[2], near[3], near[4], near[5], near[6], calendarFilter(q.CalendarIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]structures.Event, 0)
+	for rows.Next() {
+		var distance *float64
+		e, err := scanEvent(rows, &distance)
+		if err != nil {
+			return nil, err
+		}
+		e.DistanceKm = distance
+		events = append(events, *e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	page := &structures.EventPage{Events: events}
+	if len(events) > q.Limit {
+		page.Events = events[:q.Limit]
+		page.NextCursor = structures.CursorOf(page.Events[q.Limit-1]).Encode()
+	}
+	return page, nil
+}
+
+// Headline options: the title is highlighted whole, the description is cut
+// down to the fragments around the matches.
+const (
+	highlightSelectors      = `StartSel="` + structures.HighlightStart + `", StopSel="` + structures.HighlightStop + `"`
+	titleHeadlineOpts       = highlightSelectors + `, HighlightAll=true`
+	descriptionHeadlineOpts = highlightSelectors + `, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`
+	highlightMarkers        = `'` + structures.HighlightStart + structures.HighlightStop + `'`
+)
+
+// SearchEvents matches q.Text against the search_vector of every event
+// outside the trash. With q.Language the query is parsed once in that
+// language, so the GIN index answers it; without it each event's query is
+// parsed in the event's own language.
+func (s *pgEventStore) SearchEvents(ctx context.Context, q structures.SearchQuery) (*structures.SearchPage, error) {
+	config := "e.search_language"
+	if q.Language != "" {
+		config = "$2::text::regconfig"
+	}
+	query := `
+        SELECT ` + selectEventColumns + `,
+               r.rank,
+               ts_headline(` + config + `, translate(e.title, ` + highlightMarkers + `, ''), q.query, '` + titleHeadlineOpts + `'),
+               ts_headline(` + config + `, translate(COALESCE(e.description, ''), ` + highlightMarkers + `, ''), q.query, '` + descriptionHeadlineOpts + `')` +
+		fromEvents + `
+        CROSS JOIN LATERAL (SELECT websearch_to_tsquery(` + config + `, $1) AS query) q
+        CROSS JOIN LATERAL (SELECT ts_rank(e.search_vector, q.query) AS rank) r
+        WHERE e.deleted_at IS NULL
+          AND e.search_vector @@ q.query
+          AND ($2::text IS NULL OR e.search_language = $2::text::regconfig)
+          AND ($3::real IS NULL OR r.rank < $3 OR (r.rank = $3 AND e.id > $4))
+          AND ($6::uuid[] IS NULL OR e.calendar_id = ANY($6::uuid[]))
+        ORDER BY r.rank DESC, e.id ASC
+        LIMIT $5
+    `
+	var language *string
+	if q.Language != "" {
+		language = &q.Language
+	}
+	var afterRank *float32
+	var afterID uuid.UUID
+	if q.After != nil {
+		afterRank, afterID = &q.After.Rank, q.After.ID
+	}
+	rows, err := s.db.QueryContext(ctx, query, q.Text, language, afterRank, afterID, q.Limit+1, calendarFilter(q.CalendarIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]structures.SearchResult, 0)
+	for rows.Next() {
+		var r structures.SearchResult
+		e, err := scanEvent(rows, &r.Rank, &r.TitleHeadline, &r.DescriptionHeadline)
+		if err != nil {
+			return nil, err
+		}
+		r.Event = *e
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	page := &structures.SearchPage{Results: results}
+	if len(results) > q.Limit {
+		page.Results = results[:q.Limit]
+		page.NextCursor = structures.SearchCursorOf(page.Results[q.Limit-1]).Encode()
+	}
+	return page, nil
+}
+
+// statsGroups are the group column of each StatsQuery.GroupBy and the
+// joins it needs.
+var statsGroups = map[string]struct{ column, joins string }{
+	"":                       {column: `NULL::text`},
+	structures.StatsByStatus: {column: `m.status`},
+	structures.StatsByTag: {column: `tg.name`, joins: `
+        JOIN event_tags et ON et.event_id = m.id
+        JOIN tags tg ON tg.id = et.tag_id`},
+}
+
+// EventStats counts the matching events per bucket and group in one
+// round trip: the row without a bucket carries the totals, so an event
+// with several tags is still counted once there.
+func (s *pgEventStore) EventStats(ctx context.Context, q structures.StatsQuery) (*structures.EventStats, error) {
+	loc, err := structures.LoadTimeZone(q.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	group := statsGroups[q.GroupBy]
+	query := `
+        WITH matched AS (
+            SELECT e.id, e.status,
+                   date_trunc($3, e.start_time, $4) AS bucket,
+                   EXTRACT(EPOCH FROM e.end_time - e.start_time) / 3600 AS hours
+            FROM events e
+            WHERE e.deleted_at IS NULL
+              AND e.start_time >= $1 AND e.start_time < $2
+              AND ($5::uuid[] IS NULL OR e.calendar_id = ANY($5::uuid[]))
+        )
+        SELECT m.bucket, ` + group.column + `, COUNT(*), SUM(m.hours)
+        FROM matched m` + group.joins + `
+        GROUP BY 1, 2
+        UNION ALL
+        SELECT NULL, NULL, COUNT(*), COALESCE(SUM(hours), 0) FROM matched
+        ORDER BY 1 NULLS FIRST, 2
+    `
+	rows, err := s.db.QueryContext(ctx, query, q.From, q.To, q.Interval, q.TimeZone, calendarFilter(q.CalendarIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &structures.EventStats{
+		Interval: q.Interval,
+		TimeZone: q.TimeZone,
+		GroupBy:  q.GroupBy,
+		Buckets:  make([]structures.StatsBucket, 0),
+	}
+	for rows.Next() {
+		var bucket *time.Time
+		var group *string
+		var events int
+		var hours float64
+		if err := rows.Scan(&bucket, &group, &events, &hours); err != nil {
+			return nil, err
+		}
+		if bucket == nil {
+			stats.Total = structures.StatsTotal{Events: events, Hours: hours}
+			continue
+		}
+		b := structures.StatsBucket{Start: bucket.In(loc), Events: events, Hours: hours}
+		if group != nil {
+			b.Group = *group
+		}
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetEvents returns the events with the given ids that exist, in no
+// particular order.
+func (s *pgEventStore) GetEvents(ctx context.Context, ids []uuid.UUID) ([]structures.Event, error) {
+	const q = selectEvents + `
+        WHERE e.id = ANY($1::uuid[]) AND e.deleted_at IS NULL
     `
-	rows, err := s.db.QueryContext(ctx, q)
+	return s.queryEvents(ctx, q, uuidStrings(ids))
+}
+
+func (s *pgEventStore) queryEvents(ctx context.Context, q string, args ...any) ([]structures.Event, error) {
+	rows, err := s.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -47,11 +324,11 @@ func (s *pgEventStore) ListEvents(ctx context.Context) ([]structures.Event, erro
 
 	events := make([]structures.Event, 0)
 	for rows.Next() {
-		var e structures.Event
-		if err := rows.Scan(&e.ID, &e.Title, &e.Description, &e.StartTime, &e.EndTime, &e.CreatedAt); err != nil {
+		e, err := scanEvent(rows)
+		if err != nil {
 			return nil, err
 		}
-		events = append(events, e)
+		events = append(events, *e)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -60,19 +337,473 @@ func (s *pgEventStore) ListEvents(ctx context.Context) ([]structures.Event, erro
 }
 
 func (s *pgEventStore) GetEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	const q = selectEvents + `
+        WHERE e.id = $1 AND e.deleted_at IS NULL
+    `
+	e, err := scanEvent(s.db.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// EventCalendarID returns the calendar of the event, including one in the
+// trash.
+func (s *pgEventStore) EventCalendarID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var calendarID uuid.UUID
+	err := s.db.QueryRowContext(ctx, `SELECT calendar_id FROM events WHERE id = $1`, id).Scan(&calendarID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, structures.ErrEventNotFound
+	}
+	return calendarID, err
+}
+
+// UpdateEvent replaces the editable fields of an event, tags, metadata
+// and location included. Resource bookings
+// are moved to the new time range (an overlap is reported as a
+// *structures.BookingConflictError) and, when the capacity grows or is
+// removed, waitlisted attendees are promoted into the new seats.
+func (s *pgEventStore) UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockEventCapacity(ctx, tx, e.ID); err != nil {
+		return nil, err
+	}
+
+	const readQ = selectEvents + `
+        WHERE e.id = $1
+    `
+	before, err := scanEvent(tx.QueryRowContext(ctx, readQ, e.ID))
+	if err != nil {
+		return nil, err
+	}
+
 	const q = `
-        SELECT id, title, COALESCE(description, ''), start_time, end_time, created_at
-        FROM events
+        UPDATE events
+        SET title = $2, description = $3, start_time = $4, end_time = $5,
+            time_zone = $6, all_day = $7, capacity = $8, metadata = $9::jsonb,
+            location_name = $10, location_address = $11, latitude = $12, longitude = $13,
+            search_language = $14::regconfig
         WHERE id = $1
     `
-	var e structures.Event
-	err := s.db.QueryRowContext(ctx, q, id).
-		Scan(&e.ID, &e.Title, &e.Description, &e.StartTime, &e.EndTime, &e.CreatedAt)
+	metadata, err := metadataParam(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	locName, locAddress, lat, lng := locationParams(e.Location)
+	_, err = tx.ExecContext(ctx, q,
+		e.ID,
+		e.Title,
+		e.Description,
+		e.StartTime,
+		e.EndTime,
+		e.TimeZone,
+		e.AllDay,
+		e.Capacity,
+		metadata,
+		locName,
+		locAddress,
+		lat,
+		lng,
+		e.Language,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := replaceEventTags(ctx, tx, e.ID, e.Tags); err != nil {
+		return nil, err
+	}
+
+	const bookingsQ = `
+        UPDATE event_resources
+        SET during = tstzrange($2, $3, '[)')
+        WHERE event_id = $1
+    `
+	_, err = tx.ExecContext(ctx, bookingsQ, e.ID, e.StartTime, e.EndTime)
+	if pgErrorCode(err) == pgExclusionViolation {
+		return nil, rescheduleConflict(ctx, s.db, e)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := promoteWaitlist(ctx, tx, e.ID, e.Capacity); err != nil {
+		return nil, err
+	}
+	if err := rearmReminders(ctx, tx, e); err != nil {
+		return nil, err
+	}
+
+	updated, err := scanEvent(tx.QueryRowContext(ctx, readQ, e.ID))
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, e.ID, structures.EventUpdated, updated); err != nil {
+		return nil, err
+	}
+	if err := insertHistory(ctx, tx, structures.HistoryUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteEvent moves an event to the trash. Its attendees and reminders are
+// kept for a restore, but its resource bookings are released so the
+// resources can be booked again.
+func (s *pgEventStore) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockEventCapacity(ctx, tx, id); err != nil {
+		return err
+	}
+
+	const readQ = selectEvents + `
+        WHERE e.id = $1
+    `
+	before, err := scanEvent(tx.QueryRowContext(ctx, readQ, id))
+	if err != nil {
+		return err
+	}
+
+	const q = `UPDATE events SET deleted_at = NOW() WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, q, id); err != nil {
+		return err
+	}
+	const bookingsQ = `DELETE FROM event_resources WHERE event_id = $1`
+	if _, err := tx.ExecContext(ctx, bookingsQ, id); err != nil {
+		return err
+	}
+	deleted, err := scanEvent(tx.QueryRowContext(ctx, readQ, id))
+	if err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, tx, id, structures.EventDeleted, deleted); err != nil {
+		return err
+	}
+	if err := insertHistory(ctx, tx, structures.HistoryDelete, before, deleted); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TransitionEvent moves an event to c.To, provided it is still in c.From,
+// and records c.Reason. Cancelling releases the event's resource bookings,
+// as deleting does; scheduling it again does not restore them. Checking
+// that the transition is allowed is the service's job.
+func (s *pgEventStore) TransitionEvent(ctx context.Context, c structures.StatusChange) (*structures.Event, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockEventCapacity(ctx, tx, c.EventID); err != nil {
+		return nil, err
+	}
+	const readQ = selectEvents + `
+        WHERE e.id = $1
+    `
+	before, err := scanEvent(tx.QueryRowContext(ctx, readQ, c.EventID))
+	if err != nil {
+		return nil, err
+	}
+	if before.Status != c.From {
+		return nil, structures.ErrStatusChanged
+	}
+
+	const q = `UPDATE events SET status = $2, status_reason = $3 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, q, c.EventID, c.To, c.Reason); err != nil {
+		return nil, err
+	}
+	if c.To == structures.StatusCancelled {
+		const bookingsQ = `DELETE FROM event_resources WHERE event_id = $1`
+		if _, err := tx.ExecContext(ctx, bookingsQ, c.EventID); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := scanEvent(tx.QueryRowContext(ctx, readQ, c.EventID))
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, c.EventID, structures.EventUpdated, updated); err != nil {
+		return nil, err
+	}
+	if err := insertHistory(ctx, tx, structures.HistoryUpdate, before, updated); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RestoreEvent takes an event out of the trash. Bookings released by the
+// delete are not restored. Consumers are told with an event.created
+// message, since they dropped the event on event.deleted.
+func (s *pgEventStore) RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const lockQ = `SELECT deleted_at IS NOT NULL FROM events WHERE id = $1 FOR UPDATE`
+	var deleted bool
+	err = tx.QueryRowContext(ctx, lockQ, id).Scan(&deleted)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, nil
+		return nil, structures.ErrEventNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
+	if !deleted {
+		return nil, structures.ErrEventNotDeleted
+	}
+
+	const readQ = selectEvents + `
+        WHERE e.id = $1
+    `
+	before, err := scanEvent(tx.QueryRowContext(ctx, readQ, id))
+	if err != nil {
+		return nil, err
+	}
+	const q = `UPDATE events SET deleted_at = NULL WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, q, id); err != nil {
+		return nil, err
+	}
+	restored, err := scanEvent(tx.QueryRowContext(ctx, readQ, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, id, structures.EventCreated, restored); err != nil {
+		return nil, err
+	}
+	if err := insertHistory(ctx, tx, structures.HistoryRestore, before, restored); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// PurgeDeletedEvents permanently deletes up to limit events that went to
+// the trash before cutoff, with their attendees and reminders. SKIP LOCKED
+// lets several replicas purge at once. Their history is kept.
+func (s *pgEventStore) PurgeDeletedEvents(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	const q = selectEvents + `
+        WHERE e.id IN (
+            SELECT id
+            FROM events
+            WHERE deleted_at < $1
+            ORDER BY deleted_at ASC
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+    `
+	rows, err := tx.QueryContext(ctx, q, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	var purged []*structures.Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		purged = append(purged, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, len(purged))
+	for i, e := range purged {
+		ids[i] = e.ID
+	}
+	const deleteQ = `DELETE FROM events WHERE id = ANY($1::uuid[])`
+	if _, err := tx.ExecContext(ctx, deleteQ, uuidStrings(ids)); err != nil {
+		return 0, err
+	}
+	for _, e := range purged {
+		if err := insertHistory(ctx, tx, structures.HistoryPurge, e, nil); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(purged), nil
+}
+
+// BusyIntervals returns the time ranges inside [q.From, q.To) during which
+// any of the requested resources is booked or any of the requested attendees
+// holds a seat at an event. Intervals are ordered by start but not merged.
+func (s *pgEventStore) BusyIntervals(ctx context.Context, q structures.AvailabilityQuery) ([]structures.Interval, error) {
+	const query = `
+        SELECT lower(er.during), upper(er.during)
+        FROM event_resources er
+        WHERE er.resource_id = ANY($1::uuid[])
+          AND er.during && tstzrange($3, $4, '[)')
+        UNION ALL
+        SELECT e.start_time, e.end_time
+        FROM events e
+        JOIN attendees a ON a.event_id = e.id
+        WHERE a.email = ANY($2::text[])
+          AND a.rsvp_status <> 'declined'
+          AND NOT a.waitlisted
+          AND e.start_time < $4
+          AND e.end_time > $3
+          AND e.deleted_at IS NULL
+          AND e.status <> 'cancelled'
+        ORDER BY 1 ASC
+    `
+	rows, err := s.db.QueryContext(ctx, query, uuidStrings(q.ResourceIDs), q.Attendees, q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	busy := make([]structures.Interval, 0)
+	for rows.Next() {
+		var in structures.Interval
+		if err := rows.Scan(&in.Start, &in.End); err != nil {
+			return nil, err
+		}
+		busy = append(busy, in)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return busy, nil
+}
+
+// selectEvents reads events together with their tags and attendee counts.
+// Tags come back as JSON because database/sql cannot scan text[]. Callers
+// append their own WHERE / ORDER BY clauses.
+const selectEvents = `
+        SELECT ` + selectEventColumns + fromEvents
+
+// selectEventColumns are the columns scanEvent reads, for queries that add
+// columns of their own after them.
+const selectEventColumns = `e.id, e.title, COALESCE(e.description, ''), e.start_time, e.end_time,
+               e.time_zone, e.all_day, e.capacity, e.created_at, e.status, e.status_reason, e.deleted_at,
+               e.metadata, COALESCE(t.tags, '[]'), e.calendar_id,
+               COALESCE(e.location_name, ''), COALESCE(e.location_address, ''), e.latitude, e.longitude,
+               e.search_language::text,
+               a.total, a.accepted, a.declined, a.tentative, a.pending, a.waitlisted`
+
+const fromEvents = `
+        FROM events e
+        LEFT JOIN LATERAL (
+            SELECT COUNT(*) AS total,
+                   COUNT(*) FILTER (WHERE rsvp_status = 'accepted') AS accepted,
+                   COUNT(*) FILTER (WHERE rsvp_status = 'declined') AS declined,
+                   COUNT(*) FILTER (WHERE rsvp_status = 'tentative') AS tentative,
+                   COUNT(*) FILTER (WHERE rsvp_status = 'pending') AS pending,
+                   COUNT(*) FILTER (WHERE waitlisted) AS waitlisted
+            FROM attendees
+            WHERE event_id = e.id
+        ) a ON TRUE
+        LEFT JOIN LATERAL (
+            SELECT json_agg(tg.name ORDER BY tg.name) AS tags
+            FROM event_tags et
+            JOIN tags tg ON tg.id = et.tag_id
+            WHERE et.event_id = e.id
+        ) t ON TRUE`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// uuidStrings formats ids for a $n::uuid[] parameter.
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+// calendarFilter formats a CalendarIDs filter for a $n::uuid[] parameter:
+// NULL keeps every calendar, an empty array none.
+func calendarFilter(ids []uuid.UUID) any {
+	if ids == nil {
+		return nil
+	}
+	return uuidStrings(ids)
+}
+
+// nonNil formats a filter for a $n::text[] parameter, which must not be
+// nil.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// escapeLike makes s match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// scanEvent reads the selectEventColumns of a row into an event, and any
+// further columns into extra.
+func scanEvent(row rowScanner, extra ...any) (*structures.Event, error) {
+	var e structures.Event
+	var metadata, tags []byte
+	var locName, locAddress string
+	var lat, lng *float64
+	dest := []any{
+		&e.ID, &e.Title, &e.Description, &e.StartTime, &e.EndTime,
+		&e.TimeZone, &e.AllDay, &e.Capacity, &e.CreatedAt, &e.Status, &e.StatusReason, &e.DeletedAt,
+		&metadata, &tags, &e.CalendarID,
+		&locName, &locAddress, &lat, &lng,
+		&e.Language,
+		&e.Attendees.Total, &e.Attendees.Accepted, &e.Attendees.Declined,
+		&e.Attendees.Tentative, &e.Attendees.Pending, &e.Attendees.Waitlisted,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	e.Location = scannedLocation(locName, locAddress, lat, lng)
+	if err := json.Unmarshal(metadata, &e.Metadata); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tags, &e.Tags); err != nil {
+		return nil, err
+	}
+	if len(e.Metadata) == 0 {
+		e.Metadata = nil
+	}
+	if len(e.Tags) == 0 {
+		e.Tags = nil
+	}
 	return &e, nil
 }
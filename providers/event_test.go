@@ -2,7 +2,10 @@ package providers
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,8 +15,17 @@ import (
 	"github.com/google/uuid"
 )
 
+var eventColumns = []string{
+	"id", "title", "description", "start_time", "end_time",
+	"time_zone", "all_day", "capacity", "created_at", "status", "status_reason", "deleted_at",
+	"metadata", "tags", "calendar_id",
+	"location_name", "location_address", "latitude", "longitude",
+	"language",
+	"total", "accepted", "declined", "tentative", "pending", "waitlisted",
+}
+
 func TestCreateEvent(t *testing.T) {
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
 	if err != nil {
 		t.Fatalf("sqlmock.New: %v", err)
 	}
@@ -21,6 +33,7 @@ func TestCreateEvent(t *testing.T) {
 
 	store := &pgEventStore{db: db}
 
+	lat, lng := 52.52, 13.405
 	e := &structures.Event{
 		ID:          uuid.New(),
 		Title:       "Test Event",
@@ -28,16 +41,38 @@ func TestCreateEvent(t *testing.T) {
 		StartTime:   time.Now().Add(time.Hour),
 		EndTime:     time.Now().Add(2 * time.Hour),
 		CreatedAt:   time.Now(),
+		Status:      structures.StatusDraft,
+		CalendarID:  structures.DefaultCalendarID,
+		Tags:        []string{"project:atlas", "team:payments"},
+		Metadata:    map[string]any{"cost_center": "cc-42"},
+		Location:    &structures.Location{Name: "HQ", Latitude: &lat, Longitude: &lng},
+		Language:    "english",
 	}
 
 	query := regexp.QuoteMeta(`
-        INSERT INTO events (id, title, description, start_time, end_time, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO events (id, title, description, start_time, end_time, time_zone, all_day, capacity, created_at, status, metadata, calendar_id,
+                            location_name, location_address, latitude, longitude, search_language)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12, $13, $14, $15, $16, $17::regconfig)
     `)
 
+	mock.ExpectBegin()
 	mock.ExpectExec(query).
-		WithArgs(e.ID, e.Title, e.Description, e.StartTime, e.EndTime, e.CreatedAt).
+		WithArgs(e.ID, e.Title, e.Description, e.StartTime, e.EndTime, e.TimeZone, e.AllDay, e.Capacity, e.CreatedAt, e.Status, `{"cost_center":"cc-42"}`, e.CalendarID,
+			"HQ", nil, 52.52, 13.405, "english").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM event_tags WHERE event_id = $1`)).
+		WithArgs(e.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tags (name)`)).
+		WithArgs(e.Tags).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO event_tags (event_id, tag_id)`)).
+		WithArgs(e.ID, e.Tags).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectOutbox(mock, e.ID, structures.EventCreated)
+	expectHistory(mock, e.ID, structures.HistoryCreate, structures.SystemActor,
+		"id", "calendar_id", "title", "description", "start_time", "end_time", "time_zone", "all_day", "created_at", "status", "tags", "metadata", "location", "language")
+	mock.ExpectCommit()
 
 	got, err := store.CreateEvent(context.Background(), e)
 	if err != nil {
@@ -64,15 +99,13 @@ func TestListEvents(t *testing.T) {
 	now := time.Now().UTC()
 	eID := uuid.New()
 
-	query := regexp.QuoteMeta(`
-        SELECT id, title, COALESCE(description, ''), start_time, end_time, created_at
-        FROM events
-        ORDER BY start_time ASC
+	query := regexp.QuoteMeta(selectEvents + `
+        WHERE e.deleted_at IS NULL
+        ORDER BY e.start_time ASC
     `)
 
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "description", "start_time", "end_time", "created_at",
-	}).AddRow(eID, "Test Event", "desc", now, now.Add(time.Hour), now)
+	rows := sqlmock.NewRows(eventColumns).
+		AddRow(eID, "Test Event", "desc", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 3, 1, 1, 0, 1, 0)
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 
@@ -92,6 +125,188 @@ func TestListEvents(t *testing.T) {
 	}
 }
 
+func TestQueryEvents_ReturnsNextCursor(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	now := time.Now().UTC()
+	after := &structures.EventCursor{StartTime: now.Add(-time.Hour), ID: uuid.New()}
+	first, second, extra := uuid.New(), uuid.New(), uuid.New()
+	calendarID := uuid.New()
+
+	// One row more than the limit tells the store there is a next page.
+	mock.ExpectQuery(regexp.QuoteMeta(`(e.start_time, e.id) > ($4, $5)`)).
+		WithArgs(nil, nil, `50\%`, after.StartTime, after.ID, 3, false, []string{structures.StatusScheduled},
+			[]string{"team:payments"}, `{"room":{"floor":"3"}}`, &calendarID, nil, nil, nil, nil, nil, nil, nil, []string{calendarID.String()}).
+		WillReturnRows(sqlmock.NewRows(append(eventColumns, "distance_km")).
+			AddRow(first, "a", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0, nil).
+			AddRow(second, "b", "", now.Add(time.Hour), now.Add(2*time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0, nil).
+			AddRow(extra, "c", "", now.Add(2*time.Hour), now.Add(3*time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0, nil))
+
+	page, err := store.QueryEvents(context.Background(), structures.EventQuery{
+		TitleContains: "50%", After: after, Limit: 2, Statuses: []string{structures.StatusScheduled},
+		Tags: []string{"team:payments"}, Metadata: map[string]string{"room.floor": "3"}, CalendarID: &calendarID,
+		CalendarIDs: []uuid.UUID{calendarID},
+	})
+	if err != nil {
+		t.Fatalf("QueryEvents returned error: %v", err)
+	}
+	if len(page.Events) != 2 || page.Events[1].ID != second {
+		t.Fatalf("unexpected page: %+v", page.Events)
+	}
+	next, err := structures.DecodeEventCursor(page.NextCursor)
+	if err != nil || next.ID != second || !next.StartTime.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the cursor to point at the last event, got %+v (%v)", next, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestQueryEvents_Near(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	now := time.Now().UTC()
+	id := uuid.New()
+	near := structures.GeoPoint{Lat: 52.52, Lng: 13.405}
+	minLat, maxLat, minLng, maxLng := near.BoundingBox(5)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`e.latitude BETWEEN $14 AND $15`)).
+		WithArgs(nil, nil, "", nil, uuid.Nil, 11, false, []string{}, []string{}, "{}", nil,
+			near.Lat, near.Lng, minLat, maxLat, minLng, maxLng, 5.0, nil).
+		WillReturnRows(sqlmock.NewRows(append(eventColumns, "distance_km")).
+			AddRow(id, "a", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID,
+				"Gendarmenmarkt", "", 52.5137, 13.3927, "english", 0, 0, 0, 0, 0, 0, 1.13))
+
+	page, err := store.QueryEvents(context.Background(), structures.EventQuery{Limit: 10, Near: &near, RadiusKm: 5})
+	if err != nil {
+		t.Fatalf("QueryEvents returned error: %v", err)
+	}
+	if len(page.Events) != 1 {
+		t.Fatalf("unexpected page: %+v", page.Events)
+	}
+	got := page.Events[0]
+	if got.DistanceKm == nil || *got.DistanceKm != 1.13 {
+		t.Fatalf("expected the distance to be read, got %v", got.DistanceKm)
+	}
+	if got.Location == nil || got.Location.Name != "Gendarmenmarkt" || got.Location.Latitude == nil || *got.Location.Latitude != 52.5137 {
+		t.Fatalf("unexpected location: %+v", got.Location)
+	}
+	if minLat >= near.Lat || maxLng <= near.Lng || maxLat-minLat > 0.1 {
+		t.Fatalf("unexpected bounding box: %v..%v, %v..%v", minLat, maxLat, minLng, maxLng)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestEventStats_ByTag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	week := time.Date(2026, 3, 2, 0, 0, 0, 0, madrid)
+	q := structures.StatsQuery{From: week, To: week.AddDate(0, 0, 14), Interval: structures.StatsWeek, TimeZone: "Europe/Madrid", GroupBy: structures.StatsByTag}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`JOIN tags tg ON tg.id = et.tag_id`)).
+		WithArgs(q.From, q.To, "week", "Europe/Madrid", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "group", "count", "hours"}).
+			AddRow(nil, nil, 3, 4.5).
+			AddRow(week.UTC(), "team:payments", 2, 3.0).
+			AddRow(week.UTC(), "type:review", 2, 2.5))
+
+	stats, err := store.EventStats(context.Background(), q)
+	if err != nil {
+		t.Fatalf("EventStats returned error: %v", err)
+	}
+	if stats.Total != (structures.StatsTotal{Events: 3, Hours: 4.5}) || stats.GroupBy != structures.StatsByTag {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if len(stats.Buckets) != 2 || stats.Buckets[0].Group != "team:payments" || stats.Buckets[1].Hours != 2.5 {
+		t.Fatalf("unexpected buckets: %+v", stats.Buckets)
+	}
+	if start := stats.Buckets[0].Start; !start.Equal(week) || start.Location().String() != "Europe/Madrid" {
+		t.Errorf("expected the bucket to start at midnight in Madrid, got %v", start)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSearchEvents_InOneLanguage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	now := time.Now().UTC()
+	after := structures.SearchCursor{Rank: 0.5, ID: uuid.New()}
+	first, second := uuid.New(), uuid.New()
+	columns := append(eventColumns, "rank", "title_headline", "description_headline")
+	mark := func(s string) string { return structures.HighlightStart + s + structures.HighlightStop }
+
+	mock.ExpectQuery(regexp.QuoteMeta(`websearch_to_tsquery($2::text::regconfig, $1)`)).
+		WithArgs("planificación", "spanish", 0.5, after.ID, 2, nil).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(first, "Planificación", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "spanish", 0, 0, 0, 0, 0, 0,
+				0.25, mark("Planificación"), "").
+			AddRow(second, "Planificar", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "spanish", 0, 0, 0, 0, 0, 0,
+				0.125, mark("Planificar"), ""))
+
+	page, err := store.SearchEvents(context.Background(), structures.SearchQuery{Text: "planificación", Language: "spanish", After: &after, Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchEvents returned error: %v", err)
+	}
+	if len(page.Results) != 1 || page.Results[0].Event.ID != first || page.Results[0].Rank != 0.25 || page.Results[0].TitleHeadline != mark("Planificación") {
+		t.Fatalf("unexpected page: %+v", page.Results)
+	}
+	cursor, err := structures.DecodeSearchCursor(page.NextCursor)
+	if err != nil || cursor.ID != first || cursor.Rank != 0.25 {
+		t.Errorf("unexpected cursor %+v (%v)", cursor, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSearchEvents_EachInItsOwnLanguage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`websearch_to_tsquery(e.search_language, $1)`)).
+		WithArgs("planning", nil, nil, uuid.Nil, 21, nil).
+		WillReturnRows(sqlmock.NewRows(append(eventColumns, "rank", "title_headline", "description_headline")))
+
+	page, err := store.SearchEvents(context.Background(), structures.SearchQuery{Text: "planning", Limit: 20})
+	if err != nil || len(page.Results) != 0 || page.NextCursor != "" {
+		t.Fatalf("SearchEvents = %+v, %v", page, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
 func TestGetEvent_Found(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -104,15 +319,13 @@ func TestGetEvent_Found(t *testing.T) {
 	now := time.Now().UTC()
 	eID := uuid.New()
 
-	query := regexp.QuoteMeta(`
-        SELECT id, title, COALESCE(description, ''), start_time, end_time, created_at
-        FROM events
-        WHERE id = $1
+	query := regexp.QuoteMeta(selectEvents + `
+        WHERE e.id = $1 AND e.deleted_at IS NULL
     `)
 
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "description", "start_time", "end_time", "created_at",
-	}).AddRow(eID, "Test Event", "desc", now, now.Add(time.Hour), now)
+	rows := sqlmock.NewRows(eventColumns).
+		AddRow(eID, "Test Event", "desc", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil,
+			`{"room": {"floor": 3}}`, `["project:atlas", "team:payments"]`, structures.DefaultCalendarID, "", "", nil, nil, "english", 3, 1, 1, 0, 1, 0)
 
 	mock.ExpectQuery(query).
 		WithArgs(eID).
@@ -125,6 +338,13 @@ func TestGetEvent_Found(t *testing.T) {
 	if e == nil || e.ID != eID {
 		t.Fatalf("unexpected event: %+v", e)
 	}
+	if strings.Join(e.Tags, ",") != "project:atlas,team:payments" || e.Metadata["room"].(map[string]any)["floor"] != 3.0 {
+		t.Fatalf("unexpected tags or metadata: %v %v", e.Tags, e.Metadata)
+	}
+	want := structures.AttendeeCounts{Total: 3, Accepted: 1, Declined: 1, Pending: 1}
+	if e.Attendees != want {
+		t.Fatalf("unexpected attendee counts: got %+v, want %+v", e.Attendees, want)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("unmet expectations: %v", err)
@@ -140,17 +360,13 @@ func TestGetEvent_NotFound(t *testing.T) {
 
 	store := &pgEventStore{db: db}
 
-	query := regexp.QuoteMeta(`
-        SELECT id, title, COALESCE(description, ''), start_time, end_time, created_at
-        FROM events
-        WHERE id = $1
+	query := regexp.QuoteMeta(selectEvents + `
+        WHERE e.id = $1 AND e.deleted_at IS NULL
     `)
 
 	mock.ExpectQuery(query).
 		WithArgs(sqlmock.AnyArg()).
-		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "title", "description", "start_time", "end_time", "created_at",
-		})) // no rows
+		WillReturnRows(sqlmock.NewRows(eventColumns)) // no rows
 
 	e, err := store.GetEvent(context.Background(), uuid.New())
 	if err != nil {
@@ -164,3 +380,385 @@ func TestGetEvent_NotFound(t *testing.T) {
 		t.Fatalf("unmet expectations: %v", err)
 	}
 }
+
+func TestEventCalendarID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	trashed, missing, calendarID := uuid.New(), uuid.New(), uuid.New()
+
+	// Events in the trash still belong to their calendar.
+	query := regexp.QuoteMeta(`SELECT calendar_id FROM events WHERE id = $1`)
+	mock.ExpectQuery(query).
+		WithArgs(trashed).
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_id"}).AddRow(calendarID))
+	mock.ExpectQuery(query).
+		WithArgs(missing).
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_id"}))
+
+	if got, err := store.EventCalendarID(context.Background(), trashed); err != nil || got != calendarID {
+		t.Fatalf("EventCalendarID = %v, %v; want %v", got, err, calendarID)
+	}
+	if _, err := store.EventCalendarID(context.Background(), missing); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// arrayConverter lets sqlmock accept the slice arguments that pgx encodes as
+// Postgres arrays.
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v any) (driver.Value, error) {
+	switch v.(type) {
+	case []string:
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func TestBusyIntervals(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+
+	from := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+	roomID := uuid.New()
+
+	mock.ExpectQuery(`FROM event_resources er(.|\n)*AND e.status <> 'cancelled'`).
+		WithArgs([]string{roomID.String()}, []string{"ana@example.com"}, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"lower", "upper"}).
+			AddRow(from.Add(time.Hour), from.Add(2*time.Hour)))
+
+	busy, err := store.BusyIntervals(context.Background(), structures.AvailabilityQuery{
+		ResourceIDs: []uuid.UUID{roomID},
+		Attendees:   []string{"ana@example.com"},
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		t.Fatalf("BusyIntervals returned error: %v", err)
+	}
+	if len(busy) != 1 || !busy[0].Start.Equal(from.Add(time.Hour)) {
+		t.Fatalf("unexpected busy intervals: %v", busy)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpdateEvent_WritesOutbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+
+	now := time.Now().UTC()
+	e := &structures.Event{
+		ID:        uuid.New(),
+		Title:     "Renamed",
+		StartTime: now,
+		EndTime:   now.Add(time.Hour),
+		TimeZone:  "UTC",
+		Language:  "english",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(e.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(e.ID).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(e.ID, "Original", "", e.StartTime, e.EndTime, "UTC", false, nil, now, "scheduled", "", nil, "{}", `["team:payments"]`, structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE events`)).
+		WithArgs(e.ID, e.Title, e.Description, e.StartTime, e.EndTime, e.TimeZone, e.AllDay, e.Capacity, "{}", nil, nil, nil, nil, "english").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The event had tags; a PUT without them clears them.
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM event_tags WHERE event_id = $1`)).
+		WithArgs(e.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE event_resources`)).
+		WithArgs(e.ID, e.StartTime, e.EndTime).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SET waitlisted = FALSE`)).
+		WillReturnRows(sqlmock.NewRows(attendeeRowColumns))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE reminders`)).
+		WithArgs(e.ID, e.StartTime).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(e.ID).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(e.ID, e.Title, "", e.StartTime, e.EndTime, "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	expectOutbox(mock, e.ID, structures.EventUpdated)
+	expectHistory(mock, e.ID, structures.HistoryUpdate, "token:abc", "title", "tags")
+	mock.ExpectCommit()
+
+	ctx := structures.WithActor(context.Background(), "token:abc")
+	got, err := store.UpdateEvent(ctx, e)
+	if err != nil {
+		t.Fatalf("UpdateEvent returned error: %v", err)
+	}
+	if got.Title != "Renamed" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteEvent_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}))
+	mock.ExpectRollback()
+
+	err = store.DeleteEvent(context.Background(), uuid.New())
+	if !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteEvent_MovesToTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET deleted_at = NOW() WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM event_resources WHERE event_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", now, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	expectOutbox(mock, id, structures.EventDeleted)
+	expectHistory(mock, id, structures.HistoryDelete, structures.SystemActor, "deleted_at")
+	mock.ExpectCommit()
+
+	if err := store.DeleteEvent(context.Background(), id); err != nil {
+		t.Fatalf("DeleteEvent returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestRestoreEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+	now := time.Now().UTC()
+	lockQ := regexp.QuoteMeta(`SELECT deleted_at IS NOT NULL FROM events WHERE id = $1 FOR UPDATE`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQ).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", now, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET deleted_at = NULL WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	expectOutbox(mock, id, structures.EventCreated)
+	expectHistory(mock, id, structures.HistoryRestore, structures.SystemActor, "deleted_at")
+	mock.ExpectCommit()
+
+	e, err := store.RestoreEvent(context.Background(), id)
+	if err != nil || e.DeletedAt != nil {
+		t.Fatalf("RestoreEvent = %+v, %v", e, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQ).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"deleted"}).AddRow(false))
+	mock.ExpectRollback()
+	if _, err := store.RestoreEvent(context.Background(), id); !errors.Is(err, structures.ErrEventNotDeleted) {
+		t.Fatalf("expected ErrEventNotDeleted for a live event, got %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQ).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"deleted"}))
+	mock.ExpectRollback()
+	if _, err := store.RestoreEvent(context.Background(), id); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestPurgeDeletedEvents(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+	now := time.Now().UTC()
+	cutoff := now.AddDate(0, 0, -30)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE SKIP LOCKED`)).
+		WithArgs(cutoff, 100).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Old", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", cutoff.Add(-time.Hour), "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM events WHERE id = ANY($1::uuid[])`)).
+		WithArgs([]string{id.String()}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectHistory(mock, id, structures.HistoryPurge, structures.SystemActor,
+		"id", "calendar_id", "title", "start_time", "end_time", "time_zone", "all_day", "created_at", "status", "deleted_at", "language")
+	mock.ExpectCommit()
+
+	n, err := store.PurgeDeletedEvents(context.Background(), cutoff, 100)
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeDeletedEvents = %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestTransitionEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+	now := time.Now().UTC()
+	change := structures.StatusChange{EventID: id, From: structures.StatusScheduled, To: structures.StatusCancelled, Reason: "venue closed"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET status = $2, status_reason = $3 WHERE id = $1`)).
+		WithArgs(id, change.To, change.Reason).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Cancelling frees the rooms the event had booked.
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM event_resources WHERE event_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "cancelled", "venue closed", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	expectOutbox(mock, id, structures.EventUpdated)
+	expectHistory(mock, id, structures.HistoryUpdate, structures.SystemActor, "status", "status_reason")
+	mock.ExpectCommit()
+
+	e, err := store.TransitionEvent(context.Background(), change)
+	if err != nil || e.Status != structures.StatusCancelled || e.StatusReason != "venue closed" {
+		t.Fatalf("TransitionEvent = %+v, %v", e, err)
+	}
+
+	// Another request changed the status since the service read it.
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "postponed", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectRollback()
+	if _, err := store.TransitionEvent(context.Background(), change); !errors.Is(err, structures.ErrStatusChanged) {
+		t.Fatalf("expected ErrStatusChanged, got %v", err)
+	}
+
+	// Postponing keeps the bookings.
+	postpone := structures.StatusChange{EventID: id, From: structures.StatusScheduled, To: structures.StatusPostponed}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockEventQuery)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(nil))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "scheduled", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET status = $2, status_reason = $3 WHERE id = $1`)).
+		WithArgs(id, postpone.To, postpone.Reason).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(id, "Standup", "", now, now.Add(time.Hour), "UTC", false, nil, now, "postponed", "", nil, "{}", "[]", structures.DefaultCalendarID, "", "", nil, nil, "english", 0, 0, 0, 0, 0, 0))
+	expectOutbox(mock, id, structures.EventUpdated)
+	expectHistory(mock, id, structures.HistoryUpdate, structures.SystemActor, "status")
+	mock.ExpectCommit()
+	if _, err := store.TransitionEvent(context.Background(), postpone); err != nil {
+		t.Fatalf("TransitionEvent(postpone) returned error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
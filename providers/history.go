@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"events/structures"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// insertHistory appends a row to event_history inside the caller's
+// transaction, attributed to the actor in ctx. before is nil on create and
+// after is nil on delete.
+func insertHistory(ctx context.Context, tx *sql.Tx, operation string, before, after *structures.Event) error {
+	const q = `
+        INSERT INTO event_history (event_id, operation, actor, snapshot, diff)
+        VALUES ($1, $2, $3, $4::jsonb, $5::jsonb)
+    `
+	snapshot := after
+	if snapshot == nil {
+		snapshot = before
+	}
+	diff, err := structures.DiffEvents(before, after)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, q, snapshot.ID, operation, structures.ActorFromContext(ctx), string(snapshotJSON), string(diffJSON))
+	return err
+}
+
+// ListEventHistory returns the recorded mutations of an event, oldest
+// first. The history of a deleted event is still returned; an id that was
+// never seen is structures.ErrEventNotFound.
+func (s *pgEventStore) ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error) {
+	const q = `
+        SELECT id, event_id, operation, actor, changed_at, diff
+        FROM event_history
+        WHERE event_id = $1
+        ORDER BY changed_at ASC, id ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]structures.EventHistoryEntry, 0)
+	for rows.Next() {
+		var h structures.EventHistoryEntry
+		var diff []byte
+		if err := rows.Scan(&h.ID, &h.EventID, &h.Operation, &h.Actor, &h.ChangedAt, &diff); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(diff, &h.Diff); err != nil {
+			return nil, err
+		}
+		entries = append(entries, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return entries, nil
+	}
+
+	// Events created before history was recorded have none.
+	e, err := s.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, structures.ErrEventNotFound
+	}
+	return entries, nil
+}
+
+// GetEventAsOf rebuilds the event from the last history entry at or before
+// at. It returns nil when the event did not exist then, was in the trash
+// or had been purged, or has no history that old.
+func (s *pgEventStore) GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*structures.Event, error) {
+	const q = `
+        SELECT operation, snapshot
+        FROM event_history
+        WHERE event_id = $1 AND changed_at <= $2
+        ORDER BY changed_at DESC, id DESC
+        LIMIT 1
+    `
+	var operation string
+	var snapshot []byte
+	err := s.db.QueryRowContext(ctx, q, id, at).Scan(&operation, &snapshot)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case operation == structures.HistoryDelete, operation == structures.HistoryPurge:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e structures.Event
+	if err := json.Unmarshal(snapshot, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
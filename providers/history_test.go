@@ -0,0 +1,141 @@
+package providers
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+var historyColumns = []string{"id", "event_id", "operation", "actor", "changed_at", "diff"}
+
+// expectHistory expects an event_history insert whose diff names exactly
+// the given fields.
+func expectHistory(mock sqlmock.Sqlmock, eventID any, operation, actor string, fields ...string) {
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO event_history`)).
+		WithArgs(eventID, operation, actor, sqlmock.AnyArg(), diffFields(fields)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+type diffFields []string
+
+func (want diffFields) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	var diff map[string]structures.FieldChange
+	if err := json.Unmarshal([]byte(s), &diff); err != nil || len(diff) != len(want) {
+		return false
+	}
+	for _, name := range want {
+		if _, ok := diff[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func TestListEventHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM event_history`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(historyColumns).
+			AddRow(1, id, "create", "token:abc", now, `{"title":{"before":null,"after":"Standup"}}`).
+			AddRow(2, id, "update", "token:abc", now, `{"title":{"before":"Standup","after":"Retro"}}`))
+
+	got, err := store.ListEventHistory(context.Background(), id)
+	if err != nil {
+		t.Fatalf("ListEventHistory returned error: %v", err)
+	}
+	if len(got) != 2 || got[1].Diff["title"].After != "Retro" || got[0].Actor != "token:abc" {
+		t.Fatalf("unexpected history: %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestListEventHistory_UnknownEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM event_history`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(historyColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(selectEvents)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(eventColumns))
+
+	if _, err := store.ListEventHistory(context.Background(), id); err != structures.ErrEventNotFound {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetEventAsOf(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgEventStore{db: db}
+	id := uuid.New()
+	at := time.Now().Add(-time.Hour)
+	query := regexp.QuoteMeta(`changed_at <= $2`)
+
+	mock.ExpectQuery(query).
+		WithArgs(id, at).
+		WillReturnRows(sqlmock.NewRows([]string{"operation", "snapshot"}).
+			AddRow("update", `{"id":"`+id.String()+`","title":"Standup"}`))
+	e, err := store.GetEventAsOf(context.Background(), id, at)
+	if err != nil || e == nil || e.Title != "Standup" {
+		t.Fatalf("GetEventAsOf = %+v, %v", e, err)
+	}
+
+	mock.ExpectQuery(query).
+		WithArgs(id, at).
+		WillReturnRows(sqlmock.NewRows([]string{"operation", "snapshot"}).
+			AddRow("delete", `{"id":"`+id.String()+`","title":"Standup"}`))
+	if e, err := store.GetEventAsOf(context.Background(), id, at); err != nil || e != nil {
+		t.Fatalf("expected no event after its deletion, got %+v, %v", e, err)
+	}
+
+	mock.ExpectQuery(query).
+		WithArgs(id, at).
+		WillReturnRows(sqlmock.NewRows([]string{"operation", "snapshot"}))
+	if e, err := store.GetEventAsOf(context.Background(), id, at); err != nil || e != nil {
+		t.Fatalf("expected no event before its creation, got %+v, %v", e, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
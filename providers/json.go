@@ -0,0 +1,21 @@
+package providers
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// jsonParam encodes v for a $n::jsonb placeholder, mapping nil to NULL.
+func jsonParam(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
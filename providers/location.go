@@ -0,0 +1,32 @@
+package providers
+
+import (
+	"database/sql"
+	"events/structures"
+)
+
+// haversineKm is the distance in km between an event's location and the
+// point ($12, $13), or NULL when either is missing. The radius matches
+// structures.EarthRadiusKm.
+const haversineKm = `(2 * 6371.0088 * asin(least(1, sqrt(
+            power(sin(radians(e.latitude - $12) / 2), 2) +
+            cos(radians($12)) * cos(radians(e.latitude)) * power(sin(radians(e.longitude - $13) / 2), 2)))))`
+
+// locationParams formats a location for the location columns, which are
+// NULL when unset.
+func locationParams(l *structures.Location) (name, address sql.NullString, lat, lng *float64) {
+	if l == nil {
+		return
+	}
+	name = sql.NullString{String: l.Name, Valid: l.Name != ""}
+	address = sql.NullString{String: l.Address, Valid: l.Address != ""}
+	return name, address, l.Latitude, l.Longitude
+}
+
+// scannedLocation builds the location of a row, or nil when it has none.
+func scannedLocation(name, address string, lat, lng *float64) *structures.Location {
+	if name == "" && address == "" && lat == nil && lng == nil {
+		return nil
+	}
+	return &structures.Location{Name: name, Address: address, Latitude: lat, Longitude: lng}
+}
@@ -0,0 +1,166 @@
+package providers
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"events/structures"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxChannel is the LISTEN/NOTIFY channel that announces new outbox
+// rows. The notification payload is the row id.
+const OutboxChannel = "outbox"
+
+// insertOutbox records a change notification inside the caller's
+// transaction, so it is committed (or rolled back) together with the
+// mutation it describes. Postgres only delivers the NOTIFY on commit.
+func insertOutbox(ctx context.Context, tx *sql.Tx, eventID uuid.UUID, msgType string, payload any) error {
+	const q = `
+        WITH m AS (
+            INSERT INTO outbox (event_id, event_type, payload)
+            VALUES ($1, $2, $3::jsonb)
+            RETURNING id
+        )
+        SELECT pg_notify('` + OutboxChannel + `', m.id::text) FROM m
+    `
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, q, eventID, msgType, string(b))
+	return err
+}
+
+type pgOutboxStore struct {
+	db *sql.DB
+}
+
+func NewPGOutboxStore(db *sql.DB) *pgOutboxStore {
+	return &pgOutboxStore{db: db}
+}
+
+// ClaimOutbox leases up to limit due messages to the caller by pushing
+// their next_attempt_at forward by lease. SKIP LOCKED lets several relays
+// claim disjoint batches; if a relay dies mid-batch the lease expires and
+// the messages are claimed again, which gives at-least-once delivery.
+func (s *pgOutboxStore) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]structures.OutboxMessage, error) {
+	const q = `
+        UPDATE outbox
+        SET next_attempt_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id
+            FROM outbox
+            WHERE published_at IS NULL AND next_attempt_at <= NOW()
+            ORDER BY id ASC
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, event_id, event_type, payload, created_at, attempts
+    `
+	rows, err := s.db.QueryContext(ctx, q, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	msgs, err := scanOutboxMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING does not preserve the sub-select order.
+	slices.SortFunc(msgs, func(a, b structures.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return msgs, nil
+}
+
+// OutboxAfter returns up to limit messages with an id above afterID,
+// published or not, in id order.
+func (s *pgOutboxStore) OutboxAfter(ctx context.Context, afterID int64, limit int) ([]structures.OutboxMessage, error) {
+	const q = `
+        SELECT id, event_id, event_type, payload, created_at, attempts
+        FROM outbox
+        WHERE id > $1
+        ORDER BY id ASC
+        LIMIT $2
+    `
+	rows, err := s.db.QueryContext(ctx, q, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanOutboxMessages(rows)
+}
+
+func (s *pgOutboxStore) LatestOutboxID(ctx context.Context) (int64, error) {
+	const q = `SELECT COALESCE(MAX(id), 0) FROM outbox`
+	var id int64
+	err := s.db.QueryRowContext(ctx, q).Scan(&id)
+	return id, err
+}
+
+func (s *pgOutboxStore) MarkOutboxPublished(ctx context.Context, id int64) error {
+	const q = `UPDATE outbox SET published_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := s.db.ExecContext(ctx, q, id)
+	return err
+}
+
+func (s *pgOutboxStore) MarkOutboxFailed(ctx context.Context, id int64, retryAt time.Time, cause string) error {
+	const q = `
+        UPDATE outbox
+        SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+        WHERE id = $1
+    `
+	_, err := s.db.ExecContext(ctx, q, id, retryAt, cause)
+	return err
+}
+
+// PurgeOutbox deletes up to limit published messages created before
+// cutoff. Messages a webhook delivery is still pending for are kept, as
+// deleting them would cascade to the delivery.
+func (s *pgOutboxStore) PurgeOutbox(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	const q = `
+        DELETE FROM outbox
+        WHERE id IN (
+            SELECT o.id
+            FROM outbox o
+            WHERE o.published_at IS NOT NULL AND o.created_at < $1
+              AND NOT EXISTS (
+                  SELECT 1
+                  FROM webhook_deliveries d
+                  WHERE d.message_id = o.id AND d.status = 'pending'
+              )
+            ORDER BY o.id ASC
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+    `
+	res, err := s.db.ExecContext(ctx, q, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func scanOutboxMessages(rows *sql.Rows) ([]structures.OutboxMessage, error) {
+	msgs := make([]structures.OutboxMessage, 0)
+	for rows.Next() {
+		var m structures.OutboxMessage
+		var payload []byte
+		if err := rows.Scan(&m.ID, &m.EventID, &m.Type, &payload, &m.CreatedAt, &m.Attempts); err != nil {
+			return nil, err
+		}
+		m.Payload = json.RawMessage(payload)
+		msgs = append(msgs, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
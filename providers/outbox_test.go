@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+// expectOutbox expects the outbox row every mutation writes in its
+// transaction.
+func expectOutbox(mock sqlmock.Sqlmock, eventID any, msgType string) {
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox`)).
+		WithArgs(eventID, msgType, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestClaimOutbox_ReturnsMessagesInIDOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgOutboxStore{db: db}
+	now := time.Now().UTC()
+	eventID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE SKIP LOCKED`)).
+		WithArgs(10, float64(30)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "event_type", "payload", "created_at", "attempts"}).
+			AddRow(int64(7), eventID, "event.updated", []byte(`{"id":"x"}`), now, 0).
+			AddRow(int64(3), eventID, "event.created", []byte(`{"id":"x"}`), now, 2))
+
+	msgs, err := store.ClaimOutbox(context.Background(), 10, 30*time.Second)
+	if err != nil {
+		t.Fatalf("ClaimOutbox returned error: %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].ID != 3 || msgs[1].ID != 7 {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+	if msgs[0].Attempts != 2 || string(msgs[0].Payload) != `{"id":"x"}` {
+		t.Fatalf("unexpected message fields: %+v", msgs[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestMarkOutboxFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgOutboxStore{db: db}
+	retryAt := time.Now().Add(time.Minute)
+
+	mock.ExpectExec(regexp.QuoteMeta(`SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3`)).
+		WithArgs(int64(3), retryAt, "sink down").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := store.MarkOutboxFailed(context.Background(), 3, retryAt, "sink down"); err != nil {
+		t.Fatalf("MarkOutboxFailed returned error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestOutboxAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgOutboxStore{db: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id > $1`)).
+		WithArgs(int64(41), 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "event_type", "payload", "created_at", "attempts"}).
+			AddRow(int64(42), uuid.New(), "event.deleted", []byte(`{}`), time.Now(), 0))
+
+	msgs, err := store.OutboxAfter(context.Background(), 41, 500)
+	if err != nil {
+		t.Fatalf("OutboxAfter returned error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].ID != 42 {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+}
+
+func TestPurgeOutbox_KeepsPendingDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgOutboxStore{db: db}
+	cutoff := time.Now().Add(-7 * 24 * time.Hour)
+
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE d.message_id = o.id AND d.status = 'pending'`)).
+		WithArgs(cutoff, 100).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	n, err := store.PurgeOutbox(context.Background(), cutoff, 100)
+	if err != nil || n != 4 {
+		t.Fatalf("PurgeOutbox = %d, %v; want 4", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"events/structures"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type pgReminderStore struct {
+	db *sql.DB
+}
+
+func NewPGReminderStore(db *sql.DB) *pgReminderStore {
+	return &pgReminderStore{db: db}
+}
+
+const reminderColumns = `
+        r.id, r.event_id, r.minutes_before, r.channel, r.target,
+        e.start_time - make_interval(mins => r.minutes_before),
+        r.status, r.attempts, COALESCE(r.last_error, ''), r.sent_at, r.created_at`
+
+func (s *pgReminderStore) CreateReminder(ctx context.Context, r *structures.Reminder) (*structures.Reminder, error) {
+	const q = `
+        WITH r AS (
+            INSERT INTO reminders (id, event_id, minutes_before, channel, target, created_at)
+            SELECT $1, e.id, $3, $4, $5, $6
+            FROM events e
+            WHERE e.id = $2 AND e.deleted_at IS NULL
+            RETURNING *
+        )
+        SELECT ` + reminderColumns + `
+        FROM r
+        JOIN events e ON e.id = r.event_id
+    `
+	created, err := scanReminder(s.db.QueryRowContext(ctx, q,
+		r.ID, r.EventID, r.MinutesBefore, r.Channel, r.Target, r.CreatedAt))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, structures.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *pgReminderStore) ListReminders(ctx context.Context, eventID uuid.UUID) ([]structures.Reminder, error) {
+	const existsQ = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, existsQ, eventID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, structures.ErrEventNotFound
+	}
+
+	const q = `
+        SELECT ` + reminderColumns + `
+        FROM reminders r
+        JOIN events e ON e.id = r.event_id
+        WHERE r.event_id = $1
+        ORDER BY r.minutes_before DESC, r.created_at ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := make([]structures.Reminder, 0)
+	for rows.Next() {
+		r, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (s *pgReminderStore) DeleteReminder(ctx context.Context, eventID, id uuid.UUID) error {
+	const q = `DELETE FROM reminders WHERE event_id = $1 AND id = $2`
+	res, err := s.db.ExecContext(ctx, q, eventID, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return structures.ErrReminderNotFound
+	}
+	return nil
+}
+
+// ClaimDueReminders leases pending reminders whose time has come. SKIP
+// LOCKED lets every replica run a scheduler without sending a reminder
+// twice; a replica that dies mid-send releases its batch when the lease
+// runs out. Only scheduled events send reminders; those of events in the
+// trash, drafts, postponed and cancelled events wait.
+func (s *pgReminderStore) ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]structures.DueReminder, error) {
+	const q = `
+        WITH due AS (
+            SELECT r.id
+            FROM reminders r
+            JOIN events e ON e.id = r.event_id
+            WHERE r.status = 'pending'
+              AND e.deleted_at IS NULL
+              AND e.status = 'scheduled'
+              AND r.next_attempt_at <= NOW()
+              AND e.start_time - make_interval(mins => r.minutes_before) <= NOW()
+            ORDER BY e.start_time - make_interval(mins => r.minutes_before) ASC
+            LIMIT $1
+            FOR UPDATE OF r SKIP LOCKED
+        ), r AS (
+            UPDATE reminders
+            SET next_attempt_at = NOW() + make_interval(secs => $2)
+            WHERE id IN (SELECT id FROM due)
+            RETURNING *
+        )
+        SELECT ` + reminderColumns + `,
+               e.title, COALESCE(e.description, ''), e.start_time, e.end_time, e.time_zone, e.all_day
+        FROM r
+        JOIN events e ON e.id = r.event_id
+    `
+	rows, err := s.db.QueryContext(ctx, q, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := make([]structures.DueReminder, 0)
+	for rows.Next() {
+		var d structures.DueReminder
+		r, e := &d.Reminder, &d.Event
+		err := rows.Scan(
+			&r.ID, &r.EventID, &r.MinutesBefore, &r.Channel, &r.Target, &r.RemindAt,
+			&r.Status, &r.Attempts, &r.LastError, &r.SentAt, &r.CreatedAt,
+			&e.Title, &e.Description, &e.StartTime, &e.EndTime, &e.TimeZone, &e.AllDay,
+		)
+		if err != nil {
+			return nil, err
+		}
+		e.ID = r.EventID
+		due = append(due, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+func (s *pgReminderStore) MarkReminderSent(ctx context.Context, id uuid.UUID) error {
+	const q = `
+        UPDATE reminders
+        SET status = 'sent', attempts = attempts + 1, last_error = NULL, sent_at = NOW()
+        WHERE id = $1
+    `
+	_, err := s.db.ExecContext(ctx, q, id)
+	return err
+}
+
+// MarkReminderFailed records a failed attempt; a nil retryAt gives up.
+func (s *pgReminderStore) MarkReminderFailed(ctx context.Context, id uuid.UUID, cause string, retryAt *time.Time) error {
+	const q = `
+        UPDATE reminders
+        SET attempts = attempts + 1, last_error = $2,
+            status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+            next_attempt_at = COALESCE($3, next_attempt_at)
+        WHERE id = $1
+    `
+	_, err := s.db.ExecContext(ctx, q, id, cause, retryAt)
+	return err
+}
+
+// rearmReminders puts sent or failed reminders of a rescheduled event back
+// to pending when their new time is still ahead.
+func rearmReminders(ctx context.Context, tx *sql.Tx, e *structures.Event) error {
+	const q = `
+        UPDATE reminders
+        SET status = 'pending', attempts = 0, last_error = NULL, sent_at = NULL, next_attempt_at = NOW()
+        WHERE event_id = $1
+          AND status <> 'pending'
+          AND $2::timestamptz - make_interval(mins => minutes_before) > NOW()
+    `
+	_, err := tx.ExecContext(ctx, q, e.ID, e.StartTime)
+	return err
+}
+
+func scanReminder(row rowScanner) (*structures.Reminder, error) {
+	var r structures.Reminder
+	err := row.Scan(&r.ID, &r.EventID, &r.MinutesBefore, &r.Channel, &r.Target, &r.RemindAt,
+		&r.Status, &r.Attempts, &r.LastError, &r.SentAt, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
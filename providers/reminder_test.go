@@ -0,0 +1,66 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestCreateReminder_EventNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgReminderStore{db: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO reminders`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = store.CreateReminder(context.Background(), &structures.Reminder{ID: uuid.New(), EventID: uuid.New(), Channel: structures.ReminderLog})
+	if !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestClaimDueReminders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgReminderStore{db: db}
+	id, eventID := uuid.New(), uuid.New()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE OF r SKIP LOCKED`)).
+		WithArgs(10, float64(60)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "event_id", "minutes_before", "channel", "target", "remind_at",
+			"status", "attempts", "last_error", "sent_at", "created_at",
+			"title", "description", "start_time", "end_time", "time_zone", "all_day",
+		}).AddRow(id, eventID, 15, "email", "ana@example.com", start.Add(-15*time.Minute),
+			"pending", 0, "", nil, start.Add(-time.Hour),
+			"Standup", "", start, start.Add(15*time.Minute), "UTC", false))
+
+	due, err := store.ClaimDueReminders(context.Background(), 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDueReminders returned error: %v", err)
+	}
+	if len(due) != 1 || due[0].Reminder.ID != id || due[0].Event.ID != eventID || due[0].Event.Title != "Standup" {
+		t.Fatalf("unexpected due reminders: %+v", due)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
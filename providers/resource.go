@@ -0,0 +1,316 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+const pgExclusionViolation = "23P01"
+
+type pgResourceStore struct {
+	db *sql.DB
+}
+
+func NewPGResourceStore(db *sql.DB) *pgResourceStore {
+	return &pgResourceStore{db: db}
+}
+
+func (s *pgResourceStore) CreateResource(ctx context.Context, r *structures.Resource) (*structures.Resource, error) {
+	const q = `
+        INSERT INTO resources (id, name, kind, working_hours, created_at)
+        VALUES ($1, $2, $3, $4::jsonb, $5)
+    `
+	workingHours, err := jsonParam(r.WorkingHours)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.db.ExecContext(ctx, q, r.ID, r.Name, r.Kind, workingHours, r.CreatedAt)
+	if pgErrorCode(err) == pgUniqueViolation {
+		return nil, structures.ErrResourceExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *pgResourceStore) ListResources(ctx context.Context) ([]structures.Resource, error) {
+	const q = `
+        SELECT id, name, kind, working_hours, created_at
+        FROM resources
+        ORDER BY name ASC
+    `
+	return s.queryResources(ctx, q)
+}
+
+func (s *pgResourceStore) GetResource(ctx context.Context, id uuid.UUID) (*structures.Resource, error) {
+	const q = `
+        SELECT id, name, kind, working_hours, created_at
+        FROM resources
+        WHERE id = $1
+    `
+	r, err := scanResource(s.db.QueryRowContext(ctx, q, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BookResource reserves a resource for the whole duration of an event. The
+// exclusion constraint on event_resources is what guarantees no double
+// booking; when it fires the clashing events are looked up and returned as
+// a *structures.BookingConflictError.
+func (s *pgResourceStore) BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error) {
+	const q = `
+        INSERT INTO event_resources (event_id, resource_id, during)
+        SELECT e.id, r.id, tstzrange(e.start_time, e.end_time, '[)')
+        FROM events e, resources r
+        WHERE e.id = $1 AND e.deleted_at IS NULL AND r.id = $2
+        RETURNING event_id, resource_id, lower(during), upper(during), created_at
+    `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var b structures.Booking
+	err = tx.QueryRowContext(ctx, q, eventID, resourceID).
+		Scan(&b.EventID, &b.ResourceID, &b.StartTime, &b.EndTime, &b.CreatedAt)
+	switch pgErrorCode(err) {
+	case pgUniqueViolation:
+		return nil, structures.ErrBookingExists
+	case pgExclusionViolation:
+		return nil, s.bookingConflict(ctx, eventID, resourceID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.missingBookingTarget(ctx, eventID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := insertOutbox(ctx, tx, eventID, structures.ResourceBooked, &b); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (s *pgResourceStore) ListEventResources(ctx context.Context, eventID uuid.UUID) ([]structures.Resource, error) {
+	const existsQ = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, existsQ, eventID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, structures.ErrEventNotFound
+	}
+
+	const q = `
+        SELECT r.id, r.name, r.kind, r.working_hours, r.created_at
+        FROM resources r
+        JOIN event_resources er ON er.resource_id = r.id
+        WHERE er.event_id = $1
+        ORDER BY r.name ASC
+    `
+	return s.queryResources(ctx, q, eventID)
+}
+
+// ListResourcesForEvents returns the resources booked by several events in
+// one query, keyed by event id. Events without bookings are absent.
+func (s *pgResourceStore) ListResourcesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Resource, error) {
+	const q = `
+        SELECT er.event_id, r.id, r.name, r.kind, r.working_hours, r.created_at
+        FROM resources r
+        JOIN event_resources er ON er.resource_id = r.id
+        WHERE er.event_id = ANY($1::uuid[])
+        ORDER BY r.name ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, uuidStrings(eventIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resources := make(map[uuid.UUID][]structures.Resource)
+	for rows.Next() {
+		var eventID uuid.UUID
+		r, err := scanResource(eventResourceRow{rows, &eventID})
+		if err != nil {
+			return nil, err
+		}
+		resources[eventID] = append(resources[eventID], *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// eventResourceRow scans a leading event_id column before handing the rest
+// of the row to scanResource.
+type eventResourceRow struct {
+	rows    *sql.Rows
+	eventID *uuid.UUID
+}
+
+func (r eventResourceRow) Scan(dest ...any) error {
+	return r.rows.Scan(append([]any{r.eventID}, dest...)...)
+}
+
+func (s *pgResourceStore) ReleaseResource(ctx context.Context, eventID, resourceID uuid.UUID) error {
+	const q = `
+        DELETE FROM event_resources
+        WHERE event_id = $1 AND resource_id = $2
+        RETURNING event_id, resource_id, lower(during), upper(during), created_at
+    `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var b structures.Booking
+	err = tx.QueryRowContext(ctx, q, eventID, resourceID).
+		Scan(&b.EventID, &b.ResourceID, &b.StartTime, &b.EndTime, &b.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return structures.ErrBookingNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := insertOutbox(ctx, tx, eventID, structures.ResourceReleased, &b); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *pgResourceStore) queryResources(ctx context.Context, q string, args ...any) ([]structures.Resource, error) {
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resources := make([]structures.Resource, 0)
+	for rows.Next() {
+		r, err := scanResource(rows)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func scanResource(row rowScanner) (*structures.Resource, error) {
+	var r structures.Resource
+	var workingHours []byte
+	if err := row.Scan(&r.ID, &r.Name, &r.Kind, &workingHours, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	if workingHours != nil {
+		r.WorkingHours = &structures.WorkingHours{}
+		if err := json.Unmarshal(workingHours, r.WorkingHours); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+func (s *pgResourceStore) bookingConflict(ctx context.Context, eventID, resourceID uuid.UUID) error {
+	const q = `
+        SELECT er.event_id
+        FROM event_resources er, events e
+        WHERE e.id = $1
+          AND er.resource_id = $2
+          AND er.event_id <> e.id
+          AND er.during && tstzrange(e.start_time, e.end_time, '[)')
+        ORDER BY lower(er.during) ASC
+    `
+	rows, err := s.db.QueryContext(ctx, q, eventID, resourceID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	conflict := &structures.BookingConflictError{ResourceID: resourceID, EventIDs: make([]uuid.UUID, 0)}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		conflict.EventIDs = append(conflict.EventIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return conflict
+}
+
+// missingBookingTarget works out which side of a booking did not exist.
+func (s *pgResourceStore) missingBookingTarget(ctx context.Context, eventID uuid.UUID) error {
+	const q = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, q, eventID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return structures.ErrEventNotFound
+	}
+	return structures.ErrResourceNotFound
+}
+
+// rescheduleConflict finds which bookings of e clash with other events once
+// e moves to its new time range. It runs outside the failed transaction.
+func rescheduleConflict(ctx context.Context, db *sql.DB, e *structures.Event) error {
+	const q = `
+        SELECT other.resource_id, other.event_id
+        FROM event_resources mine
+        JOIN event_resources other
+          ON other.resource_id = mine.resource_id AND other.event_id <> mine.event_id
+        WHERE mine.event_id = $1
+          AND other.during && tstzrange($2, $3, '[)')
+        ORDER BY other.resource_id, lower(other.during) ASC
+    `
+	rows, err := db.QueryContext(ctx, q, e.ID, e.StartTime, e.EndTime)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var conflict *structures.BookingConflictError
+	for rows.Next() {
+		var resourceID, eventID uuid.UUID
+		if err := rows.Scan(&resourceID, &eventID); err != nil {
+			return err
+		}
+		if conflict == nil {
+			conflict = &structures.BookingConflictError{ResourceID: resourceID}
+		}
+		// Report the first clashing resource only, like BookResource does.
+		if resourceID == conflict.ResourceID {
+			conflict.EventIDs = append(conflict.EventIDs, eventID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if conflict == nil {
+		return errors.New("booking conflict while rescheduling event")
+	}
+	return conflict
+}
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestBookResource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgResourceStore{db: db}
+
+	eventID, resourceID := uuid.New(), uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO event_resources`)).
+		WithArgs(eventID, resourceID).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "resource_id", "lower", "upper", "created_at"}).
+			AddRow(eventID, resourceID, now, now.Add(time.Hour), now))
+	expectOutbox(mock, eventID, structures.ResourceBooked)
+	mock.ExpectCommit()
+
+	b, err := store.BookResource(context.Background(), eventID, resourceID)
+	if err != nil {
+		t.Fatalf("BookResource returned error: %v", err)
+	}
+	if b.EventID != eventID || b.ResourceID != resourceID {
+		t.Fatalf("unexpected booking: %+v", b)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBookResource_OverlapReturnsClashingEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgResourceStore{db: db}
+
+	eventID, resourceID, clashID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO event_resources`)).
+		WillReturnError(&pgconn.PgError{Code: pgExclusionViolation})
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT er.event_id`)).
+		WithArgs(eventID, resourceID).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(clashID))
+	mock.ExpectRollback()
+
+	_, err = store.BookResource(context.Background(), eventID, resourceID)
+	var conflict *structures.BookingConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected BookingConflictError, got %v", err)
+	}
+	if len(conflict.EventIDs) != 1 || conflict.EventIDs[0] != clashID {
+		t.Fatalf("unexpected clashing events: %v", conflict.EventIDs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBookResource_MissingResource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgResourceStore{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO event_resources`)).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "resource_id", "lower", "upper", "created_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	_, err = store.BookResource(context.Background(), uuid.New(), uuid.New())
+	if !errors.Is(err, structures.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+}
+
+func TestCreateResource_DuplicateName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgResourceStore{db: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO resources`)).
+		WillReturnError(&pgconn.PgError{Code: pgUniqueViolation})
+
+	_, err = store.CreateResource(context.Background(), &structures.Resource{ID: uuid.New(), Name: "Room A", Kind: structures.ResourceRoom})
+	if !errors.Is(err, structures.ErrResourceExists) {
+		t.Fatalf("expected ErrResourceExists, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// replaceEventTags sets the tags of an event inside the caller's
+// transaction, creating the tags nobody used before. Tags left without
+// events are kept; they cost a row each.
+func replaceEventTags(ctx context.Context, tx *sql.Tx, eventID uuid.UUID, tags []string) error {
+	const deleteQ = `DELETE FROM event_tags WHERE event_id = $1`
+	if _, err := tx.ExecContext(ctx, deleteQ, eventID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	const upsertQ = `
+        INSERT INTO tags (name)
+        SELECT unnest($1::text[])
+        ON CONFLICT (name) DO NOTHING
+    `
+	if _, err := tx.ExecContext(ctx, upsertQ, tags); err != nil {
+		return err
+	}
+	const linkQ = `
+        INSERT INTO event_tags (event_id, tag_id)
+        SELECT $1, id FROM tags WHERE name = ANY($2::text[])
+    `
+	_, err := tx.ExecContext(ctx, linkQ, eventID, tags)
+	return err
+}
+
+// metadataParam encodes metadata for a $n::jsonb placeholder. The column is
+// NOT NULL, so missing metadata is stored as an empty object.
+func metadataParam(m map[string]any) (string, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
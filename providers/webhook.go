@@ -0,0 +1,271 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"events/structures"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type pgWebhookStore struct {
+	db *sql.DB
+}
+
+func NewPGWebhookStore(db *sql.DB) *pgWebhookStore {
+	return &pgWebhookStore{db: db}
+}
+
+func (s *pgWebhookStore) CreateWebhook(ctx context.Context, w *structures.Webhook) (*structures.Webhook, error) {
+	const q = `
+        INSERT INTO webhooks (id, url, event_types, secret, owner, created_at)
+        VALUES ($1, $2, $3::text[], $4, $5, $6)
+    `
+	_, err := s.db.ExecContext(ctx, q, w.ID, w.URL, w.EventTypes, w.Secret, w.Owner, w.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// webhookColumns leaves out the secret, which is only shown on creation.
+// event_types goes through JSON because database/sql cannot scan text[].
+const webhookColumns = `id, url, array_to_json(event_types), owner, created_at`
+
+// ListWebhooks returns the webhooks of the actor in ctx; other actors'
+// webhooks are invisible to it, like their calendars.
+func (s *pgWebhookStore) ListWebhooks(ctx context.Context) ([]structures.Webhook, error) {
+	const q = `SELECT ` + webhookColumns + ` FROM webhooks WHERE owner = $1 ORDER BY created_at ASC`
+	return s.queryWebhooks(ctx, q, structures.ActorFromContext(ctx))
+}
+
+// WebhookSubscribers returns the webhooks of every owner subscribed to
+// messageType.
+func (s *pgWebhookStore) WebhookSubscribers(ctx context.Context, messageType string) ([]structures.Webhook, error) {
+	const q = `
+        SELECT ` + webhookColumns + `
+        FROM webhooks
+        WHERE cardinality(event_types) = 0 OR $1 = ANY(event_types)
+        ORDER BY created_at ASC
+    `
+	return s.queryWebhooks(ctx, q, messageType)
+}
+
+func (s *pgWebhookStore) queryWebhooks(ctx context.Context, q string, args ...any) ([]structures.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]structures.Webhook, 0)
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (s *pgWebhookStore) GetWebhook(ctx context.Context, id uuid.UUID) (*structures.Webhook, error) {
+	const q = `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND owner = $2`
+	w, err := scanWebhook(s.db.QueryRowContext(ctx, q, id, structures.ActorFromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *pgWebhookStore) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	const q = `DELETE FROM webhooks WHERE id = $1 AND owner = $2`
+	res, err := s.db.ExecContext(ctx, q, id, structures.ActorFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return structures.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns the newest deliveries of a webhook first,
+// optionally restricted to one status.
+func (s *pgWebhookStore) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit int) ([]structures.WebhookDelivery, error) {
+	const existsQ = `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1 AND owner = $2)`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, existsQ, webhookID, structures.ActorFromContext(ctx)).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, structures.ErrWebhookNotFound
+	}
+
+	const q = `
+        SELECT id, webhook_id, message_id, event_type, status, attempts,
+               last_status_code, COALESCE(last_error, ''), next_attempt_at, delivered_at, created_at
+        FROM webhook_deliveries
+        WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+        ORDER BY id DESC
+        LIMIT $3
+    `
+	rows, err := s.db.QueryContext(ctx, q, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]structures.WebhookDelivery, 0)
+	for rows.Next() {
+		var d structures.WebhookDelivery
+		var nextAttempt time.Time
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.MessageID, &d.EventType, &d.Status, &d.Attempts,
+			&d.LastStatusCode, &d.LastError, &nextAttempt, &d.DeliveredAt, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if d.Status == structures.DeliveryPending {
+			d.NextAttemptAt = &nextAttempt
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// EnqueueDeliveries queues m for the given webhooks. It is idempotent, so
+// the outbox relay may hand the same message over twice.
+func (s *pgWebhookStore) EnqueueDeliveries(ctx context.Context, m structures.OutboxMessage, webhookIDs []uuid.UUID) (int, error) {
+	const q = `
+        INSERT INTO webhook_deliveries (webhook_id, message_id, event_type)
+        SELECT id, $1, $2
+        FROM webhooks
+        WHERE id = ANY($3::uuid[])
+        ON CONFLICT (webhook_id, message_id) DO NOTHING
+    `
+	res, err := s.db.ExecContext(ctx, q, m.ID, m.Type, uuidStrings(webhookIDs))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// ClaimDeliveries leases due deliveries the same way ClaimOutbox leases
+// outbox rows.
+func (s *pgWebhookStore) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]structures.PendingDelivery, error) {
+	const q = `
+        UPDATE webhook_deliveries d
+        SET next_attempt_at = NOW() + make_interval(secs => $2)
+        FROM webhooks w, outbox o
+        WHERE d.id IN (
+            SELECT id
+            FROM webhook_deliveries
+            WHERE status = 'pending' AND next_attempt_at <= NOW()
+            ORDER BY id ASC
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+          AND w.id = d.webhook_id
+          AND o.id = d.message_id
+        RETURNING d.id, w.url, w.secret, d.attempts,
+                  o.id, o.event_id, o.event_type, o.payload, o.created_at
+    `
+	rows, err := s.db.QueryContext(ctx, q, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pending := make([]structures.PendingDelivery, 0)
+	for rows.Next() {
+		var d structures.PendingDelivery
+		var payload []byte
+		err := rows.Scan(&d.ID, &d.URL, &d.Secret, &d.Attempts,
+			&d.Message.ID, &d.Message.EventID, &d.Message.Type, &payload, &d.Message.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		d.Message.Payload = json.RawMessage(payload)
+		pending = append(pending, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+func (s *pgWebhookStore) MarkDeliveryDelivered(ctx context.Context, id int64, statusCode int) error {
+	const q = `
+        UPDATE webhook_deliveries
+        SET status = 'delivered', attempts = attempts + 1, last_status_code = $2,
+            last_error = NULL, delivered_at = NOW()
+        WHERE id = $1
+    `
+	_, err := s.db.ExecContext(ctx, q, id, statusCode)
+	return err
+}
+
+// MarkDeliveryFailed records a failed attempt. A nil retryAt means the
+// delivery has run out of attempts and is given up on.
+func (s *pgWebhookStore) MarkDeliveryFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error {
+	const q = `
+        UPDATE webhook_deliveries
+        SET attempts = attempts + 1, last_status_code = $2, last_error = $3,
+            status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+            next_attempt_at = COALESCE($4, next_attempt_at)
+        WHERE id = $1
+    `
+	_, err := s.db.ExecContext(ctx, q, id, statusCode, cause, retryAt)
+	return err
+}
+
+// PurgeWebhookDeliveries deletes up to limit delivered or given up
+// deliveries created before cutoff. Pending ones are kept however old.
+func (s *pgWebhookStore) PurgeWebhookDeliveries(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	const q = `
+        DELETE FROM webhook_deliveries
+        WHERE id IN (
+            SELECT id
+            FROM webhook_deliveries
+            WHERE status <> 'pending' AND created_at < $1
+            ORDER BY id ASC
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+    `
+	res, err := s.db.ExecContext(ctx, q, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func scanWebhook(row rowScanner) (*structures.Webhook, error) {
+	var w structures.Webhook
+	var eventTypes []byte
+	if err := row.Scan(&w.ID, &w.URL, &eventTypes, &w.Owner, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &w.EventTypes); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestEnqueueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgWebhookStore{db: db}
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE id = ANY($3::uuid[])
+        ON CONFLICT (webhook_id, message_id) DO NOTHING`)).
+		WithArgs(int64(12), structures.EventCreated, uuidStrings(ids)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := store.EnqueueDeliveries(context.Background(), structures.OutboxMessage{ID: 12, Type: structures.EventCreated}, ids)
+	if err != nil {
+		t.Fatalf("EnqueueDeliveries returned error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deliveries queued, got %d", n)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetWebhook_DecodesEventTypes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgWebhookStore{db: db}
+	id := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, url, array_to_json(event_types), owner, created_at FROM webhooks WHERE id = $1 AND owner = $2`)).
+		WithArgs(id, "token:ana").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "owner", "created_at"}).
+			AddRow(id, "https://example.com/hook", []byte(`["event.created"]`), "token:ana", time.Now()))
+
+	w, err := store.GetWebhook(structures.WithActor(context.Background(), "token:ana"), id)
+	if err != nil {
+		t.Fatalf("GetWebhook returned error: %v", err)
+	}
+	if len(w.EventTypes) != 1 || w.EventTypes[0] != structures.EventCreated || w.Secret != "" || w.Owner != "token:ana" {
+		t.Fatalf("unexpected webhook: %+v", w)
+	}
+}
+
+func TestListWebhooks_OnlyTheCallers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgWebhookStore{db: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM webhooks WHERE owner = $1 ORDER BY created_at ASC`)).
+		WithArgs("token:ana").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "owner", "created_at"}).
+			AddRow(uuid.New(), "https://example.com/hook", []byte(`[]`), "token:ana", time.Now()))
+
+	hooks, err := store.ListWebhooks(structures.WithActor(context.Background(), "token:ana"))
+	if err != nil || len(hooks) != 1 {
+		t.Fatalf("ListWebhooks = %+v, %v", hooks, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteWebhook_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgWebhookStore{db: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhooks WHERE id = $1 AND owner = $2`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = store.DeleteWebhook(context.Background(), uuid.New())
+	if !errors.Is(err, structures.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestPurgeWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	store := &pgWebhookStore{db: db}
+	cutoff := time.Now().Add(-7 * 24 * time.Hour)
+
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE status <> 'pending' AND created_at < $1`)).
+		WithArgs(cutoff, 100).
+		WillReturnResult(sqlmock.NewResult(0, 100))
+
+	n, err := store.PurgeWebhookDeliveries(context.Background(), cutoff, 100)
+	if err != nil || n != 100 {
+		t.Fatalf("PurgeWebhookDeliveries = %d, %v; want 100", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
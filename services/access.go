@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"events/structures"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventCalendarStore finds the calendar an event belongs to. Events never
+// move between calendars, so the answer can be cached.
+type EventCalendarStore interface {
+	// EventCalendarID returns the calendar of the event, also while it is
+	// in the trash, or structures.ErrEventNotFound.
+	EventCalendarID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+}
+
+// EventAccess checks the calendar permission of the actor in ctx before
+// events, or the attendees, comments, attachments, reminders and bookings
+// that hang off them, are read or changed.
+type EventAccess interface {
+	// AuthorizeCalendar checks that the caller has at least want on the
+	// calendar. Calendars the caller cannot read are reported as
+	// structures.ErrCalendarNotFound, and a lower permission as
+	// structures.ErrForbidden.
+	AuthorizeCalendar(ctx context.Context, calendarID uuid.UUID, want string) error
+	// AuthorizeEvent does the same for the calendar of an event. Events in
+	// calendars the caller cannot read are reported as
+	// structures.ErrEventNotFound, so their existence does not leak.
+	AuthorizeEvent(ctx context.Context, eventID uuid.UUID, want string) error
+	// ReadableCalendars returns the ids of the calendars the caller can
+	// read, never nil.
+	ReadableCalendars(ctx context.Context) ([]uuid.UUID, error)
+	EventCalendarID(ctx context.Context, eventID uuid.UUID) (uuid.UUID, error)
+}
+
+type eventAccess struct {
+	events    EventCalendarStore
+	calendars CalendarService
+}
+
+// NewEventAccess checks permissions through calendars, which reports the
+// caller's permission with every calendar it returns.
+func NewEventAccess(events EventCalendarStore, calendars CalendarService) EventAccess {
+	return &eventAccess{events: events, calendars: calendars}
+}
+
+func (a *eventAccess) AuthorizeCalendar(ctx context.Context, calendarID uuid.UUID, want string) error {
+	c, err := a.calendars.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return structures.ErrCalendarNotFound
+	}
+	if !structures.PermissionAllows(c.Permission, want) {
+		return structures.ErrForbidden
+	}
+	return nil
+}
+
+func (a *eventAccess) AuthorizeEvent(ctx context.Context, eventID uuid.UUID, want string) error {
+	calendarID, err := a.events.EventCalendarID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	return eventPermissionError(a.AuthorizeCalendar(ctx, calendarID, want))
+}
+
+func (a *eventAccess) ReadableCalendars(ctx context.Context) ([]uuid.UUID, error) {
+	calendars, err := a.calendars.ListCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(calendars))
+	for _, c := range calendars {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+func (a *eventAccess) EventCalendarID(ctx context.Context, eventID uuid.UUID) (uuid.UUID, error) {
+	return a.events.EventCalendarID(ctx, eventID)
+}
+
+// eventPermissionError reports an unreadable calendar as a missing event.
+func eventPermissionError(err error) error {
+	if errors.Is(err, structures.ErrCalendarNotFound) {
+		return structures.ErrEventNotFound
+	}
+	return err
+}
+
+// readableSet loads the caller's readable calendars for filtering events
+// in memory.
+func readableSet(ctx context.Context, access EventAccess) (map[uuid.UUID]bool, error) {
+	ids, err := access.ReadableCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// ReadableChangesTTL is how long a stream trusts the caller's readable
+// calendars before loading them again, and so how long a revoked share
+// keeps streaming changes.
+const ReadableChangesTTL = 30 * time.Second
+
+// maxCachedEventCalendars bounds the event-to-calendar cache of a stream;
+// past it the cache starts over.
+const maxCachedEventCalendars = 4096
+
+// ReadableChanges filters a change stream down to the events whose
+// calendar the stream's caller can read. It belongs to one stream and is
+// not safe for concurrent use.
+type ReadableChanges struct {
+	ctx    context.Context
+	access EventAccess
+	now    func() time.Time
+
+	calendars map[uuid.UUID]bool
+	expires   time.Time
+	events    map[uuid.UUID]uuid.UUID
+}
+
+// NewReadableChanges checks changes as the actor in ctx.
+func NewReadableChanges(ctx context.Context, access EventAccess) *ReadableChanges {
+	return &ReadableChanges{ctx: ctx, access: access, now: time.Now, events: make(map[uuid.UUID]uuid.UUID)}
+}
+
+// Allows reports whether the caller may see m. Event payloads carry their
+// calendar; for other changes it is looked up, and changes of events that
+// no longer exist are left out.
+func (r *ReadableChanges) Allows(m structures.OutboxMessage) (bool, error) {
+	if now := r.now(); r.calendars == nil || !now.Before(r.expires) {
+		set, err := readableSet(r.ctx, r.access)
+		if err != nil {
+			return false, err
+		}
+		r.calendars, r.expires = set, now.Add(ReadableChangesTTL)
+	}
+
+	calendarID, ok := r.events[m.EventID]
+	if !ok {
+		var payload struct {
+			CalendarID uuid.UUID `json:"calendar_id"`
+		}
+		if err := json.Unmarshal(m.Payload, &payload); err == nil && payload.CalendarID != uuid.Nil {
+			calendarID = payload.CalendarID
+		} else {
+			var err error
+			calendarID, err = r.access.EventCalendarID(r.ctx, m.EventID)
+			if errors.Is(err, structures.ErrEventNotFound) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+		}
+		if len(r.events) >= maxCachedEventCalendars {
+			clear(r.events)
+		}
+		r.events[m.EventID] = calendarID
+	}
+	return r.calendars[calendarID], nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// fakeCalendars gives the caller the permission in perms on each calendar,
+// and none on the others.
+type fakeCalendars struct {
+	CalendarService
+
+	perms map[uuid.UUID]string
+	lists int
+}
+
+func (f *fakeCalendars) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	if f.perms[id] == "" {
+		return nil, nil
+	}
+	return &structures.Calendar{ID: id, Permission: f.perms[id]}, nil
+}
+
+func (f *fakeCalendars) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	f.lists++
+	out := make([]structures.Calendar, 0)
+	for id, p := range f.perms {
+		out = append(out, structures.Calendar{ID: id, Permission: p})
+	}
+	return out, nil
+}
+
+// fakeEventCalendars places events in calendars; unknown events are in the
+// default calendar.
+type fakeEventCalendars map[uuid.UUID]uuid.UUID
+
+func (f fakeEventCalendars) EventCalendarID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	if c, ok := f[id]; ok {
+		if c == uuid.Nil {
+			return uuid.Nil, structures.ErrEventNotFound
+		}
+		return c, nil
+	}
+	return structures.DefaultCalendarID, nil
+}
+
+// openAccess lets the caller write to the default calendar, where every
+// event of the older tests lives.
+func openAccess() EventAccess {
+	return NewEventAccess(fakeEventCalendars{}, &fakeCalendars{perms: map[uuid.UUID]string{
+		structures.DefaultCalendarID: structures.PermissionWrite,
+	}})
+}
+
+func TestEventAccess_AuthorizeEvent(t *testing.T) {
+	ctx := context.Background()
+	readOnly, private, gone := uuid.New(), uuid.New(), uuid.New()
+	readCal, privateCal := uuid.New(), uuid.New()
+	access := NewEventAccess(
+		fakeEventCalendars{readOnly: readCal, private: privateCal, gone: uuid.Nil},
+		&fakeCalendars{perms: map[uuid.UUID]string{readCal: structures.PermissionRead}},
+	)
+
+	for _, tc := range []struct {
+		name string
+		id   uuid.UUID
+		want string
+		err  error
+	}{
+		{"read on a read share", readOnly, structures.PermissionRead, nil},
+		{"write on a read share", readOnly, structures.PermissionWrite, structures.ErrForbidden},
+		{"read on an unshared calendar", private, structures.PermissionRead, structures.ErrEventNotFound},
+		{"missing event", gone, structures.PermissionRead, structures.ErrEventNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := access.AuthorizeEvent(ctx, tc.id, tc.want); !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestReadableChanges_FiltersByCalendar(t *testing.T) {
+	ctx := context.Background()
+	shared, private := uuid.New(), uuid.New()
+	attendeeEvent := uuid.New()
+	calendars := &fakeCalendars{perms: map[uuid.UUID]string{shared: structures.PermissionRead}}
+	access := NewEventAccess(fakeEventCalendars{attendeeEvent: private}, calendars)
+	r := NewReadableChanges(ctx, access)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	eventChange := func(calendarID uuid.UUID) structures.OutboxMessage {
+		payload, _ := json.Marshal(structures.Event{ID: uuid.New(), CalendarID: calendarID})
+		return structures.OutboxMessage{EventID: uuid.New(), Type: structures.EventUpdated, Payload: payload}
+	}
+	for _, tc := range []struct {
+		name string
+		m    structures.OutboxMessage
+		want bool
+	}{
+		{"shared event", eventChange(shared), true},
+		{"private event", eventChange(private), false},
+		{"attendee of a private event", structures.OutboxMessage{EventID: attendeeEvent, Type: structures.AttendeeAdded, Payload: []byte(`{"email":"ana@example.com"}`)}, false},
+	} {
+		got, err := r.Allows(tc.m)
+		if err != nil || got != tc.want {
+			t.Fatalf("%s: expected %v, got %v, %v", tc.name, tc.want, got, err)
+		}
+	}
+	if calendars.lists != 1 {
+		t.Fatalf("expected the readable calendars to be loaded once, got %d", calendars.lists)
+	}
+
+	// A share granted later shows up once the cached calendars expire.
+	calendars.perms[private] = structures.PermissionRead
+	now = now.Add(ReadableChangesTTL)
+	if got, _ := r.Allows(eventChange(private)); !got {
+		t.Fatal("expected the new share to apply after ReadableChangesTTL")
+	}
+}
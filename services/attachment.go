@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// BlobStore keeps attachment content by key.
+type BlobStore interface {
+	// Put stores the size bytes of r under key, replacing what was there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get fails with structures.ErrBlobNotFound for keys it does not hold.
+	// The caller closes the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete succeeds for keys it does not hold.
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentStore keeps the metadata of attachments; the content is in a
+// BlobStore.
+type AttachmentStore interface {
+	// CreateAttachment fails with structures.ErrEventNotFound unless the
+	// event exists outside the trash.
+	CreateAttachment(ctx context.Context, a *structures.Attachment) (*structures.Attachment, error)
+	ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error)
+	// GetAttachment returns nil when the attachment does not exist or its
+	// event is in the trash.
+	GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error)
+	// DeleteAttachment returns what it deleted, so the blob can follow.
+	DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error)
+}
+
+type AttachmentService interface {
+	// UploadAttachment stores content and records it as a. It fails with
+	// structures.ErrAttachmentTooLarge past structures.MaxAttachmentSize.
+	UploadAttachment(ctx context.Context, a *structures.Attachment, content io.Reader) (*structures.Attachment, error)
+	ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error)
+	GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error)
+	// OpenAttachment returns the attachment with its content, which the
+	// caller closes.
+	OpenAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) error
+}
+
+type attachmentService struct {
+	store  AttachmentStore
+	blobs  BlobStore
+	access EventAccess
+}
+
+// NewAttachmentService needs read permission on the event's calendar to
+// list and download attachments and write to upload or delete them.
+func NewAttachmentService(store AttachmentStore, blobs BlobStore, access EventAccess) AttachmentService {
+	return &attachmentService{store: store, blobs: blobs, access: access}
+}
+
+// UploadAttachment spools content to a temporary file first: the size and
+// digest must be known before the blob is written, and an upload over the
+// limit must not reach the blob store at all. The blob is written before
+// the metadata, and removed again if the metadata cannot be saved, so a
+// listed attachment always has content.
+func (s *attachmentService) UploadAttachment(ctx context.Context, a *structures.Attachment, content io.Reader) (*structures.Attachment, error) {
+	if err := s.access.AuthorizeEvent(ctx, a.EventID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	spool, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(spool, h), io.LimitReader(content, structures.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > structures.MaxAttachmentSize {
+		return nil, structures.ErrAttachmentTooLarge
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	a.Size = n
+	a.SHA256 = hex.EncodeToString(h.Sum(nil))
+	a.UploadedBy = structures.ActorFromContext(ctx)
+	a.BlobKey = "events/" + a.EventID.String() + "/" + a.ID.String()
+	if err := s.blobs.Put(ctx, a.BlobKey, spool, n, a.ContentType); err != nil {
+		return nil, err
+	}
+	created, err := s.store.CreateAttachment(ctx, a)
+	if err != nil {
+		s.deleteBlob(a.BlobKey)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *attachmentService) ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.store.ListAttachments(ctx, eventID)
+}
+
+func (s *attachmentService) GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.store.GetAttachment(ctx, eventID, id)
+}
+
+func (s *attachmentService) OpenAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, io.ReadCloser, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, nil, err
+	}
+	a, err := s.store.GetAttachment(ctx, eventID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a == nil {
+		return nil, nil, structures.ErrAttachmentNotFound
+	}
+	body, err := s.blobs.Get(ctx, a.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, body, nil
+}
+
+// DeleteAttachment removes the metadata first; a blob left behind by a
+// failed delete is only wasted space, while metadata without a blob would
+// be listed but fail to download.
+func (s *attachmentService) DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) error {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return err
+	}
+	a, err := s.store.DeleteAttachment(ctx, eventID, id)
+	if err != nil {
+		return err
+	}
+	s.deleteBlob(a.BlobKey)
+	return nil
+}
+
+// deleteBlob is best effort and outlives the request, which may already
+// have been cancelled.
+func (s *attachmentService) deleteBlob(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		log.Printf("attachments: delete blob %s: %v", key, err)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type memBlobStore struct {
+	blobs   map[string][]byte
+	deleted []string
+}
+
+func (m *memBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	m.blobs[key] = data
+	return nil
+}
+
+func (m *memBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, structures.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memBlobStore) Delete(ctx context.Context, key string) error {
+	delete(m.blobs, key)
+	m.deleted = append(m.deleted, key)
+	return nil
+}
+
+type mockAttachmentStore struct {
+	created   *structures.Attachment
+	createErr error
+	got       *structures.Attachment
+}
+
+func (m *mockAttachmentStore) CreateAttachment(ctx context.Context, a *structures.Attachment) (*structures.Attachment, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = a
+	return a, nil
+}
+
+func (m *mockAttachmentStore) ListAttachments(ctx context.Context, eventID uuid.UUID) ([]structures.Attachment, error) {
+	return nil, nil
+}
+
+func (m *mockAttachmentStore) GetAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	return m.got, nil
+}
+
+func (m *mockAttachmentStore) DeleteAttachment(ctx context.Context, eventID, id uuid.UUID) (*structures.Attachment, error) {
+	return m.got, nil
+}
+
+func TestAttachmentService_Upload_StoresBlobAndMetadata(t *testing.T) {
+	store := &mockAttachmentStore{}
+	blobs := &memBlobStore{blobs: make(map[string][]byte)}
+	svc := NewAttachmentService(store, blobs, openAccess())
+
+	a := &structures.Attachment{ID: uuid.New(), EventID: uuid.New(), Filename: "a.txt", ContentType: "text/plain"}
+	ctx := structures.WithActor(context.Background(), "token:abc")
+	got, err := svc.UploadAttachment(ctx, a, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("UploadAttachment returned error: %v", err)
+	}
+	if got.Size != 5 || got.UploadedBy != "token:abc" ||
+		got.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("unexpected attachment %+v", got)
+	}
+	if want := "events/" + a.EventID.String() + "/" + a.ID.String(); got.BlobKey != want || string(blobs.blobs[want]) != "hello" {
+		t.Fatalf("blob not stored under %s: %v", want, blobs.blobs)
+	}
+
+	_, body, err := svc.OpenAttachment(context.Background(), a.EventID, a.ID)
+	if err == nil {
+		t.Fatalf("OpenAttachment of a missing attachment: expected an error, got %v", body)
+	}
+	store.got = got
+	_, body, err = svc.OpenAttachment(context.Background(), a.EventID, a.ID)
+	if err != nil {
+		t.Fatalf("OpenAttachment returned error: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	if string(data) != "hello" {
+		t.Fatalf("OpenAttachment read %q", data)
+	}
+
+	if err := svc.DeleteAttachment(context.Background(), a.EventID, a.ID); err != nil {
+		t.Fatalf("DeleteAttachment returned error: %v", err)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Fatalf("expected the blob to be deleted with the attachment")
+	}
+}
+
+func TestAttachmentService_Upload_RejectsLargeFiles(t *testing.T) {
+	blobs := &memBlobStore{blobs: make(map[string][]byte)}
+	svc := NewAttachmentService(&mockAttachmentStore{}, blobs, openAccess())
+
+	content := io.LimitReader(zeros{}, structures.MaxAttachmentSize+1)
+	_, err := svc.UploadAttachment(context.Background(), &structures.Attachment{ID: uuid.New()}, content)
+	if !errors.Is(err, structures.ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Fatalf("an upload over the limit must not reach the blob store")
+	}
+}
+
+func TestAttachmentService_Upload_RemovesBlobWhenMetadataFails(t *testing.T) {
+	blobs := &memBlobStore{blobs: make(map[string][]byte)}
+	svc := NewAttachmentService(&mockAttachmentStore{createErr: structures.ErrEventNotFound}, blobs, openAccess())
+
+	_, err := svc.UploadAttachment(context.Background(), &structures.Attachment{ID: uuid.New()}, strings.NewReader("x"))
+	if !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+	if len(blobs.blobs) != 0 || len(blobs.deleted) != 1 {
+		t.Fatalf("expected the orphaned blob to be deleted: %v %v", blobs.blobs, blobs.deleted)
+	}
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"events/structures"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type AttendeeService interface {
+	AddAttendee(ctx context.Context, a *structures.Attendee) (*structures.Attendee, error)
+	ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error)
+	ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error)
+	RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error
+	UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error)
+}
+
+type attendeeService struct {
+	store  AttendeeService
+	access EventAccess
+}
+
+// NewAttendeeService needs read permission on the event's calendar to list
+// attendees and write to change them. ListAttendeesForEvents is not
+// checked: it batches the attendees of events the caller already loaded
+// through the event service.
+func NewAttendeeService(store AttendeeService, access EventAccess) AttendeeService {
+	return &attendeeService{store: store, access: access}
+}
+
+func (s *attendeeService) AddAttendee(ctx context.Context, a *structures.Attendee) (*structures.Attendee, error) {
+	if err := s.access.AuthorizeEvent(ctx, a.EventID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	a.Email = normalizeEmail(a.Email)
+	if a.Role == "" {
+		a.Role = structures.RoleRequired
+	}
+	if a.RSVPStatus == "" {
+		a.RSVPStatus = structures.RSVPPending
+	}
+	return s.store.AddAttendee(ctx, a)
+}
+
+func (s *attendeeService) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.store.ListAttendees(ctx, eventID)
+}
+
+func (s *attendeeService) ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error) {
+	return s.store.ListAttendeesForEvents(ctx, eventIDs)
+}
+
+func (s *attendeeService) RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return err
+	}
+	return s.store.RemoveAttendee(ctx, eventID, normalizeEmail(email))
+}
+
+func (s *attendeeService) UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return s.store.UpdateRSVP(ctx, eventID, normalizeEmail(email), status)
+}
+
+// Emails are the attendee key, so they are compared case-insensitively.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
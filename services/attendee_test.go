@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockAttendeeStore struct {
+	addArg      *structures.Attendee
+	removeEmail string
+	rsvpEmail   string
+}
+
+func (m *mockAttendeeStore) AddAttendee(ctx context.Context, a *structures.Attendee) (*structures.Attendee, error) {
+	m.addArg = a
+	return a, nil
+}
+
+func (m *mockAttendeeStore) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]structures.Attendee, error) {
+	return nil, nil
+}
+
+func (m *mockAttendeeStore) ListAttendeesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Attendee, error) {
+	return nil, nil
+}
+
+func (m *mockAttendeeStore) RemoveAttendee(ctx context.Context, eventID uuid.UUID, email string) error {
+	m.removeEmail = email
+	return nil
+}
+
+func (m *mockAttendeeStore) UpdateRSVP(ctx context.Context, eventID uuid.UUID, email, status string) (*structures.Attendee, error) {
+	m.rsvpEmail = email
+	return &structures.Attendee{Email: email, RSVPStatus: status}, nil
+}
+
+func TestAttendeeService_AddAttendee_AppliesDefaults(t *testing.T) {
+	store := &mockAttendeeStore{}
+	svc := NewAttendeeService(store, openAccess())
+
+	_, err := svc.AddAttendee(context.Background(), &structures.Attendee{
+		EventID: uuid.New(),
+		Email:   "  Ana@Example.COM ",
+	})
+	if err != nil {
+		t.Fatalf("AddAttendee returned error: %v", err)
+	}
+	if store.addArg.Email != "ana@example.com" {
+		t.Fatalf("email not normalised: %q", store.addArg.Email)
+	}
+	if store.addArg.Role != structures.RoleRequired {
+		t.Fatalf("expected default role %q, got %q", structures.RoleRequired, store.addArg.Role)
+	}
+	if store.addArg.RSVPStatus != structures.RSVPPending {
+		t.Fatalf("expected default rsvp %q, got %q", structures.RSVPPending, store.addArg.RSVPStatus)
+	}
+}
+
+func TestAttendeeService_NormalisesEmailKey(t *testing.T) {
+	store := &mockAttendeeStore{}
+	svc := NewAttendeeService(store, openAccess())
+	ctx := context.Background()
+
+	if err := svc.RemoveAttendee(ctx, uuid.New(), "Ana@Example.com"); err != nil {
+		t.Fatalf("RemoveAttendee returned error: %v", err)
+	}
+	if _, err := svc.UpdateRSVP(ctx, uuid.New(), "ANA@example.com", structures.RSVPAccepted); err != nil {
+		t.Fatalf("UpdateRSVP returned error: %v", err)
+	}
+	if store.removeEmail != "ana@example.com" || store.rsvpEmail != "ana@example.com" {
+		t.Fatalf("email not normalised: remove=%q rsvp=%q", store.removeEmail, store.rsvpEmail)
+	}
+}
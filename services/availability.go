@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"events/structures"
+	"slices"
+	"time"
+)
+
+type AvailabilityService interface {
+	GetAvailability(ctx context.Context, q structures.AvailabilityQuery) (*structures.Availability, error)
+}
+
+// AvailabilityStore is the read side needed to compute availability; it is
+// implemented by the events store.
+type AvailabilityStore interface {
+	BusyIntervals(ctx context.Context, q structures.AvailabilityQuery) ([]structures.Interval, error)
+}
+
+type availabilityService struct {
+	store     AvailabilityStore
+	resources ResourceService
+}
+
+func NewAvailabilityService(store AvailabilityStore, resources ResourceService) AvailabilityService {
+	return &availabilityService{store: store, resources: resources}
+}
+
+// GetAvailability merges the busy intervals of every requested resource and
+// attendee, then reports the gaps of at least q.Duration that also fall
+// inside the working hours of every requested resource.
+func (s *availabilityService) GetAvailability(ctx context.Context, q structures.AvailabilityQuery) (*structures.Availability, error) {
+	q.Attendees = slices.Clone(q.Attendees)
+	for i, email := range q.Attendees {
+		q.Attendees[i] = normalizeEmail(email)
+	}
+
+	open := []structures.Interval{{Start: q.From, End: q.To}}
+	for _, id := range q.ResourceIDs {
+		res, err := s.resources.GetResource(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return nil, structures.ErrResourceNotFound
+		}
+		if res.WorkingHours != nil {
+			open = intersectIntervals(open, res.WorkingHours.Windows(q.From, q.To))
+		}
+	}
+
+	busy, err := s.store.BusyIntervals(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	busy = mergeIntervals(clipIntervals(busy, q.From, q.To))
+
+	free := make([]structures.Interval, 0)
+	for _, slot := range subtractIntervals(open, busy) {
+		if slot.End.Sub(slot.Start) >= q.Duration {
+			free = append(free, slot)
+		}
+	}
+
+	return &structures.Availability{
+		From:      q.From,
+		To:        q.To,
+		Busy:      busy,
+		FreeSlots: free,
+	}, nil
+}
+
+func clipIntervals(in []structures.Interval, from, to time.Time) []structures.Interval {
+	out := make([]structures.Interval, 0, len(in))
+	for _, iv := range in {
+		if iv.Start.Before(from) {
+			iv.Start = from
+		}
+		if iv.End.After(to) {
+			iv.End = to
+		}
+		if iv.Start.Before(iv.End) {
+			out = append(out, iv)
+		}
+	}
+	return out
+}
+
+// mergeIntervals sorts the intervals and coalesces the ones that overlap or
+// touch.
+func mergeIntervals(in []structures.Interval) []structures.Interval {
+	sorted := slices.Clone(in)
+	slices.SortFunc(sorted, func(a, b structures.Interval) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	merged := make([]structures.Interval, 0, len(sorted))
+	for _, iv := range sorted {
+		if n := len(merged); n > 0 && !iv.Start.After(merged[n-1].End) {
+			if iv.End.After(merged[n-1].End) {
+				merged[n-1].End = iv.End
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// intersectIntervals returns the overlap of two sorted, non-overlapping
+// interval lists.
+func intersectIntervals(a, b []structures.Interval) []structures.Interval {
+	out := make([]structures.Interval, 0)
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start := a[i].Start
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		end := a[i].End
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+		if start.Before(end) {
+			out = append(out, structures.Interval{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// subtractIntervals removes the sorted, merged busy intervals from open.
+func subtractIntervals(open, busy []structures.Interval) []structures.Interval {
+	out := make([]structures.Interval, 0)
+	for _, o := range open {
+		cursor := o.Start
+		for _, b := range busy {
+			if !b.End.After(cursor) || !b.Start.Before(o.End) {
+				continue
+			}
+			if b.Start.After(cursor) {
+				out = append(out, structures.Interval{Start: cursor, End: b.Start})
+			}
+			cursor = b.End
+		}
+		if cursor.Before(o.End) {
+			out = append(out, structures.Interval{Start: cursor, End: o.End})
+		}
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockAvailabilityStore struct {
+	query structures.AvailabilityQuery
+	busy  []structures.Interval
+}
+
+func (m *mockAvailabilityStore) BusyIntervals(ctx context.Context, q structures.AvailabilityQuery) ([]structures.Interval, error) {
+	m.query = q
+	return m.busy, nil
+}
+
+type mockResourceLookup struct {
+	ResourceService
+	resources map[uuid.UUID]*structures.Resource
+}
+
+func (m *mockResourceLookup) GetResource(ctx context.Context, id uuid.UUID) (*structures.Resource, error) {
+	return m.resources[id], nil
+}
+
+func at(hour, min int) time.Time {
+	return time.Date(2025, 12, 10, hour, min, 0, 0, time.UTC) // a Wednesday
+}
+
+func TestAvailabilityService_MergesBusyAndFindsFreeSlots(t *testing.T) {
+	store := &mockAvailabilityStore{busy: []structures.Interval{
+		{Start: at(10, 0), End: at(11, 0)},
+		{Start: at(10, 30), End: at(11, 30)},
+		{Start: at(11, 30), End: at(12, 0)},
+		{Start: at(14, 0), End: at(14, 15)},
+	}}
+	svc := NewAvailabilityService(store, &mockResourceLookup{})
+
+	got, err := svc.GetAvailability(context.Background(), structures.AvailabilityQuery{
+		Attendees: []string{"Ana@Example.com"},
+		From:      at(9, 0),
+		To:        at(15, 0),
+		Duration:  30 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("GetAvailability returned error: %v", err)
+	}
+	if store.query.Attendees[0] != "ana@example.com" {
+		t.Fatalf("attendee email not normalised: %q", store.query.Attendees[0])
+	}
+
+	wantBusy := []structures.Interval{
+		{Start: at(10, 0), End: at(12, 0)},
+		{Start: at(14, 0), End: at(14, 15)},
+	}
+	assertIntervals(t, "busy", got.Busy, wantBusy)
+
+	wantFree := []structures.Interval{
+		{Start: at(9, 0), End: at(10, 0)},
+		{Start: at(12, 0), End: at(14, 0)},
+		{Start: at(14, 15), End: at(15, 0)},
+	}
+	assertIntervals(t, "free", got.FreeSlots, wantFree)
+}
+
+func TestAvailabilityService_HonoursWorkingHours(t *testing.T) {
+	roomID := uuid.New()
+	resources := &mockResourceLookup{resources: map[uuid.UUID]*structures.Resource{
+		roomID: {ID: roomID, WorkingHours: &structures.WorkingHours{Start: "09:00", End: "17:00", TimeZone: "UTC"}},
+	}}
+	store := &mockAvailabilityStore{busy: []structures.Interval{
+		{Start: at(12, 0), End: at(13, 0)},
+	}}
+	svc := NewAvailabilityService(store, resources)
+
+	got, err := svc.GetAvailability(context.Background(), structures.AvailabilityQuery{
+		ResourceIDs: []uuid.UUID{roomID},
+		From:        at(0, 0),
+		To:          at(0, 0).Add(24 * time.Hour),
+		Duration:    time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("GetAvailability returned error: %v", err)
+	}
+
+	wantFree := []structures.Interval{
+		{Start: at(9, 0), End: at(12, 0)},
+		{Start: at(13, 0), End: at(17, 0)},
+	}
+	assertIntervals(t, "free", got.FreeSlots, wantFree)
+}
+
+func TestAvailabilityService_UnknownResource(t *testing.T) {
+	svc := NewAvailabilityService(&mockAvailabilityStore{}, &mockResourceLookup{})
+
+	_, err := svc.GetAvailability(context.Background(), structures.AvailabilityQuery{
+		ResourceIDs: []uuid.UUID{uuid.New()},
+		From:        at(9, 0),
+		To:          at(10, 0),
+		Duration:    time.Minute,
+	})
+	if !errors.Is(err, structures.ErrResourceNotFound) {
+		t.Fatalf("expected ErrResourceNotFound, got %v", err)
+	}
+}
+
+func assertIntervals(t *testing.T, name string, got, want []structures.Interval) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d intervals %v, want %d %v", name, len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Fatalf("%s[%d]: got %v-%v, want %v-%v", name, i, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// CalendarService manages calendars and who may use them. Every call acts
+// as the actor in ctx: the store reports that actor's permission with each
+// calendar and the service enforces it.
+type CalendarService interface {
+	CreateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error)
+	// ListCalendars returns the calendars the caller can read.
+	ListCalendars(ctx context.Context) ([]structures.Calendar, error)
+	// GetCalendar returns nil when the calendar does not exist or the
+	// caller cannot read it.
+	GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error)
+	UpdateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error)
+	// DeleteCalendar fails with structures.ErrCalendarNotEmpty while the
+	// calendar has events outside the trash.
+	DeleteCalendar(ctx context.Context, id uuid.UUID) error
+
+	ShareCalendar(ctx context.Context, s *structures.CalendarShare) (*structures.CalendarShare, error)
+	ListCalendarShares(ctx context.Context, calendarID uuid.UUID) ([]structures.CalendarShare, error)
+	UnshareCalendar(ctx context.Context, calendarID uuid.UUID, actor string) error
+}
+
+type calendarService struct {
+	store CalendarService
+}
+
+func NewCalendarService(store CalendarService) CalendarService {
+	return &calendarService{store: store}
+}
+
+// CreateCalendar makes the caller the owner.
+func (s *calendarService) CreateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	c.Owner = structures.ActorFromContext(ctx)
+	return s.store.CreateCalendar(ctx, c)
+}
+
+func (s *calendarService) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	return s.store.ListCalendars(ctx)
+}
+
+func (s *calendarService) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	c, err := s.store.GetCalendar(ctx, id)
+	if err != nil || c == nil || c.Permission == "" {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateCalendar keeps the owner; only the name, color and time zone
+// change.
+func (s *calendarService) UpdateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	if _, err := s.authorize(ctx, c.ID, structures.PermissionManage); err != nil {
+		return nil, err
+	}
+	return s.store.UpdateCalendar(ctx, c)
+}
+
+func (s *calendarService) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.authorize(ctx, id, structures.PermissionManage); err != nil {
+		return err
+	}
+	return s.store.DeleteCalendar(ctx, id)
+}
+
+func (s *calendarService) ShareCalendar(ctx context.Context, sh *structures.CalendarShare) (*structures.CalendarShare, error) {
+	if _, err := s.authorize(ctx, sh.CalendarID, structures.PermissionManage); err != nil {
+		return nil, err
+	}
+	return s.store.ShareCalendar(ctx, sh)
+}
+
+func (s *calendarService) ListCalendarShares(ctx context.Context, calendarID uuid.UUID) ([]structures.CalendarShare, error) {
+	if _, err := s.authorize(ctx, calendarID, structures.PermissionManage); err != nil {
+		return nil, err
+	}
+	return s.store.ListCalendarShares(ctx, calendarID)
+}
+
+func (s *calendarService) UnshareCalendar(ctx context.Context, calendarID uuid.UUID, actor string) error {
+	if _, err := s.authorize(ctx, calendarID, structures.PermissionManage); err != nil {
+		return err
+	}
+	return s.store.UnshareCalendar(ctx, calendarID, actor)
+}
+
+// authorize loads the calendar and checks that the caller has at least
+// want on it. Calendars the caller cannot read are reported as not found,
+// so their existence does not leak.
+func (s *calendarService) authorize(ctx context.Context, id uuid.UUID, want string) (*structures.Calendar, error) {
+	c, err := s.GetCalendar(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, structures.ErrCalendarNotFound
+	}
+	if !structures.PermissionAllows(c.Permission, want) {
+		return nil, structures.ErrForbidden
+	}
+	return c, nil
+}
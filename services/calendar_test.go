@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockCalendarStore struct {
+	CalendarService
+
+	calendar  *structures.Calendar
+	created   *structures.Calendar
+	deletedID uuid.UUID
+}
+
+func (m *mockCalendarStore) CreateCalendar(ctx context.Context, c *structures.Calendar) (*structures.Calendar, error) {
+	m.created = c
+	return c, nil
+}
+
+func (m *mockCalendarStore) GetCalendar(ctx context.Context, id uuid.UUID) (*structures.Calendar, error) {
+	return m.calendar, nil
+}
+
+func (m *mockCalendarStore) DeleteCalendar(ctx context.Context, id uuid.UUID) error {
+	m.deletedID = id
+	return nil
+}
+
+func TestCalendarService_CreateCalendar_OwnedByCaller(t *testing.T) {
+	store := &mockCalendarStore{}
+	ctx := structures.WithActor(context.Background(), "token:abc")
+	if _, err := NewCalendarService(store).CreateCalendar(ctx, &structures.Calendar{Name: "Team"}); err != nil {
+		t.Fatal(err)
+	}
+	if store.created.Owner != "token:abc" {
+		t.Fatalf("expected the caller to own the calendar, got %q", store.created.Owner)
+	}
+}
+
+func TestCalendarService_EnforcesPermissions(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	for _, tc := range []struct {
+		permission string
+		want       error
+	}{
+		{"", structures.ErrCalendarNotFound},
+		{structures.PermissionRead, structures.ErrForbidden},
+		{structures.PermissionWrite, structures.ErrForbidden},
+		{structures.PermissionManage, nil},
+	} {
+		store := &mockCalendarStore{calendar: &structures.Calendar{ID: id, Permission: tc.permission}}
+		svc := NewCalendarService(store)
+
+		err := svc.DeleteCalendar(ctx, id)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%q: DeleteCalendar = %v, want %v", tc.permission, err, tc.want)
+		}
+		if (store.deletedID == id) != (tc.want == nil) {
+			t.Errorf("%q: store reached = %v", tc.permission, store.deletedID == id)
+		}
+		if tc.permission == "" {
+			if c, err := svc.GetCalendar(ctx, id); c != nil || err != nil {
+				t.Errorf("expected an unreadable calendar to look missing, got %+v, %v", c, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+// CommentStore keeps the discussion threads of events. Events in the trash
+// have no discussion: their comments are hidden and cannot change.
+type CommentStore interface {
+	// CreateComment fails with structures.ErrEventNotFound unless the
+	// event exists outside the trash, and with
+	// structures.ErrParentCommentNotFound unless the parent is a comment
+	// of the same event that is not deleted.
+	CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error)
+	// ListComments fails with structures.ErrEventNotFound, or with
+	// structures.ErrCommentNotFound when q.ParentID is not a comment of
+	// the event.
+	ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error)
+	// GetComment returns nil when the comment does not exist or its event
+	// is in the trash.
+	GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error)
+	// UpdateComment keeps the current body as a revision and replaces it.
+	// Deleted comments are not found.
+	UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string, editedAt time.Time) (*structures.Comment, error)
+	// ListCommentRevisions returns the earlier bodies of a comment, oldest
+	// first.
+	ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error)
+	// DeleteComment removes a comment without replies, and then any
+	// deleted ancestors left without replies. A comment with replies is
+	// turned into a tombstone and its revisions are dropped.
+	DeleteComment(ctx context.Context, eventID, id uuid.UUID) error
+}
+
+// CommentService is the discussion of events as the actor in ctx sees it:
+// comments are written by that actor and only their author may edit or
+// delete them.
+type CommentService interface {
+	CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error)
+	ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error)
+	GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error)
+	UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string) (*structures.Comment, error)
+	ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error)
+	DeleteComment(ctx context.Context, eventID, id uuid.UUID) error
+}
+
+// commentService stores bodies as written and renders them on every read,
+// so a fix to the renderer also covers comments written before it.
+type commentService struct {
+	store  CommentStore
+	access EventAccess
+	now    func() time.Time
+}
+
+// NewCommentService needs read permission on the event's calendar to read
+// the discussion and write to take part in it.
+func NewCommentService(store CommentStore, access EventAccess) CommentService {
+	return &commentService{store: store, access: access, now: time.Now}
+}
+
+func (s *commentService) CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error) {
+	if err := s.access.AuthorizeEvent(ctx, c.EventID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	c.Author = structures.ActorFromContext(ctx)
+	created, err := s.store.CreateComment(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	renderComment(created)
+	return created, nil
+}
+
+func (s *commentService) ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error) {
+	if err := s.access.AuthorizeEvent(ctx, q.EventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	page, err := s.store.ListComments(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Comments {
+		renderComment(&page.Comments[i])
+	}
+	return page, nil
+}
+
+func (s *commentService) GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	c, err := s.store.GetComment(ctx, eventID, id)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	renderComment(c)
+	return c, nil
+}
+
+func (s *commentService) UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string) (*structures.Comment, error) {
+	if err := s.authorize(ctx, eventID, id); err != nil {
+		return nil, err
+	}
+	c, err := s.store.UpdateComment(ctx, eventID, id, body, s.now())
+	if err != nil {
+		return nil, err
+	}
+	renderComment(c)
+	return c, nil
+}
+
+func (s *commentService) ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	revisions, err := s.store.ListCommentRevisions(ctx, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		revisions[i].BodyHTML = renderMarkdown(revisions[i].Body)
+	}
+	return revisions, nil
+}
+
+func (s *commentService) DeleteComment(ctx context.Context, eventID, id uuid.UUID) error {
+	if err := s.authorize(ctx, eventID, id); err != nil {
+		return err
+	}
+	return s.store.DeleteComment(ctx, eventID, id)
+}
+
+// authorize checks that the caller can write to the event and wrote the
+// comment. Tombstones are not found: there is nothing left to change.
+func (s *commentService) authorize(ctx context.Context, eventID, id uuid.UUID) error {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return err
+	}
+	c, err := s.store.GetComment(ctx, eventID, id)
+	if err != nil {
+		return err
+	}
+	if c == nil || c.Deleted {
+		return structures.ErrCommentNotFound
+	}
+	if c.Author != structures.ActorFromContext(ctx) {
+		return structures.ErrNotCommentAuthor
+	}
+	return nil
+}
+
+func renderComment(c *structures.Comment) {
+	if !c.Deleted {
+		c.BodyHTML = renderMarkdown(c.Body)
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockCommentStore struct {
+	comment *structures.Comment
+
+	created   *structures.Comment
+	updated   string
+	deleted   bool
+	revisions []structures.CommentRevision
+}
+
+func (m *mockCommentStore) CreateComment(ctx context.Context, c *structures.Comment) (*structures.Comment, error) {
+	m.created = c
+	return c, nil
+}
+
+func (m *mockCommentStore) ListComments(ctx context.Context, q structures.CommentQuery) (*structures.CommentPage, error) {
+	return &structures.CommentPage{Comments: []structures.Comment{*m.comment}}, nil
+}
+
+func (m *mockCommentStore) GetComment(ctx context.Context, eventID, id uuid.UUID) (*structures.Comment, error) {
+	if m.comment == nil {
+		return nil, nil
+	}
+	c := *m.comment
+	return &c, nil
+}
+
+func (m *mockCommentStore) UpdateComment(ctx context.Context, eventID, id uuid.UUID, body string, editedAt time.Time) (*structures.Comment, error) {
+	m.updated = body
+	c := *m.comment
+	c.Body, c.EditedAt = body, &editedAt
+	return &c, nil
+}
+
+func (m *mockCommentStore) ListCommentRevisions(ctx context.Context, eventID, id uuid.UUID) ([]structures.CommentRevision, error) {
+	return m.revisions, nil
+}
+
+func (m *mockCommentStore) DeleteComment(ctx context.Context, eventID, id uuid.UUID) error {
+	m.deleted = true
+	return nil
+}
+
+func TestCommentService_Create_SetsAuthorAndRenders(t *testing.T) {
+	store := &mockCommentStore{}
+	svc := NewCommentService(store, openAccess())
+
+	ctx := structures.WithActor(context.Background(), "token:abc")
+	c, err := svc.CreateComment(ctx, &structures.Comment{ID: uuid.New(), Body: "**Bring** <snacks>"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.created.Author != "token:abc" {
+		t.Errorf("expected the caller as author, got %q", store.created.Author)
+	}
+	if want := "<p><strong>Bring</strong> &lt;snacks&gt;</p>\n"; c.BodyHTML != want {
+		t.Errorf("expected %q, got %q", want, c.BodyHTML)
+	}
+}
+
+func TestCommentService_OnlyAuthorChanges(t *testing.T) {
+	store := &mockCommentStore{comment: &structures.Comment{ID: uuid.New(), Author: "token:abc", Body: "old"}}
+	svc := NewCommentService(store, openAccess())
+	eventID, id := uuid.New(), store.comment.ID
+
+	other := structures.WithActor(context.Background(), "token:other")
+	if _, err := svc.UpdateComment(other, eventID, id, "new"); !errors.Is(err, structures.ErrNotCommentAuthor) {
+		t.Errorf("expected ErrNotCommentAuthor on update, got %v", err)
+	}
+	if err := svc.DeleteComment(other, eventID, id); !errors.Is(err, structures.ErrNotCommentAuthor) {
+		t.Errorf("expected ErrNotCommentAuthor on delete, got %v", err)
+	}
+	if store.updated != "" || store.deleted {
+		t.Fatal("store changed for another actor")
+	}
+
+	author := structures.WithActor(context.Background(), "token:abc")
+	c, err := svc.UpdateComment(author, eventID, id, "*new*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.BodyHTML != "<p><em>new</em></p>\n" || c.EditedAt == nil {
+		t.Errorf("unexpected comment: %+v", c)
+	}
+	if err := svc.DeleteComment(author, eventID, id); err != nil || !store.deleted {
+		t.Errorf("expected delete, got %v", err)
+	}
+}
+
+func TestCommentService_TombstonesCannotChange(t *testing.T) {
+	store := &mockCommentStore{comment: &structures.Comment{ID: uuid.New(), Author: "token:abc", Deleted: true}}
+	svc := NewCommentService(store, openAccess())
+
+	ctx := structures.WithActor(context.Background(), "token:abc")
+	if _, err := svc.UpdateComment(ctx, uuid.New(), store.comment.ID, "back"); !errors.Is(err, structures.ErrCommentNotFound) {
+		t.Errorf("expected ErrCommentNotFound, got %v", err)
+	}
+	page, err := svc.ListComments(ctx, structures.CommentQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Comments[0].BodyHTML != "" {
+		t.Errorf("tombstone rendered: %q", page.Comments[0].BodyHTML)
+	}
+}
@@ -2,7 +2,15 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"events/structures"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,25 +18,312 @@ import (
 type EventService interface {
 	CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error)
 	ListEvents(ctx context.Context) ([]structures.Event, error)
+	QueryEvents(ctx context.Context, q structures.EventQuery) (*structures.EventPage, error)
+	GetEvents(ctx context.Context, ids []uuid.UUID) ([]structures.Event, error)
 	GetEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error)
+	UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error)
+	// DeleteEvent moves the event to the trash; RestoreEvent takes it out.
+	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error)
+
+	// TransitionEvent changes the event's status. The service checks the
+	// change against structures.EventTransitions and fills in c.From; the
+	// store applies it only while the event is still in c.From.
+	TransitionEvent(ctx context.Context, c structures.StatusChange) (*structures.Event, error)
+
+	// ListEventHistory returns every recorded mutation of the event, oldest
+	// first, including those of a deleted event.
+	ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error)
+	// GetEventAsOf returns the event as it was at the given time, or nil
+	// when it did not exist then.
+	GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*structures.Event, error)
+
+	// SearchEvents returns a page of the events matching q.Text, best match
+	// first, with headlines marked by HighlightStart and HighlightStop.
+	// The service turns those into escaped HTML.
+	SearchEvents(ctx context.Context, q structures.SearchQuery) (*structures.SearchPage, error)
+
+	// EventStats counts events and their hours per interval and group.
+	EventStats(ctx context.Context, q structures.StatsQuery) (*structures.EventStats, error)
+}
+
+// Publisher announces event changes to a message broker.
+type Publisher interface {
+	Publish(ctx context.Context, m structures.EventMessage) error
 }
 
+// PublisherSink is the outbox Sink for a Publisher: it turns the
+// event.created, event.updated and event.deleted messages into
+// EventMessages and skips the others. Going through the outbox relay keeps
+// a slow broker from holding up writes, and failures are retried rather
+// than lost.
+type PublisherSink struct {
+	Publisher Publisher
+}
+
+func (s PublisherSink) Publish(ctx context.Context, m structures.OutboxMessage) error {
+	msg := structures.EventMessage{
+		SchemaVersion: structures.EventSchemaVersion,
+		Type:          m.Type,
+		EventID:       m.EventID,
+		OccurredAt:    m.CreatedAt.UTC(),
+	}
+	switch m.Type {
+	case structures.EventCreated, structures.EventUpdated:
+		if err := json.Unmarshal(m.Payload, &msg.Event); err != nil {
+			return err
+		}
+	case structures.EventDeleted:
+	default:
+		return nil
+	}
+	return s.Publisher.Publish(ctx, msg)
+}
+
+// StatsCacheTTL is how long EventStats answers are reused. Dashboards poll
+// the same queries; counts a few seconds old are good enough for them.
+const StatsCacheTTL = 30 * time.Second
+
+// maxCachedStats bounds the stats cache; past it, expired answers are
+// dropped, and everything if none has expired.
+const maxCachedStats = 256
+
 type eventService struct {
-	store EventService
+	store  EventService
+	access EventAccess
+	now    func() time.Time
+
+	statsMu sync.Mutex
+	stats   map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats   *structures.EventStats
+	expires time.Time
 }
 
-func NewEventService(store EventService) EventService {
-	return &eventService{store: store}
+// NewEventService wraps store. Every call acts as the actor in ctx: reads
+// need read permission on the event's calendar and changes need write,
+// checked through access. Events the caller cannot read are reported as
+// not found and left out of listings. Changes reach brokers through the
+// outbox the store writes; see PublisherSink.
+func NewEventService(store EventService, access EventAccess) EventService {
+	return &eventService{store: store, access: access, now: time.Now, stats: make(map[string]cachedStats)}
 }
 
+// CreateEvent defaults the status to scheduled and the calendar to the
+// default one. Events can only start as drafts or scheduled.
 func (s *eventService) CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	if e.CalendarID == uuid.Nil {
+		e.CalendarID = structures.DefaultCalendarID
+	}
+	if err := s.access.AuthorizeCalendar(ctx, e.CalendarID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	if e.Status == "" {
+		e.Status = structures.StatusScheduled
+	}
+	if e.Status != structures.StatusDraft && e.Status != structures.StatusScheduled {
+		return nil, &structures.InvalidTransitionError{From: "new", To: e.Status}
+	}
 	return s.store.CreateEvent(ctx, e)
 }
 
 func (s *eventService) ListEvents(ctx context.Context) ([]structures.Event, error) {
-	return s.store.ListEvents(ctx)
+	events, err := s.store.ListEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.readable(ctx, events)
+}
+
+// QueryEvents clamps the page size to [1, MaxEventPageSize], defaulting to
+// DefaultEventPageSize.
+func (s *eventService) QueryEvents(ctx context.Context, q structures.EventQuery) (*structures.EventPage, error) {
+	if q.Limit <= 0 {
+		q.Limit = structures.DefaultEventPageSize
+	}
+	q.Limit = min(q.Limit, structures.MaxEventPageSize)
+	var err error
+	if q.CalendarIDs, err = s.access.ReadableCalendars(ctx); err != nil {
+		return nil, err
+	}
+	return s.store.QueryEvents(ctx, q)
+}
+
+func (s *eventService) GetEvents(ctx context.Context, ids []uuid.UUID) ([]structures.Event, error) {
+	if len(ids) == 0 {
+		return []structures.Event{}, nil
+	}
+	events, err := s.store.GetEvents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return s.readable(ctx, events)
 }
 
 func (s *eventService) GetEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
-	return s.store.GetEvent(ctx, id)
+	e, err := s.store.GetEvent(ctx, id)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	return s.visible(ctx, e)
+}
+
+func (s *eventService) UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	if err := s.access.AuthorizeEvent(ctx, e.ID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return s.store.UpdateEvent(ctx, e)
+}
+
+func (s *eventService) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	if err := s.access.AuthorizeEvent(ctx, id, structures.PermissionWrite); err != nil {
+		return err
+	}
+	return s.store.DeleteEvent(ctx, id)
+}
+
+// RestoreEvent is announced as event.created (the store writes the outbox
+// message): subscribers dropped the event when it was deleted.
+func (s *eventService) RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	if err := s.access.AuthorizeEvent(ctx, id, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return s.store.RestoreEvent(ctx, id)
+}
+
+func (s *eventService) TransitionEvent(ctx context.Context, c structures.StatusChange) (*structures.Event, error) {
+	current, err := s.store.GetEvent(ctx, c.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, structures.ErrEventNotFound
+	}
+	if err := s.access.AuthorizeCalendar(ctx, current.CalendarID, structures.PermissionWrite); err != nil {
+		return nil, eventPermissionError(err)
+	}
+	if !structures.CanTransition(current.Status, c.To) {
+		return nil, &structures.InvalidTransitionError{From: current.Status, To: c.To}
+	}
+	c.From = current.Status
+	return s.store.TransitionEvent(ctx, c)
+}
+
+func (s *eventService) ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error) {
+	if err := s.access.AuthorizeEvent(ctx, id, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.store.ListEventHistory(ctx, id)
+}
+
+func (s *eventService) GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*structures.Event, error) {
+	if at.After(s.now()) {
+		return s.GetEvent(ctx, id)
+	}
+	e, err := s.store.GetEventAsOf(ctx, id, at)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	return s.visible(ctx, e)
+}
+
+// visible returns e, or nil when the caller cannot read its calendar.
+func (s *eventService) visible(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	err := s.access.AuthorizeCalendar(ctx, e.CalendarID, structures.PermissionRead)
+	if errors.Is(err, structures.ErrCalendarNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// readable keeps the events whose calendar the caller can read.
+func (s *eventService) readable(ctx context.Context, events []structures.Event) ([]structures.Event, error) {
+	calendars, err := readableSet(ctx, s.access)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(events, func(e structures.Event) bool { return !calendars[e.CalendarID] }), nil
+}
+
+// SearchEvents clamps the page size like QueryEvents and turns the
+// store's highlight markers into <mark> elements, escaping the rest of
+// each headline.
+func (s *eventService) SearchEvents(ctx context.Context, q structures.SearchQuery) (*structures.SearchPage, error) {
+	if q.Limit <= 0 {
+		q.Limit = structures.DefaultEventPageSize
+	}
+	q.Limit = min(q.Limit, structures.MaxEventPageSize)
+	var err error
+	if q.CalendarIDs, err = s.access.ReadableCalendars(ctx); err != nil {
+		return nil, err
+	}
+	page, err := s.store.SearchEvents(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Results {
+		r := &page.Results[i]
+		r.TitleHeadline = highlight(r.TitleHeadline)
+		r.DescriptionHeadline = highlight(r.DescriptionHeadline)
+	}
+	return page, nil
+}
+
+var highlightReplacer = strings.NewReplacer(
+	structures.HighlightStart, "<mark>",
+	structures.HighlightStop, "</mark>",
+)
+
+func highlight(headline string) string {
+	return highlightReplacer.Replace(html.EscapeString(headline))
+}
+
+// EventStats answers from the cache while the same query, over the same
+// readable calendars, was answered less than StatsCacheTTL ago. Concurrent
+// misses may each ask the store.
+func (s *eventService) EventStats(ctx context.Context, q structures.StatsQuery) (*structures.EventStats, error) {
+	var err error
+	if q.CalendarIDs, err = s.access.ReadableCalendars(ctx); err != nil {
+		return nil, err
+	}
+	key := statsKey(q)
+	now := s.now()
+	s.statsMu.Lock()
+	cached, ok := s.stats[key]
+	s.statsMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.stats, nil
+	}
+
+	stats, err := s.store.EventStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	if len(s.stats) >= maxCachedStats {
+		maps.DeleteFunc(s.stats, func(_ string, c cachedStats) bool { return !now.Before(c.expires) })
+		if len(s.stats) >= maxCachedStats {
+			clear(s.stats)
+		}
+	}
+	s.stats[key] = cachedStats{stats: stats, expires: now.Add(StatsCacheTTL)}
+	return stats, nil
+}
+
+func statsKey(q structures.StatsQuery) string {
+	calendars := make([]string, 0, len(q.CalendarIDs))
+	for _, id := range q.CalendarIDs {
+		calendars = append(calendars, id.String())
+	}
+	slices.Sort(calendars)
+	return strings.Join([]string{
+		q.From.UTC().Format(time.RFC3339Nano), q.To.UTC().Format(time.RFC3339Nano),
+		q.Interval, q.TimeZone, q.GroupBy, strings.Join(calendars, ","),
+	}, "|")
 }
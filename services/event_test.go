@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -22,10 +23,34 @@ type mockEventService struct {
 	listResp   []structures.Event
 	listErr    error
 
+	queryArg structures.EventQuery
+
 	getCalled bool
 	getArgID  uuid.UUID
 	getResp   *structures.Event
 	getErr    error
+
+	updateCalled bool
+	updateArg    *structures.Event
+	updateResp   *structures.Event
+	updateErr    error
+
+	deleteCalled bool
+	deleteArgID  uuid.UUID
+	deleteErr    error
+
+	restoreResp *structures.Event
+	restoreErr  error
+
+	transitionArg  *structures.StatusChange
+	transitionResp *structures.Event
+
+	asOfCalled bool
+
+	searchArg  structures.SearchQuery
+	searchResp *structures.SearchPage
+
+	statsCalls int
 }
 
 func (m *mockEventService) CreateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
@@ -39,12 +64,61 @@ func (m *mockEventService) ListEvents(ctx context.Context) ([]structures.Event,
 	return m.listResp, m.listErr
 }
 
+func (m *mockEventService) QueryEvents(ctx context.Context, q structures.EventQuery) (*structures.EventPage, error) {
+	m.queryArg = q
+	return &structures.EventPage{}, nil
+}
+
+func (m *mockEventService) GetEvents(ctx context.Context, ids []uuid.UUID) ([]structures.Event, error) {
+	return nil, nil
+}
+
 func (m *mockEventService) GetEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
 	m.getCalled = true
 	m.getArgID = id
 	return m.getResp, m.getErr
 }
 
+func (m *mockEventService) UpdateEvent(ctx context.Context, e *structures.Event) (*structures.Event, error) {
+	m.updateCalled = true
+	m.updateArg = e
+	return m.updateResp, m.updateErr
+}
+
+func (m *mockEventService) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+	m.deleteCalled = true
+	m.deleteArgID = id
+	return m.deleteErr
+}
+
+func (m *mockEventService) RestoreEvent(ctx context.Context, id uuid.UUID) (*structures.Event, error) {
+	return m.restoreResp, m.restoreErr
+}
+
+func (m *mockEventService) TransitionEvent(ctx context.Context, c structures.StatusChange) (*structures.Event, error) {
+	m.transitionArg = &c
+	return m.transitionResp, nil
+}
+
+func (m *mockEventService) ListEventHistory(ctx context.Context, id uuid.UUID) ([]structures.EventHistoryEntry, error) {
+	return nil, nil
+}
+
+func (m *mockEventService) GetEventAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*structures.Event, error) {
+	m.asOfCalled = true
+	return nil, nil
+}
+
+func (m *mockEventService) SearchEvents(ctx context.Context, q structures.SearchQuery) (*structures.SearchPage, error) {
+	m.searchArg = q
+	return m.searchResp, nil
+}
+
+func (m *mockEventService) EventStats(ctx context.Context, q structures.StatsQuery) (*structures.EventStats, error) {
+	m.statsCalls++
+	return &structures.EventStats{Interval: q.Interval, Total: structures.StatsTotal{Events: m.statsCalls}}, nil
+}
+
 func TestEventService_CreateEvent_DelegatesToInner(t *testing.T) {
 	ctx := context.Background()
 
@@ -70,7 +144,7 @@ func TestEventService_CreateEvent_DelegatesToInner(t *testing.T) {
 		createErr:  nil,
 	}
 
-	svc := NewEventService(mockInner)
+	svc := NewEventService(mockInner, openAccess())
 
 	got, err := svc.CreateEvent(ctx, input)
 	if err != nil {
@@ -91,8 +165,8 @@ func TestEventService_ListEvents_DelegatesToInner(t *testing.T) {
 	ctx := context.Background()
 
 	expected := []structures.Event{
-		{ID: uuid.New(), Title: "A"},
-		{ID: uuid.New(), Title: "B"},
+		{ID: uuid.New(), CalendarID: structures.DefaultCalendarID, Title: "A"},
+		{ID: uuid.New(), CalendarID: structures.DefaultCalendarID, Title: "B"},
 	}
 
 	mockInner := &mockEventService{
@@ -100,7 +174,7 @@ func TestEventService_ListEvents_DelegatesToInner(t *testing.T) {
 		listErr:  nil,
 	}
 
-	svc := NewEventService(mockInner)
+	svc := NewEventService(mockInner, openAccess())
 
 	got, err := svc.ListEvents(ctx)
 	if err != nil {
@@ -114,13 +188,29 @@ func TestEventService_ListEvents_DelegatesToInner(t *testing.T) {
 	}
 }
 
+func TestEventService_QueryEvents_ClampsLimit(t *testing.T) {
+	cases := map[int]int{0: structures.DefaultEventPageSize, 5: 5, 1000: structures.MaxEventPageSize}
+	for limit, want := range cases {
+		mockInner := &mockEventService{}
+		svc := NewEventService(mockInner, openAccess())
+
+		if _, err := svc.QueryEvents(context.Background(), structures.EventQuery{Limit: limit}); err != nil {
+			t.Fatalf("QueryEvents returned error: %v", err)
+		}
+		if mockInner.queryArg.Limit != want {
+			t.Fatalf("limit %d: expected %d, got %d", limit, want, mockInner.queryArg.Limit)
+		}
+	}
+}
+
 func TestEventService_GetEvent_DelegatesToInner(t *testing.T) {
 	ctx := context.Background()
 
 	id := uuid.New()
 	expected := &structures.Event{
-		ID:    id,
-		Title: "Found",
+		ID:         id,
+		CalendarID: structures.DefaultCalendarID,
+		Title:      "Found",
 	}
 
 	mockInner := &mockEventService{
@@ -128,7 +218,7 @@ func TestEventService_GetEvent_DelegatesToInner(t *testing.T) {
 		getErr:  nil,
 	}
 
-	svc := NewEventService(mockInner)
+	svc := NewEventService(mockInner, openAccess())
 
 	got, err := svc.GetEvent(ctx, id)
 	if err != nil {
@@ -145,6 +235,24 @@ func TestEventService_GetEvent_DelegatesToInner(t *testing.T) {
 	}
 }
 
+func TestEventService_GetEventAsOf_FutureReadsCurrentEvent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockInner := &mockEventService{getResp: &structures.Event{CalendarID: structures.DefaultCalendarID, Title: "Current"}}
+	svc := NewEventService(mockInner, openAccess()).(*eventService)
+	svc.now = func() time.Time { return now }
+
+	if _, err := svc.GetEventAsOf(ctx, uuid.New(), now.Add(-time.Hour)); err != nil || !mockInner.asOfCalled {
+		t.Fatalf("expected a past instant to read the history, err=%v", err)
+	}
+	mockInner.asOfCalled = false
+
+	got, err := svc.GetEventAsOf(ctx, uuid.New(), now.Add(time.Hour))
+	if err != nil || mockInner.asOfCalled || got == nil || got.Title != "Current" {
+		t.Fatalf("expected a future instant to return the current event, got %+v, %v", got, err)
+	}
+}
+
 func TestEventService_PropagatesErrors(t *testing.T) {
 	ctx := context.Background()
 	wantErr := errors.New("inner error")
@@ -155,7 +263,7 @@ func TestEventService_PropagatesErrors(t *testing.T) {
 		getErr:    wantErr,
 	}
 
-	svc := NewEventService(mockInner)
+	svc := NewEventService(mockInner, openAccess())
 
 	if _, err := svc.CreateEvent(ctx, &structures.Event{}); err != wantErr {
 		t.Fatalf("CreateEvent did not propagate error: got %v, want %v", err, wantErr)
@@ -167,3 +275,213 @@ func TestEventService_PropagatesErrors(t *testing.T) {
 		t.Fatalf("GetEvent did not propagate error: got %v, want %v", err, wantErr)
 	}
 }
+
+type mockPublisher struct {
+	msgs []structures.EventMessage
+	err  error
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, msg structures.EventMessage) error {
+	m.msgs = append(m.msgs, msg)
+	return m.err
+}
+
+func TestPublisherSink_RelaysEventChanges(t *testing.T) {
+	ctx := context.Background()
+	created := structures.Event{ID: uuid.New(), Title: "Created"}
+	payload, _ := json.Marshal(created)
+	at := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	pub := &mockPublisher{}
+	sink := PublisherSink{Publisher: pub}
+
+	for _, m := range []structures.OutboxMessage{
+		{ID: 1, EventID: created.ID, Type: structures.EventCreated, Payload: payload, CreatedAt: at},
+		{ID: 2, EventID: created.ID, Type: structures.AttendeeAdded, Payload: []byte(`{"email":"ana@example.com"}`), CreatedAt: at},
+		{ID: 3, EventID: created.ID, Type: structures.EventDeleted, Payload: payload, CreatedAt: at},
+	} {
+		if err := sink.Publish(ctx, m); err != nil {
+			t.Fatalf("Publish(%s) returned error: %v", m.Type, err)
+		}
+	}
+
+	if len(pub.msgs) != 2 {
+		t.Fatalf("expected 2 published messages, got %d", len(pub.msgs))
+	}
+	first, second := pub.msgs[0], pub.msgs[1]
+	if first.Type != structures.EventCreated || first.Event == nil || first.Event.Title != "Created" ||
+		first.SchemaVersion != structures.EventSchemaVersion || !first.OccurredAt.Equal(at) {
+		t.Fatalf("unexpected create message: %+v", first)
+	}
+	if second.Type != structures.EventDeleted || second.EventID != created.ID || second.Event != nil {
+		t.Fatalf("unexpected delete message: %+v", second)
+	}
+
+	// A broker failure goes back to the relay, which retries the message.
+	pub.err = errors.New("broker down")
+	if err := sink.Publish(ctx, structures.OutboxMessage{ID: 4, EventID: created.ID, Type: structures.EventUpdated, Payload: payload}); err == nil {
+		t.Fatal("expected the broker error")
+	}
+}
+
+func TestEventService_RestoreEvent(t *testing.T) {
+	ctx := context.Background()
+	restored := &structures.Event{ID: uuid.New(), Title: "Back"}
+	mockInner := &mockEventService{restoreResp: restored}
+
+	got, err := NewEventService(mockInner, openAccess()).RestoreEvent(ctx, restored.ID)
+	if err != nil || got != restored {
+		t.Fatalf("RestoreEvent = %+v, %v", got, err)
+	}
+
+	mockInner.restoreErr = structures.ErrEventNotDeleted
+	if _, err := NewEventService(mockInner, openAccess()).RestoreEvent(ctx, restored.ID); !errors.Is(err, structures.ErrEventNotDeleted) {
+		t.Fatalf("expected ErrEventNotDeleted, got %v", err)
+	}
+}
+
+func TestEventService_TransitionEvent_EnforcesLifecycle(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{structures.StatusDraft, structures.StatusScheduled, true},
+		{structures.StatusDraft, structures.StatusCompleted, false},
+		{structures.StatusScheduled, structures.StatusPostponed, true},
+		{structures.StatusPostponed, structures.StatusCompleted, false},
+		{structures.StatusCancelled, structures.StatusScheduled, true},
+		{structures.StatusCompleted, structures.StatusScheduled, false},
+		{structures.StatusScheduled, structures.StatusScheduled, false},
+	}
+	for _, tc := range cases {
+		mockInner := &mockEventService{
+			getResp:        &structures.Event{ID: id, CalendarID: structures.DefaultCalendarID, Status: tc.from},
+			transitionResp: &structures.Event{ID: id, Status: tc.to},
+		}
+		_, err := NewEventService(mockInner, openAccess()).TransitionEvent(ctx, structures.StatusChange{EventID: id, To: tc.to, Reason: "r"})
+
+		var invalid *structures.InvalidTransitionError
+		if tc.allowed {
+			if err != nil || mockInner.transitionArg == nil || mockInner.transitionArg.From != tc.from {
+				t.Errorf("%s -> %s: expected the store to apply it from %s, got %v %+v", tc.from, tc.to, tc.from, err, mockInner.transitionArg)
+			}
+		} else if !errors.As(err, &invalid) || mockInner.transitionArg != nil {
+			t.Errorf("%s -> %s: expected InvalidTransitionError without reaching the store, got %v", tc.from, tc.to, err)
+		}
+	}
+
+	if _, err := NewEventService(&mockEventService{}, openAccess()).TransitionEvent(ctx, structures.StatusChange{EventID: id, To: structures.StatusCancelled}); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound for a missing event, got %v", err)
+	}
+}
+
+func TestEventService_CreateEvent_DefaultsStatus(t *testing.T) {
+	ctx := context.Background()
+	mockInner := &mockEventService{createResp: &structures.Event{}}
+	svc := NewEventService(mockInner, openAccess())
+
+	if _, err := svc.CreateEvent(ctx, &structures.Event{}); err != nil || mockInner.createArg.Status != structures.StatusScheduled {
+		t.Fatalf("expected the status to default to scheduled, got %q (%v)", mockInner.createArg.Status, err)
+	}
+	var invalid *structures.InvalidTransitionError
+	if _, err := svc.CreateEvent(ctx, &structures.Event{Status: structures.StatusCompleted}); !errors.As(err, &invalid) {
+		t.Fatalf("expected events not to start completed, got %v", err)
+	}
+}
+
+func TestEventService_SearchEvents_EscapesHeadlines(t *testing.T) {
+	mockInner := &mockEventService{searchResp: &structures.SearchPage{Results: []structures.SearchResult{{
+		TitleHeadline:       "<b>" + structures.HighlightStart + "Planning" + structures.HighlightStop + "</b> & review",
+		DescriptionHeadline: "Bring the " + structures.HighlightStart + "plans" + structures.HighlightStop,
+	}}}}
+	svc := NewEventService(mockInner, openAccess())
+
+	page, err := svc.SearchEvents(context.Background(), structures.SearchQuery{Text: "planning", Limit: 500})
+	if err != nil {
+		t.Fatalf("SearchEvents returned error: %v", err)
+	}
+	if mockInner.searchArg.Limit != structures.MaxEventPageSize {
+		t.Errorf("expected the limit to be clamped, got %d", mockInner.searchArg.Limit)
+	}
+	r := page.Results[0]
+	if r.TitleHeadline != "&lt;b&gt;<mark>Planning</mark>&lt;/b&gt; &amp; review" {
+		t.Errorf("unexpected title headline %q", r.TitleHeadline)
+	}
+	if r.DescriptionHeadline != "Bring the <mark>plans</mark>" {
+		t.Errorf("unexpected description headline %q", r.DescriptionHeadline)
+	}
+}
+
+func TestEventService_EventStats_CachesBriefly(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockInner := &mockEventService{}
+	svc := NewEventService(mockInner, openAccess()).(*eventService)
+	svc.now = func() time.Time { return now }
+	q := structures.StatsQuery{From: now.AddDate(0, 0, -7), To: now, Interval: structures.StatsDay, TimeZone: "UTC"}
+
+	first, _ := svc.EventStats(ctx, q)
+	again, _ := svc.EventStats(ctx, q)
+	if mockInner.statsCalls != 1 || again != first {
+		t.Fatalf("expected the second call to be cached, store called %d times", mockInner.statsCalls)
+	}
+
+	q.GroupBy = structures.StatsByTag
+	svc.EventStats(ctx, q)
+	if mockInner.statsCalls != 2 {
+		t.Fatalf("expected another query to miss the cache, store called %d times", mockInner.statsCalls)
+	}
+
+	now = now.Add(StatsCacheTTL)
+	if fresh, _ := svc.EventStats(ctx, q); mockInner.statsCalls != 3 || fresh.Total.Events != 3 {
+		t.Fatalf("expected an expired answer to be recomputed, store called %d times", mockInner.statsCalls)
+	}
+}
+
+func TestEventService_EnforcesCalendarPermissions(t *testing.T) {
+	ctx := context.Background()
+	readCal, privateCal := uuid.New(), uuid.New()
+	shared := structures.Event{ID: uuid.New(), CalendarID: readCal, Title: "Shared"}
+	private := structures.Event{ID: uuid.New(), CalendarID: privateCal, Title: "Private"}
+	calendars := &fakeCalendars{perms: map[uuid.UUID]string{readCal: structures.PermissionRead}}
+	access := NewEventAccess(fakeEventCalendars{shared.ID: readCal, private.ID: privateCal}, calendars)
+	mockInner := &mockEventService{listResp: []structures.Event{shared, private}, getResp: &private, searchResp: &structures.SearchPage{}}
+	svc := NewEventService(mockInner, access)
+
+	if got, err := svc.GetEvent(ctx, private.ID); err != nil || got != nil {
+		t.Fatalf("expected an event in an unshared calendar to be hidden, got %+v, %v", got, err)
+	}
+	if got, err := svc.ListEvents(ctx); err != nil || len(got) != 1 || got[0].ID != shared.ID {
+		t.Fatalf("expected only the shared event to be listed, got %+v, %v", got, err)
+	}
+	if _, err := svc.QueryEvents(ctx, structures.EventQuery{}); err != nil || len(mockInner.queryArg.CalendarIDs) != 1 || mockInner.queryArg.CalendarIDs[0] != readCal {
+		t.Fatalf("expected the query to be limited to the readable calendar, got %v, %v", mockInner.queryArg.CalendarIDs, err)
+	}
+	if _, err := svc.SearchEvents(ctx, structures.SearchQuery{Text: "x"}); err != nil || len(mockInner.searchArg.CalendarIDs) != 1 {
+		t.Fatalf("expected the search to be limited to the readable calendar, got %v, %v", mockInner.searchArg.CalendarIDs, err)
+	}
+
+	shared.Title = "Renamed"
+	if _, err := svc.UpdateEvent(ctx, &shared); !errors.Is(err, structures.ErrForbidden) || mockInner.updateCalled {
+		t.Fatalf("expected ErrForbidden on a read share without reaching the store, got %v", err)
+	}
+	if err := svc.DeleteEvent(ctx, private.ID); !errors.Is(err, structures.ErrEventNotFound) || mockInner.deleteCalled {
+		t.Fatalf("expected ErrEventNotFound on an unshared calendar without reaching the store, got %v", err)
+	}
+	if _, err := svc.ListEventHistory(ctx, private.ID); !errors.Is(err, structures.ErrEventNotFound) {
+		t.Fatalf("expected the history of a hidden event to be not found, got %v", err)
+	}
+	if _, err := svc.CreateEvent(ctx, &structures.Event{CalendarID: readCal}); !errors.Is(err, structures.ErrForbidden) || mockInner.createCalled {
+		t.Fatalf("expected ErrForbidden creating in a read share, got %v", err)
+	}
+
+	// Stats are cached per set of readable calendars.
+	q := structures.StatsQuery{Interval: structures.StatsDay, TimeZone: "UTC"}
+	svc.EventStats(ctx, q)
+	calendars.perms[privateCal] = structures.PermissionRead
+	svc.EventStats(ctx, q)
+	if mockInner.statsCalls != 2 {
+		t.Fatalf("expected callers with other calendars not to share cached stats, store called %d times", mockInner.statsCalls)
+	}
+}
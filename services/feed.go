@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"events/structures"
+	"log"
+	"sync"
+	"time"
+)
+
+// FeedStore reads the outbox as an ordered change log.
+type FeedStore interface {
+	OutboxAfter(ctx context.Context, afterID int64, limit int) ([]structures.OutboxMessage, error)
+	LatestOutboxID(ctx context.Context) (int64, error)
+}
+
+type ChangeFeedService interface {
+	// Subscribe registers a live subscriber and returns the id of the last
+	// message already broadcast; everything after it arrives on sub.C.
+	Subscribe(buffer int) (sub *FeedSubscription, watermark int64)
+	Unsubscribe(sub *FeedSubscription)
+	// Replay calls fn for every message in (afterID, upTo], in id order.
+	Replay(ctx context.Context, afterID, upTo int64, fn func(structures.OutboxMessage) error) error
+}
+
+// FeedSubscription receives live messages on C. A subscriber that falls so
+// far behind that its buffer fills up is dropped and C is closed; it can
+// resume from the last id it saw with Replay.
+type FeedSubscription struct {
+	C chan structures.OutboxMessage
+}
+
+const (
+	feedPageSize = 500
+	// feedGapTimeout is how long the feed waits for a missing outbox id.
+	// Ids are handed out before commit, so a gap is usually a transaction
+	// that is still committing; one that rolled back leaves a permanent gap.
+	feedGapTimeout = 5 * time.Second
+)
+
+// ChangeFeed tails the outbox and broadcasts new messages, in id order, to
+// every subscriber. Notify wakes it up (from LISTEN/NOTIFY); it also polls
+// every interval in case a notification was missed.
+type ChangeFeed struct {
+	store    FeedStore
+	interval time.Duration
+	wake     chan struct{}
+	now      func() time.Time
+
+	mu       sync.Mutex
+	subs     map[*FeedSubscription]struct{}
+	lastID   int64
+	gapSince time.Time
+}
+
+func NewChangeFeed(store FeedStore, interval time.Duration) *ChangeFeed {
+	return &ChangeFeed{
+		store:    store,
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+		now:      time.Now,
+		subs:     make(map[*FeedSubscription]struct{}),
+	}
+}
+
+// Notify asks the feed to look for new messages. It never blocks.
+func (f *ChangeFeed) Notify() {
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run starts from the newest outbox message and broadcasts everything
+// committed after it until ctx is cancelled.
+func (f *ChangeFeed) Run(ctx context.Context) error {
+	last, err := f.store.LatestOutboxID(ctx)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.lastID = last
+	f.mu.Unlock()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			f.closeAll()
+			return nil
+		case <-f.wake:
+		case <-ticker.C:
+		}
+		if err := f.poll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("change feed: %v", err)
+		}
+	}
+}
+
+func (f *ChangeFeed) poll(ctx context.Context) error {
+	for {
+		f.mu.Lock()
+		after := f.lastID
+		f.mu.Unlock()
+
+		msgs, err := f.store.OutboxAfter(ctx, after, feedPageSize)
+		if err != nil {
+			return err
+		}
+		if !f.broadcast(msgs) || len(msgs) < feedPageSize {
+			return nil
+		}
+	}
+}
+
+// broadcast sends msgs to every subscriber, stopping early at a gap in the
+// ids until feedGapTimeout has passed. It reports whether it got through
+// all of msgs.
+func (f *ChangeFeed) broadcast(msgs []structures.OutboxMessage) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, m := range msgs {
+		if m.ID != f.lastID+1 {
+			if f.gapSince.IsZero() {
+				f.gapSince = f.now()
+			}
+			if f.now().Sub(f.gapSince) < feedGapTimeout {
+				return false
+			}
+		}
+		f.gapSince = time.Time{}
+		f.lastID = m.ID
+
+		for sub := range f.subs {
+			select {
+			case sub.C <- m:
+			default:
+				delete(f.subs, sub)
+				close(sub.C)
+			}
+		}
+	}
+	return true
+}
+
+func (f *ChangeFeed) Subscribe(buffer int) (*FeedSubscription, int64) {
+	sub := &FeedSubscription{C: make(chan structures.OutboxMessage, buffer)}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subs[sub] = struct{}{}
+	return sub, f.lastID
+}
+
+func (f *ChangeFeed) Unsubscribe(sub *FeedSubscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subs[sub]; ok {
+		delete(f.subs, sub)
+		close(sub.C)
+	}
+}
+
+func (f *ChangeFeed) Replay(ctx context.Context, afterID, upTo int64, fn func(structures.OutboxMessage) error) error {
+	for afterID < upTo {
+		msgs, err := f.store.OutboxAfter(ctx, afterID, feedPageSize)
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+		for _, m := range msgs {
+			if m.ID > upTo {
+				return nil
+			}
+			if err := fn(m); err != nil {
+				return err
+			}
+			afterID = m.ID
+		}
+	}
+	return nil
+}
+
+func (f *ChangeFeed) closeAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		delete(f.subs, sub)
+		close(sub.C)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"events/structures"
+)
+
+type fakeFeedStore struct {
+	msgs []structures.OutboxMessage
+}
+
+func (f *fakeFeedStore) OutboxAfter(ctx context.Context, afterID int64, limit int) ([]structures.OutboxMessage, error) {
+	out := make([]structures.OutboxMessage, 0)
+	for _, m := range f.msgs {
+		if m.ID > afterID && len(out) < limit {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeFeedStore) LatestOutboxID(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func msg(id int64) structures.OutboxMessage {
+	return structures.OutboxMessage{ID: id, Type: structures.EventUpdated}
+}
+
+func TestChangeFeed_BroadcastsInOrderAndWaitsAtGaps(t *testing.T) {
+	store := &fakeFeedStore{msgs: []structures.OutboxMessage{msg(1), msg(2), msg(4)}}
+	feed := NewChangeFeed(store, time.Second)
+	now := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+	feed.now = func() time.Time { return now }
+
+	sub, watermark := feed.Subscribe(10)
+	if watermark != 0 {
+		t.Fatalf("unexpected watermark: %d", watermark)
+	}
+
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if got := drain(sub); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("expected 1, 2 before the gap, got %v", got)
+	}
+
+	// Id 3 commits late: it is sent before 4.
+	store.msgs = []structures.OutboxMessage{msg(1), msg(2), msg(3), msg(4)}
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if got := drain(sub); len(got) != 2 || got[0] != 3 || got[1] != 4 {
+		t.Fatalf("expected 3, 4, got %v", got)
+	}
+
+	// Id 5 never commits: 6 is sent once the gap times out.
+	store.msgs = append(store.msgs, msg(6))
+	_ = feed.poll(context.Background())
+	if got := drain(sub); len(got) != 0 {
+		t.Fatalf("expected nothing while waiting at the gap, got %v", got)
+	}
+	now = now.Add(feedGapTimeout)
+	_ = feed.poll(context.Background())
+	if got := drain(sub); len(got) != 1 || got[0] != 6 {
+		t.Fatalf("expected 6 after the gap timed out, got %v", got)
+	}
+}
+
+func TestChangeFeed_DropsSlowSubscriber(t *testing.T) {
+	store := &fakeFeedStore{msgs: []structures.OutboxMessage{msg(1), msg(2), msg(3)}}
+	feed := NewChangeFeed(store, time.Second)
+
+	slow, _ := feed.Subscribe(1)
+	fast, _ := feed.Subscribe(10)
+
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+	if got := drain(fast); len(got) != 3 {
+		t.Fatalf("fast subscriber expected 3 messages, got %v", got)
+	}
+	<-slow.C
+	if _, ok := <-slow.C; ok {
+		t.Fatalf("expected slow subscriber channel to be closed")
+	}
+	feed.Unsubscribe(slow) // must not panic on an already dropped subscriber
+}
+
+func TestChangeFeed_Replay(t *testing.T) {
+	store := &fakeFeedStore{msgs: []structures.OutboxMessage{msg(1), msg(2), msg(3), msg(4)}}
+	feed := NewChangeFeed(store, time.Second)
+
+	var got []int64
+	err := feed.Replay(context.Background(), 1, 3, func(m structures.OutboxMessage) error {
+		got = append(got, m.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("expected 2, 3, got %v", got)
+	}
+}
+
+func drain(sub *FeedSubscription) []int64 {
+	var ids []int64
+	for {
+		select {
+		case m, ok := <-sub.C:
+			if !ok {
+				return ids
+			}
+			ids = append(ids, m.ID)
+		default:
+			return ids
+		}
+	}
+}
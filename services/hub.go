@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"events/structures"
+	"log"
+	"sync"
+	"time"
+)
+
+type ChangeHub interface {
+	Register() *HubClient
+	Unregister(c *HubClient)
+}
+
+// HubClient is one live subscriber of the hub. It receives nothing until a
+// filter is set. When its buffer fills up the hub drops it and closes
+// Messages(), so a slow client cannot hold back the others.
+type HubClient struct {
+	send chan structures.OutboxMessage
+
+	mu     sync.Mutex
+	filter *structures.ChangeFilter
+}
+
+func (c *HubClient) Messages() <-chan structures.OutboxMessage {
+	return c.send
+}
+
+// SetFilter replaces the client's filter; nil unsubscribes it.
+func (c *HubClient) SetFilter(f *structures.ChangeFilter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filter = f
+}
+
+func (c *HubClient) matches(m structures.OutboxMessage, start, end *time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filter != nil && c.filter.Matches(m.EventID, start, end)
+}
+
+const hubFeedBuffer = 1024
+
+// Hub fans the change feed out to many clients, each with its own filter.
+type Hub struct {
+	feed   ChangeFeedService
+	buffer int
+
+	mu      sync.Mutex
+	clients map[*HubClient]struct{}
+}
+
+func NewHub(feed ChangeFeedService, clientBuffer int) *Hub {
+	return &Hub{feed: feed, buffer: clientBuffer, clients: make(map[*HubClient]struct{})}
+}
+
+func (h *Hub) Register() *HubClient {
+	c := &HubClient{send: make(chan structures.OutboxMessage, h.buffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[c] = struct{}{}
+	return c
+}
+
+func (h *Hub) Unregister(c *HubClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// Run dispatches feed messages until ctx is cancelled. If the hub itself
+// falls behind the feed it resubscribes and replays what it missed.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.closeAll()
+
+	sub, last := h.feed.Subscribe(hubFeedBuffer)
+	defer func() { h.feed.Unsubscribe(sub) }()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-sub.C:
+			if ok {
+				if m.ID > last {
+					last = m.ID
+					h.dispatch(m)
+				}
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			var watermark int64
+			sub, watermark = h.feed.Subscribe(hubFeedBuffer)
+			err := h.feed.Replay(ctx, last, watermark, func(m structures.OutboxMessage) error {
+				h.dispatch(m)
+				return nil
+			})
+			if err != nil {
+				log.Printf("hub replay: %v", err)
+			}
+			last = watermark
+		}
+	}
+}
+
+func (h *Hub) dispatch(m structures.OutboxMessage) {
+	start, end := changeWindow(m)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		if !c.matches(m, start, end) {
+			continue
+		}
+		select {
+		case c.send <- m:
+		default:
+			delete(h.clients, c)
+			close(c.send)
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// changeWindow extracts the time range of an event or booking payload.
+func changeWindow(m structures.OutboxMessage) (*time.Time, *time.Time) {
+	var window struct {
+		StartTime *time.Time `json:"start_time"`
+		EndTime   *time.Time `json:"end_time"`
+	}
+	if err := json.Unmarshal(m.Payload, &window); err != nil {
+		return nil, nil
+	}
+	return window.StartTime, window.EndTime
+}
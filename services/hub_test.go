@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+func TestHub_FiltersPerClient(t *testing.T) {
+	hub := NewHub(nil, 10)
+	watched := uuid.New()
+	nine := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+
+	byID := hub.Register()
+	byID.SetFilter(&structures.ChangeFilter{EventIDs: []uuid.UUID{watched}})
+	from, to := nine, nine.Add(2*time.Hour)
+	byWindow := hub.Register()
+	byWindow.SetFilter(&structures.ChangeFilter{From: &from, To: &to})
+	idle := hub.Register()
+
+	payload := func(start time.Time) json.RawMessage {
+		b, _ := json.Marshal(structures.Event{StartTime: start, EndTime: start.Add(time.Hour)})
+		return b
+	}
+	hub.dispatch(structures.OutboxMessage{ID: 1, EventID: watched, Type: structures.AttendeeAdded, Payload: json.RawMessage(`{}`)})
+	hub.dispatch(structures.OutboxMessage{ID: 2, EventID: uuid.New(), Type: structures.EventCreated, Payload: payload(nine.Add(time.Hour))})
+	hub.dispatch(structures.OutboxMessage{ID: 3, EventID: uuid.New(), Type: structures.EventCreated, Payload: payload(nine.Add(5 * time.Hour))})
+
+	if got := drainHub(byID); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("id subscriber expected [1], got %v", got)
+	}
+	if got := drainHub(byWindow); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("window subscriber expected [2], got %v", got)
+	}
+	if got := drainHub(idle); len(got) != 0 {
+		t.Fatalf("client without a filter expected nothing, got %v", got)
+	}
+}
+
+func TestHub_DropsSlowClient(t *testing.T) {
+	hub := NewHub(nil, 1)
+	slow := hub.Register()
+	slow.SetFilter(&structures.ChangeFilter{})
+
+	hub.dispatch(structures.OutboxMessage{ID: 1})
+	hub.dispatch(structures.OutboxMessage{ID: 2})
+
+	<-slow.Messages()
+	if _, ok := <-slow.Messages(); ok {
+		t.Fatalf("expected slow client to be dropped")
+	}
+	hub.Unregister(slow) // must not panic on an already dropped client
+}
+
+func TestHub_RunForwardsFeed(t *testing.T) {
+	store := &fakeFeedStore{msgs: []structures.OutboxMessage{msg(1)}}
+	feed := NewChangeFeed(store, time.Second)
+	hub := NewHub(feed, 10)
+	client := hub.Register()
+	client.SetFilter(&structures.ChangeFilter{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	// Wait for the hub to subscribe before the feed broadcasts.
+	for deadline := time.Now().Add(time.Second); ; {
+		feed.mu.Lock()
+		n := len(feed.subs)
+		feed.mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := feed.poll(ctx); err != nil {
+		t.Fatalf("poll returned error: %v", err)
+	}
+
+	select {
+	case m := <-client.Messages():
+		if m.ID != 1 {
+			t.Fatalf("unexpected message: %+v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for the hub")
+	}
+}
+
+func drainHub(c *HubClient) []int64 {
+	var ids []int64
+	for {
+		select {
+		case m := <-c.Messages():
+			ids = append(ids, m.ID)
+		default:
+			return ids
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// renderMarkdown renders the subset of markdown comments support:
+// paragraphs with hard line breaks, headings, block quotes, bulleted and
+// numbered lists, fenced code blocks, emphasis, code spans and links.
+//
+// It is a whitelist rather than a sanitiser: every byte of src is escaped
+// on its way out and the only markup in the result is what the renderer
+// writes itself. Raw HTML in src is shown as text, and links are only
+// kept for http, https and mailto URLs.
+func renderMarkdown(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"))
+	return b.String()
+}
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case isFence(line):
+			i++
+			var code []string
+			for ; i < len(lines) && !isFence(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // the closing fence, if any
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case headingLevel(line) > 0:
+			level := headingLevel(line)
+			tag := "h" + string(rune('0'+level))
+			b.WriteString("<" + tag + ">")
+			renderInline(b, strings.TrimSpace(line[level:]))
+			b.WriteString("</" + tag + ">\n")
+			i++
+
+		case strings.HasPrefix(line, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(lines[i], ">"); i++ {
+				l := strings.TrimPrefix(lines[i], ">")
+				quoted = append(quoted, strings.TrimPrefix(l, " "))
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case listItem(line, false) != "":
+			i = renderList(b, lines, i, false)
+
+		case listItem(line, true) != "":
+			i = renderList(b, lines, i, true)
+
+		default:
+			b.WriteString("<p>")
+			for first := true; i < len(lines) && startsParagraphLine(lines[i]); i++ {
+				if !first {
+					b.WriteString("<br>\n")
+				}
+				first = false
+				renderInline(b, strings.TrimSpace(lines[i]))
+			}
+			b.WriteString("</p>\n")
+		}
+	}
+}
+
+// startsParagraphLine reports whether line continues a paragraph rather
+// than ending it or starting another block.
+func startsParagraphLine(line string) bool {
+	return strings.TrimSpace(line) != "" && !isFence(line) && headingLevel(line) == 0 &&
+		!strings.HasPrefix(line, ">") && listItem(line, false) == "" && listItem(line, true) == ""
+}
+
+// renderList writes the run of list items starting at lines[i] and
+// returns the index of the first line after it.
+func renderList(b *strings.Builder, lines []string, i int, ordered bool) int {
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag + ">\n")
+	for ; i < len(lines); i++ {
+		item := listItem(lines[i], ordered)
+		if item == "" {
+			break
+		}
+		b.WriteString("<li>")
+		renderInline(b, strings.TrimSpace(item))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// listItem returns the text of a list item line, or "" when line is not
+// one: "- ", "* " or "+ " for bullets, digits and ". " for numbers.
+func listItem(line string, ordered bool) string {
+	line = strings.TrimLeft(line, " ")
+	if !ordered {
+		if len(line) > 2 && strings.IndexByte("-*+", line[0]) >= 0 && line[1] == ' ' {
+			return line[2:]
+		}
+		return ""
+	}
+	digits := 0
+	for digits < len(line) && digits < 9 && '0' <= line[digits] && line[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 && strings.HasPrefix(line[digits:], ". ") && len(line) > digits+2 {
+		return line[digits+2:]
+	}
+	return ""
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "```")
+}
+
+// headingLevel returns the level of an ATX heading such as "## Agenda",
+// or 0.
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && level < 7 && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// markdownPunct holds the characters a backslash escapes.
+const markdownPunct = "\\`*_[]()#+-.!>"
+
+func renderInline(b *strings.Builder, s string) {
+	start := 0
+	flush := func(end int) {
+		b.WriteString(html.EscapeString(s[start:end]))
+	}
+	for i := 0; i < len(s); {
+		n := 0 // bytes of markup consumed at i
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte(markdownPunct, s[i+1]) >= 0 {
+				flush(i)
+				b.WriteString(html.EscapeString(s[i+1 : i+2]))
+				n = 2
+			}
+
+		case '`':
+			if j := strings.IndexByte(s[i+1:], '`'); j > 0 {
+				flush(i)
+				b.WriteString("<code>" + html.EscapeString(s[i+1:i+1+j]) + "</code>")
+				n = j + 2
+			}
+
+		case '*', '_':
+			if c == '_' && i > 0 && isWordByte(s[i-1]) {
+				break
+			}
+			delim, tag := string(c), "em"
+			if i+1 < len(s) && s[i+1] == c {
+				delim, tag = delim+delim, "strong"
+			}
+			open := i + len(delim)
+			if open >= len(s) || s[open] == ' ' {
+				break
+			}
+			j := strings.Index(s[open:], delim)
+			if j <= 0 || s[open+j-1] == ' ' {
+				break
+			}
+			end := open + j + len(delim)
+			if c == '_' && end < len(s) && isWordByte(s[end]) {
+				break
+			}
+			flush(i)
+			b.WriteString("<" + tag + ">")
+			renderInline(b, s[open:open+j])
+			b.WriteString("</" + tag + ">")
+			n = end - i
+
+		case '[':
+			j := strings.Index(s[i:], "](")
+			if j < 0 {
+				break
+			}
+			k := closingParen(s[i+j+2:])
+			if k < 0 {
+				break
+			}
+			text, href := s[i+1:i+j], s[i+j+2:i+j+2+k]
+			flush(i)
+			if safeLink(href) {
+				b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">`)
+				renderInline(b, text)
+				b.WriteString("</a>")
+			} else {
+				renderInline(b, text)
+			}
+			n = j + 2 + k + 1
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+		i += n
+		start = i
+	}
+	flush(len(s))
+}
+
+// closingParen returns the index of the ")" that closes a link URL,
+// which may itself hold balanced parentheses, or -1.
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func isWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c >= 0x80
+}
+
+// safeLink accepts absolute http and https URLs and mailto addresses;
+// anything else, javascript: and data: included, is dropped.
+func safeLink(href string) bool {
+	if strings.ContainsAny(href, " \t\"'<>`") {
+		return false
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
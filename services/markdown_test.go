@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraphs", "Bring snacks.\nAnd water.\n\nSee you!", "<p>Bring snacks.<br>\nAnd water.</p>\n<p>See you!</p>\n"},
+		{"emphasis", "**Room 4** is *booked*, _really_", "<p><strong>Room 4</strong> is <em>booked</em>, <em>really</em></p>\n"},
+		{"snake case", "use start_time_utc", "<p>use start_time_utc</p>\n"},
+		{"code span", "run `make <test>`", "<p>run <code>make &lt;test&gt;</code></p>\n"},
+		{"escaped markup", `\*not bold\*`, "<p>*not bold*</p>\n"},
+		{"heading", "## Agenda", "<h2>Agenda</h2>\n"},
+		{"bullets", "- one\n- **two**", "<ul>\n<li>one</li>\n<li><strong>two</strong></li>\n</ul>\n"},
+		{"numbers", "1. one\n2. two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"quote", "> agreed\n> fully", "<blockquote>\n<p>agreed<br>\nfully</p>\n</blockquote>\n"},
+		{"fence", "```\n<b>x</b>\n**y**\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;\n**y**</code></pre>\n"},
+		{"link", "[slides](https://example.com/a?b=1&c=2)",
+			`<p><a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer">slides</a></p>` + "\n"},
+		{"mailto", "[Ana](mailto:ana@example.com)",
+			`<p><a href="mailto:ana@example.com" rel="nofollow noopener noreferrer">Ana</a></p>` + "\n"},
+		{"unclosed", "2 * 3 and [x", "<p>2 * 3 and [x</p>\n"},
+		{"crlf", "a\r\nb", "<p>a<br>\nb</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(tt.src); got != tt.want {
+				t.Errorf("renderMarkdown(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdown_Sanitises(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`<script>alert(1)</script>`, "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{`<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{`[click](javascript:alert(1))`, "<p>click</p>\n"},
+		{`[click](JaVaScRiPt:alert(1))`, "<p>click</p>\n"},
+		{`[click](data:text/html;base64,PHNjcmlwdD4=)`, "<p>click</p>\n"},
+		{`[click](//evil.example)`, "<p>click</p>\n"},
+		{`[x](https://a.example/"onmouseover="alert(1))`, "<p>x</p>\n"},
+		{`**<b onclick=x>**`, "<p><strong>&lt;b onclick=x&gt;</strong></p>\n"},
+		{"[<i>t</i>](https://a.example)", `<p><a href="https://a.example" rel="nofollow noopener noreferrer">&lt;i&gt;t&lt;/i&gt;</a></p>` + "\n"},
+	}
+	for _, tt := range tests {
+		if got := renderMarkdown(tt.src); got != tt.want {
+			t.Errorf("renderMarkdown(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdown_DeepNesting(t *testing.T) {
+	src := strings.Repeat("**", 2000) + "x" + strings.Repeat("**", 2000)
+	if got := renderMarkdown(src); !strings.Contains(got, "x") {
+		t.Errorf("lost the text: %q", got)
+	}
+}
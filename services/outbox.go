@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"events/structures"
+	"log"
+	"time"
+)
+
+// OutboxStore is the queue side of the transactional outbox.
+type OutboxStore interface {
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]structures.OutboxMessage, error)
+	MarkOutboxPublished(ctx context.Context, id int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, retryAt time.Time, cause string) error
+}
+
+// Sink receives outbox messages. Publish may be called more than once for
+// the same message, so sinks should be idempotent on OutboxMessage.ID.
+type Sink interface {
+	Publish(ctx context.Context, m structures.OutboxMessage) error
+}
+
+// MultiSink publishes every message to each of its sinks. If any of them
+// fails the whole message is retried, so the others may see it again.
+type MultiSink []Sink
+
+func (m MultiSink) Publish(ctx context.Context, msg structures.OutboxMessage) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+const (
+	outboxBatchSize = 100
+	// outboxLease reserves a claimed message for one publish. Each Publish
+	// is cut off after outboxPublishTimeout, well inside the lease, so a
+	// slow sink cannot make another relay publish the message again.
+	outboxLease          = time.Minute
+	outboxPublishTimeout = outboxLease / 2
+	retryBaseDelay       = time.Second
+	retryMaxBackoff      = 5 * time.Minute
+)
+
+// OutboxRelay moves committed outbox messages to a Sink.
+type OutboxRelay struct {
+	store    OutboxStore
+	sink     Sink
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewOutboxRelay(store OutboxStore, sink Sink, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{store: store, sink: sink, interval: interval, now: time.Now}
+}
+
+// Run relays messages every interval until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.RelayOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes up to outboxBatchSize due messages in order. A failed
+// message is rescheduled with exponential backoff and does not hold back
+// the rest. Messages are claimed one at a time, like webhook deliveries, so
+// each lease only has to cover its own publish. It returns how many
+// messages were published.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	published := 0
+	for range outboxBatchSize {
+		msgs, err := r.store.ClaimOutbox(ctx, 1, outboxLease)
+		if err != nil || len(msgs) == 0 {
+			return published, err
+		}
+		m := msgs[0]
+
+		publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+		err = r.sink.Publish(publishCtx, m)
+		cancel()
+		if err != nil {
+			retryAt := r.now().Add(retryBackoff(m.Attempts))
+			if err := r.store.MarkOutboxFailed(ctx, m.ID, retryAt, err.Error()); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := r.store.MarkOutboxPublished(ctx, m.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// retryBackoff doubles the delay with every failed attempt, up to
+// retryMaxBackoff. It is shared by the outbox relay and webhook deliveries.
+func retryBackoff(attempts int) time.Duration {
+	d := retryBaseDelay
+	for i := 0; i < attempts && d < retryMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, retryMaxBackoff)
+}
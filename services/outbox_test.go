@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"events/structures"
+)
+
+type mockOutboxStore struct {
+	claimed   []structures.OutboxMessage
+	published []int64
+	failed    map[int64]time.Time
+	claims    []int
+}
+
+func (m *mockOutboxStore) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]structures.OutboxMessage, error) {
+	m.claims = append(m.claims, limit)
+	n := min(limit, len(m.claimed))
+	claimed := m.claimed[:n]
+	m.claimed = m.claimed[n:]
+	return claimed, nil
+}
+
+func (m *mockOutboxStore) MarkOutboxPublished(ctx context.Context, id int64) error {
+	m.published = append(m.published, id)
+	return nil
+}
+
+func (m *mockOutboxStore) MarkOutboxFailed(ctx context.Context, id int64, retryAt time.Time, cause string) error {
+	if m.failed == nil {
+		m.failed = make(map[int64]time.Time)
+	}
+	m.failed[id] = retryAt
+	return nil
+}
+
+type mockSink struct {
+	failIDs  map[int64]bool
+	got      []int64
+	timeouts []time.Duration
+}
+
+func (m *mockSink) Publish(ctx context.Context, msg structures.OutboxMessage) error {
+	m.got = append(m.got, msg.ID)
+	if deadline, ok := ctx.Deadline(); ok {
+		m.timeouts = append(m.timeouts, time.Until(deadline))
+	}
+	if m.failIDs[msg.ID] {
+		return errors.New("sink unavailable")
+	}
+	return nil
+}
+
+func TestOutboxRelay_PublishesAndBacksOffFailures(t *testing.T) {
+	now := time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)
+	store := &mockOutboxStore{claimed: []structures.OutboxMessage{
+		{ID: 1, Type: structures.EventCreated},
+		{ID: 2, Type: structures.EventUpdated, Attempts: 3},
+		{ID: 3, Type: structures.EventDeleted},
+	}}
+	sink := &mockSink{failIDs: map[int64]bool{2: true}}
+
+	relay := NewOutboxRelay(store, sink, time.Second)
+	relay.now = func() time.Time { return now }
+
+	n, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RelayOnce returned error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 published, got %d", n)
+	}
+	if len(sink.got) != 3 || sink.got[0] != 1 || sink.got[2] != 3 {
+		t.Fatalf("unexpected publish order: %v", sink.got)
+	}
+	if len(store.published) != 2 || store.published[0] != 1 || store.published[1] != 3 {
+		t.Fatalf("unexpected published ids: %v", store.published)
+	}
+	if got := store.failed[2]; !got.Equal(now.Add(8 * time.Second)) {
+		t.Fatalf("unexpected retry time: %v", got)
+	}
+}
+
+func TestOutboxRelay_LeasesEachMessageForItsOwnPublish(t *testing.T) {
+	store := &mockOutboxStore{}
+	for id := range int64(outboxBatchSize + 5) {
+		store.claimed = append(store.claimed, structures.OutboxMessage{ID: id, Type: structures.EventCreated})
+	}
+	sink := &mockSink{}
+	relay := NewOutboxRelay(store, sink, time.Second)
+
+	n, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RelayOnce returned error: %v", err)
+	}
+	if n != outboxBatchSize || len(store.claimed) != 5 {
+		t.Fatalf("expected one batch to be published, got %d published and %d left", n, len(store.claimed))
+	}
+	if len(store.claims) != outboxBatchSize || store.claims[0] != 1 {
+		t.Fatalf("expected %d claims of one message, got %v", outboxBatchSize, store.claims)
+	}
+	if len(sink.timeouts) != outboxBatchSize {
+		t.Fatalf("expected every publish to have a deadline, got %d", len(sink.timeouts))
+	}
+	for _, timeout := range sink.timeouts {
+		if timeout <= 0 || timeout > outboxPublishTimeout || outboxPublishTimeout >= outboxLease {
+			t.Fatalf("expected every publish to end inside its lease, got a %v timeout", timeout)
+		}
+	}
+}
+
+func TestRetryBackoff_IsCapped(t *testing.T) {
+	if got := retryBackoff(0); got != time.Second {
+		t.Fatalf("expected 1s, got %v", got)
+	}
+	if got := retryBackoff(50); got != retryMaxBackoff {
+		t.Fatalf("expected %v, got %v", retryMaxBackoff, got)
+	}
+}
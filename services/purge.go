@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PurgeStore permanently removes events that have been in the trash for
+// too long.
+type PurgeStore interface {
+	PurgeDeletedEvents(ctx context.Context, cutoff time.Time, limit int) (int, error)
+}
+
+const purgeBatchSize = 100
+
+// EventPurger hard-deletes events that were deleted more than retention
+// ago.
+type EventPurger struct {
+	store     PurgeStore
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewEventPurger(store PurgeStore, retention, interval time.Duration) *EventPurger {
+	return &EventPurger{store: store, retention: retention, interval: interval, now: time.Now}
+}
+
+// Run purges every interval until ctx is cancelled.
+func (p *EventPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if n, err := p.PurgeOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("event purge: %v", err)
+		} else if n > 0 {
+			log.Printf("event purge: removed %d events", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce removes expired events in batches until none are left and
+// returns how many it removed.
+func (p *EventPurger) PurgeOnce(ctx context.Context) (int, error) {
+	return purgeBatches(ctx, p.store.PurgeDeletedEvents, p.now().Add(-p.retention))
+}
+
+// purgeBatches calls purge until it removes less than a full batch.
+func purgeBatches(ctx context.Context, purge func(ctx context.Context, cutoff time.Time, limit int) (int, error), cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		n, err := purge(ctx, cutoff, purgeBatchSize)
+		total += n
+		if err != nil || n < purgeBatchSize {
+			return total, err
+		}
+	}
+}
+
+// DeliveryPurgeStore removes webhook deliveries that are no longer
+// pending.
+type DeliveryPurgeStore interface {
+	PurgeWebhookDeliveries(ctx context.Context, cutoff time.Time, limit int) (int, error)
+}
+
+// OutboxPurgeStore removes published outbox messages that no pending
+// webhook delivery still needs.
+type OutboxPurgeStore interface {
+	PurgeOutbox(ctx context.Context, cutoff time.Time, limit int) (int, error)
+}
+
+// OutboxPurger deletes finished webhook deliveries and published outbox
+// messages created more than retention ago. Change streams cannot resume
+// from before then.
+type OutboxPurger struct {
+	deliveries DeliveryPurgeStore
+	outbox     OutboxPurgeStore
+	retention  time.Duration
+	interval   time.Duration
+	now        func() time.Time
+}
+
+func NewOutboxPurger(deliveries DeliveryPurgeStore, outbox OutboxPurgeStore, retention, interval time.Duration) *OutboxPurger {
+	return &OutboxPurger{deliveries: deliveries, outbox: outbox, retention: retention, interval: interval, now: time.Now}
+}
+
+// Run purges every interval until ctx is cancelled.
+func (p *OutboxPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if n, err := p.PurgeOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("outbox purge: %v", err)
+		} else if n > 0 {
+			log.Printf("outbox purge: removed %d rows", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce removes expired deliveries, then expired messages, and returns
+// how many rows it removed.
+func (p *OutboxPurger) PurgeOnce(ctx context.Context) (int, error) {
+	cutoff := p.now().Add(-p.retention)
+	deliveries, err := purgeBatches(ctx, p.deliveries.PurgeWebhookDeliveries, cutoff)
+	if err != nil {
+		return deliveries, err
+	}
+	messages, err := purgeBatches(ctx, p.outbox.PurgeOutbox, cutoff)
+	return deliveries + messages, err
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockPurgeStore struct {
+	remaining int
+	cutoffs   []time.Time
+	err       error
+}
+
+func (m *mockPurgeStore) PurgeDeletedEvents(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	m.cutoffs = append(m.cutoffs, cutoff)
+	if m.err != nil {
+		return 0, m.err
+	}
+	n := min(m.remaining, limit)
+	m.remaining -= n
+	return n, nil
+}
+
+func TestEventPurger_PurgesInBatchesPastRetention(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &mockPurgeStore{remaining: 2*purgeBatchSize + 5}
+	p := NewEventPurger(store, 30*24*time.Hour, time.Hour)
+	p.now = func() time.Time { return now }
+
+	n, err := p.PurgeOnce(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeOnce returned error: %v", err)
+	}
+	if n != 2*purgeBatchSize+5 || len(store.cutoffs) != 3 {
+		t.Fatalf("expected everything purged in 3 batches, got %d in %d", n, len(store.cutoffs))
+	}
+	if want := now.AddDate(0, 0, -30); !store.cutoffs[0].Equal(want) {
+		t.Fatalf("cutoff = %v, want %v", store.cutoffs[0], want)
+	}
+}
+
+func TestEventPurger_StopsOnError(t *testing.T) {
+	store := &mockPurgeStore{err: errors.New("db down")}
+	p := NewEventPurger(store, time.Hour, time.Hour)
+
+	if _, err := p.PurgeOnce(context.Background()); err == nil || len(store.cutoffs) != 1 {
+		t.Fatalf("expected one failed batch, got %v after %d", err, len(store.cutoffs))
+	}
+}
+
+type mockOutboxPurgeStore struct {
+	deliveries, messages mockPurgeStore
+	order                []string
+}
+
+func (m *mockOutboxPurgeStore) PurgeWebhookDeliveries(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	m.order = append(m.order, "deliveries")
+	return m.deliveries.PurgeDeletedEvents(ctx, cutoff, limit)
+}
+
+func (m *mockOutboxPurgeStore) PurgeOutbox(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	m.order = append(m.order, "outbox")
+	return m.messages.PurgeDeletedEvents(ctx, cutoff, limit)
+}
+
+func TestOutboxPurger_PurgesDeliveriesBeforeMessages(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := &mockOutboxPurgeStore{
+		deliveries: mockPurgeStore{remaining: purgeBatchSize + 1},
+		messages:   mockPurgeStore{remaining: 3},
+	}
+	p := NewOutboxPurger(store, store, 7*24*time.Hour, time.Hour)
+	p.now = func() time.Time { return now }
+
+	n, err := p.PurgeOnce(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeOnce returned error: %v", err)
+	}
+	if n != purgeBatchSize+4 || strings.Join(store.order, ",") != "deliveries,deliveries,outbox" {
+		t.Fatalf("expected both batches of deliveries before the messages, got %d rows in %v", n, store.order)
+	}
+	if want := now.AddDate(0, 0, -7); !store.messages.cutoffs[0].Equal(want) {
+		t.Fatalf("cutoff = %v, want %v", store.messages.cutoffs[0], want)
+	}
+
+	store.deliveries.err = errors.New("db down")
+	store.order = nil
+	if _, err := p.PurgeOnce(context.Background()); err == nil || len(store.order) != 1 {
+		t.Fatalf("expected the messages to wait for the deliveries, got %v after %v", err, store.order)
+	}
+}
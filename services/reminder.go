@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"events/structures"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReminderService interface {
+	CreateReminder(ctx context.Context, r *structures.Reminder) (*structures.Reminder, error)
+	ListReminders(ctx context.Context, eventID uuid.UUID) ([]structures.Reminder, error)
+	DeleteReminder(ctx context.Context, eventID, id uuid.UUID) error
+}
+
+// ReminderStore adds the scheduler's queue to the reminder CRUD.
+type ReminderStore interface {
+	ReminderService
+	ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]structures.DueReminder, error)
+	MarkReminderSent(ctx context.Context, id uuid.UUID) error
+	MarkReminderFailed(ctx context.Context, id uuid.UUID, cause string, retryAt *time.Time) error
+}
+
+// Notifier delivers one reminder over one channel.
+type Notifier interface {
+	Notify(ctx context.Context, d structures.DueReminder) error
+}
+
+type reminderService struct {
+	store  ReminderStore
+	access EventAccess
+}
+
+// NewReminderService needs read permission on the event's calendar to list
+// reminders and write to change them.
+func NewReminderService(store ReminderStore, access EventAccess) ReminderService {
+	return &reminderService{store: store, access: access}
+}
+
+func (s *reminderService) CreateReminder(ctx context.Context, r *structures.Reminder) (*structures.Reminder, error) {
+	if err := s.access.AuthorizeEvent(ctx, r.EventID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	if r.Channel == structures.ReminderEmail {
+		r.Target = normalizeEmail(r.Target)
+	}
+	return s.store.CreateReminder(ctx, r)
+}
+
+func (s *reminderService) ListReminders(ctx context.Context, eventID uuid.UUID) ([]structures.Reminder, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.store.ListReminders(ctx, eventID)
+}
+
+func (s *reminderService) DeleteReminder(ctx context.Context, eventID, id uuid.UUID) error {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return err
+	}
+	return s.store.DeleteReminder(ctx, eventID, id)
+}
+
+const (
+	reminderBatchSize   = 50
+	reminderLease       = time.Minute
+	maxReminderAttempts = 5
+)
+
+var errEventAlreadyEnded = errors.New("event already ended")
+
+// ReminderScheduler sends due reminders through the Notifier registered
+// for their channel. Every replica can run one; the store's SKIP LOCKED
+// claim keeps them from sending the same reminder.
+type ReminderScheduler struct {
+	store     ReminderStore
+	notifiers map[string]Notifier
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewReminderScheduler(store ReminderStore, notifiers map[string]Notifier, interval time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{store: store, notifiers: notifiers, interval: interval, now: time.Now}
+}
+
+// Run sends reminders every interval until ctx is cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("reminder scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends one batch of due reminders and returns how many were sent.
+func (s *ReminderScheduler) RunOnce(ctx context.Context) (int, error) {
+	due, err := s.store.ClaimDueReminders(ctx, reminderBatchSize, reminderLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, d := range due {
+		err := s.notify(ctx, d)
+		if err == nil {
+			if err := s.store.MarkReminderSent(ctx, d.Reminder.ID); err != nil {
+				return sent, err
+			}
+			sent++
+			continue
+		}
+
+		var retryAt *time.Time
+		if d.Reminder.Attempts+1 < maxReminderAttempts && !errors.Is(err, errEventAlreadyEnded) {
+			at := s.now().Add(retryBackoff(d.Reminder.Attempts))
+			retryAt = &at
+		}
+		if err := s.store.MarkReminderFailed(ctx, d.Reminder.ID, err.Error(), retryAt); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func (s *ReminderScheduler) notify(ctx context.Context, d structures.DueReminder) error {
+	// A reminder that comes due after the event (for example because the
+	// scheduler was down) is no longer useful.
+	if !s.now().Before(d.Event.EndTime) {
+		return errEventAlreadyEnded
+	}
+	n, ok := s.notifiers[d.Reminder.Channel]
+	if !ok {
+		return fmt.Errorf("no notifier for channel %q", d.Reminder.Channel)
+	}
+	return n.Notify(ctx, d)
+}
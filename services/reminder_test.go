@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockReminderStore struct {
+	ReminderService
+
+	due    []structures.DueReminder
+	sent   []uuid.UUID
+	failed map[uuid.UUID]*time.Time
+}
+
+func (m *mockReminderStore) ClaimDueReminders(ctx context.Context, limit int, lease time.Duration) ([]structures.DueReminder, error) {
+	return m.due, nil
+}
+
+func (m *mockReminderStore) MarkReminderSent(ctx context.Context, id uuid.UUID) error {
+	m.sent = append(m.sent, id)
+	return nil
+}
+
+func (m *mockReminderStore) MarkReminderFailed(ctx context.Context, id uuid.UUID, cause string, retryAt *time.Time) error {
+	if m.failed == nil {
+		m.failed = make(map[uuid.UUID]*time.Time)
+	}
+	m.failed[id] = retryAt
+	return nil
+}
+
+type notifierFunc func(ctx context.Context, d structures.DueReminder) error
+
+func (f notifierFunc) Notify(ctx context.Context, d structures.DueReminder) error { return f(ctx, d) }
+
+func TestReminderScheduler_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	event := structures.Event{ID: uuid.New(), Title: "Standup", StartTime: now.Add(15 * time.Minute), EndTime: now.Add(time.Hour)}
+	ended := structures.Event{ID: uuid.New(), Title: "Yesterday", StartTime: now.Add(-25 * time.Hour), EndTime: now.Add(-24 * time.Hour)}
+
+	ok := structures.DueReminder{Reminder: structures.Reminder{ID: uuid.New(), Channel: structures.ReminderLog}, Event: event}
+	flaky := structures.DueReminder{Reminder: structures.Reminder{ID: uuid.New(), Channel: structures.ReminderWebhook, Attempts: 1}, Event: event}
+	exhausted := structures.DueReminder{Reminder: structures.Reminder{ID: uuid.New(), Channel: structures.ReminderWebhook, Attempts: maxReminderAttempts - 1}, Event: event}
+	stale := structures.DueReminder{Reminder: structures.Reminder{ID: uuid.New(), Channel: structures.ReminderLog}, Event: ended}
+
+	store := &mockReminderStore{due: []structures.DueReminder{ok, flaky, exhausted, stale}}
+	var logged []uuid.UUID
+	s := NewReminderScheduler(store, map[string]Notifier{
+		structures.ReminderLog: notifierFunc(func(ctx context.Context, d structures.DueReminder) error {
+			logged = append(logged, d.Reminder.ID)
+			return nil
+		}),
+		structures.ReminderWebhook: notifierFunc(func(ctx context.Context, d structures.DueReminder) error {
+			return errors.New("receiver down")
+		}),
+	}, time.Second)
+	s.now = func() time.Time { return now }
+
+	sent, err := s.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 1 || len(store.sent) != 1 || store.sent[0] != ok.Reminder.ID {
+		t.Fatalf("expected only %v to be sent, got %v", ok.Reminder.ID, store.sent)
+	}
+	if len(logged) != 1 {
+		t.Fatalf("expected the ended event not to be notified, got %v", logged)
+	}
+	if at := store.failed[flaky.Reminder.ID]; at == nil || !at.Equal(now.Add(2*time.Second)) {
+		t.Fatalf("expected flaky reminder to retry at %v, got %v", now.Add(2*time.Second), at)
+	}
+	if at, found := store.failed[exhausted.Reminder.ID]; !found || at != nil {
+		t.Fatalf("expected exhausted reminder to give up, got %v", at)
+	}
+	if at, found := store.failed[stale.Reminder.ID]; !found || at != nil {
+		t.Fatalf("expected reminder for an ended event to give up, got %v", at)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type ResourceService interface {
+	CreateResource(ctx context.Context, r *structures.Resource) (*structures.Resource, error)
+	ListResources(ctx context.Context) ([]structures.Resource, error)
+	GetResource(ctx context.Context, id uuid.UUID) (*structures.Resource, error)
+	BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error)
+	ListEventResources(ctx context.Context, eventID uuid.UUID) ([]structures.Resource, error)
+	ListResourcesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Resource, error)
+	ReleaseResource(ctx context.Context, eventID, resourceID uuid.UUID) error
+}
+
+type resourceService struct {
+	store  ResourceService
+	access EventAccess
+}
+
+// NewResourceService checks bookings like attendees: read permission on
+// the event's calendar to list them and write to change them. Resources
+// themselves are shared by every calendar.
+func NewResourceService(store ResourceService, access EventAccess) ResourceService {
+	return &resourceService{store: store, access: access}
+}
+
+func (s *resourceService) CreateResource(ctx context.Context, r *structures.Resource) (*structures.Resource, error) {
+	return s.store.CreateResource(ctx, r)
+}
+
+func (s *resourceService) ListResources(ctx context.Context) ([]structures.Resource, error) {
+	return s.store.ListResources(ctx)
+}
+
+func (s *resourceService) GetResource(ctx context.Context, id uuid.UUID) (*structures.Resource, error) {
+	return s.store.GetResource(ctx, id)
+}
+
+func (s *resourceService) BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return nil, err
+	}
+	return s.store.BookResource(ctx, eventID, resourceID)
+}
+
+func (s *resourceService) ListResourcesForEvents(ctx context.Context, eventIDs []uuid.UUID) (map[uuid.UUID][]structures.Resource, error) {
+	return s.store.ListResourcesForEvents(ctx, eventIDs)
+}
+
+func (s *resourceService) ListEventResources(ctx context.Context, eventID uuid.UUID) ([]structures.Resource, error) {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.store.ListEventResources(ctx, eventID)
+}
+
+func (s *resourceService) ReleaseResource(ctx context.Context, eventID, resourceID uuid.UUID) error {
+	if err := s.access.AuthorizeEvent(ctx, eventID, structures.PermissionWrite); err != nil {
+		return err
+	}
+	return s.store.ReleaseResource(ctx, eventID, resourceID)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockResourceStore struct {
+	ResourceService
+
+	bookEventID    uuid.UUID
+	bookResourceID uuid.UUID
+	bookErr        error
+}
+
+func (m *mockResourceStore) BookResource(ctx context.Context, eventID, resourceID uuid.UUID) (*structures.Booking, error) {
+	m.bookEventID = eventID
+	m.bookResourceID = resourceID
+	return nil, m.bookErr
+}
+
+func TestResourceService_BookResource_PropagatesConflict(t *testing.T) {
+	conflict := &structures.BookingConflictError{ResourceID: uuid.New(), EventIDs: []uuid.UUID{uuid.New()}}
+	store := &mockResourceStore{bookErr: conflict}
+	svc := NewResourceService(store, openAccess())
+
+	eventID, resourceID := uuid.New(), uuid.New()
+	_, err := svc.BookResource(context.Background(), eventID, resourceID)
+
+	var got *structures.BookingConflictError
+	if !errors.As(err, &got) || got != conflict {
+		t.Fatalf("expected conflict error to propagate, got %v", err)
+	}
+	if store.bookEventID != eventID || store.bookResourceID != resourceID {
+		t.Fatalf("store called with wrong ids: %v %v", store.bookEventID, store.bookResourceID)
+	}
+}
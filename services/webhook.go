@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"events/structures"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, w *structures.Webhook) (*structures.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]structures.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*structures.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit int) ([]structures.WebhookDelivery, error)
+}
+
+// WebhookStore adds the delivery queue to the subscription CRUD.
+type WebhookStore interface {
+	WebhookService
+	WebhookSubscribers(ctx context.Context, messageType string) ([]structures.Webhook, error)
+	EnqueueDeliveries(ctx context.Context, m structures.OutboxMessage, webhookIDs []uuid.UUID) (int, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]structures.PendingDelivery, error)
+	MarkDeliveryDelivered(ctx context.Context, id int64, statusCode int) error
+	MarkDeliveryFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error
+}
+
+// WebhookPoster sends one signed payload and reports the HTTP status code
+// received. err is only set when no response was received at all.
+type WebhookPoster interface {
+	Post(ctx context.Context, url, secret string, deliveryID int64, body []byte) (int, error)
+}
+
+type webhookService struct {
+	store WebhookStore
+}
+
+func NewWebhookService(store WebhookStore) WebhookService {
+	return &webhookService{store: store}
+}
+
+// CreateWebhook generates a signing secret when the caller did not pick one.
+// The webhook belongs to the actor in ctx.
+func (s *webhookService) CreateWebhook(ctx context.Context, w *structures.Webhook) (*structures.Webhook, error) {
+	w.Owner = structures.ActorFromContext(ctx)
+	if w.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		w.Secret = hex.EncodeToString(b)
+	}
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+	return s.store.CreateWebhook(ctx, w)
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]structures.Webhook, error) {
+	return s.store.ListWebhooks(ctx)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, id uuid.UUID) (*structures.Webhook, error) {
+	return s.store.GetWebhook(ctx, id)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return s.store.DeleteWebhook(ctx, id)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit int) ([]structures.WebhookDelivery, error) {
+	return s.store.ListDeliveries(ctx, webhookID, status, limit)
+}
+
+// WebhookDispatcher is the outbox Sink for webhooks: it only queues a
+// delivery per subscriber, so one slow subscriber never holds back the
+// outbox or the other subscribers. Like the change streams, a subscriber
+// only gets changes of calendars its owner can read.
+type WebhookDispatcher struct {
+	store  WebhookStore
+	access EventAccess
+}
+
+func NewWebhookDispatcher(store WebhookStore, access EventAccess) *WebhookDispatcher {
+	return &WebhookDispatcher{store: store, access: access}
+}
+
+func (d *WebhookDispatcher) Publish(ctx context.Context, m structures.OutboxMessage) error {
+	subscribers, err := d.store.WebhookSubscribers(ctx, m.Type)
+	if err != nil || len(subscribers) == 0 {
+		return err
+	}
+
+	allowed := make(map[string]bool)
+	var ids []uuid.UUID
+	for _, w := range subscribers {
+		ok, checked := allowed[w.Owner]
+		if !checked {
+			ok, err = NewReadableChanges(structures.WithActor(ctx, w.Owner), d.access).Allows(m)
+			if err != nil {
+				return err
+			}
+			allowed[w.Owner] = ok
+		}
+		if ok {
+			ids = append(ids, w.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err = d.store.EnqueueDeliveries(ctx, m, ids)
+	return err
+}
+
+const (
+	webhookBatchSize = 50
+	// webhookLease reserves a claimed delivery for one attempt. Each Post
+	// is cut off after webhookPostTimeout, well inside the lease, so a slow
+	// receiver cannot make another worker send the same delivery again.
+	webhookLease       = time.Minute
+	webhookPostTimeout = webhookLease / 2
+	maxWebhookAttempts = 10
+)
+
+// WebhookDeliverer sends queued deliveries, retrying failures with
+// exponential backoff until maxWebhookAttempts is reached.
+type WebhookDeliverer struct {
+	store    WebhookStore
+	poster   WebhookPoster
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewWebhookDeliverer(store WebhookStore, poster WebhookPoster, interval time.Duration) *WebhookDeliverer {
+	return &WebhookDeliverer{store: store, poster: poster, interval: interval, now: time.Now}
+}
+
+// Run delivers webhooks every interval until ctx is cancelled.
+func (d *WebhookDeliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("webhook deliverer: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce sends up to webhookBatchSize due deliveries and returns how
+// many succeeded. Deliveries are claimed one at a time, so each lease only
+// has to cover its own attempt.
+func (d *WebhookDeliverer) DeliverOnce(ctx context.Context) (int, error) {
+	delivered := 0
+	for range webhookBatchSize {
+		pending, err := d.store.ClaimDeliveries(ctx, 1, webhookLease)
+		if err != nil || len(pending) == 0 {
+			return delivered, err
+		}
+		ok, err := d.deliver(ctx, pending[0])
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver makes one attempt at p and records the outcome.
+func (d *WebhookDeliverer) deliver(ctx context.Context, p structures.PendingDelivery) (bool, error) {
+	body, err := json.Marshal(structures.WebhookPayload{
+		ID:        p.Message.ID,
+		Type:      p.Message.Type,
+		EventID:   p.Message.EventID,
+		CreatedAt: p.Message.CreatedAt,
+		Data:      p.Message.Payload,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	postCtx, cancel := context.WithTimeout(ctx, webhookPostTimeout)
+	status, err := d.poster.Post(postCtx, p.URL, p.Secret, p.ID, body)
+	cancel()
+	if err == nil && status >= 200 && status <= 299 {
+		return true, d.store.MarkDeliveryDelivered(ctx, p.ID, status)
+	}
+
+	var statusCode *int
+	cause := ""
+	if err != nil {
+		cause = err.Error()
+	} else {
+		statusCode = &status
+		cause = fmt.Sprintf("unexpected response status %d", status)
+	}
+	var retryAt *time.Time
+	if p.Attempts+1 < maxWebhookAttempts {
+		at := d.now().Add(retryBackoff(p.Attempts))
+		retryAt = &at
+	}
+	return false, d.store.MarkDeliveryFailed(ctx, p.ID, statusCode, cause, retryAt)
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"events/clients"
+	"events/structures"
+
+	"github.com/google/uuid"
+)
+
+type mockWebhookStore struct {
+	WebhookService
+
+	created     *structures.Webhook
+	subscribers []structures.Webhook
+	enqueued    []uuid.UUID
+	pending     []structures.PendingDelivery
+	delivered   map[int64]int
+	failed      map[int64]*time.Time
+	claims      []int
+}
+
+func (m *mockWebhookStore) CreateWebhook(ctx context.Context, w *structures.Webhook) (*structures.Webhook, error) {
+	m.created = w
+	return w, nil
+}
+
+func (m *mockWebhookStore) WebhookSubscribers(ctx context.Context, messageType string) ([]structures.Webhook, error) {
+	return m.subscribers, nil
+}
+
+func (m *mockWebhookStore) EnqueueDeliveries(ctx context.Context, msg structures.OutboxMessage, webhookIDs []uuid.UUID) (int, error) {
+	m.enqueued = append(m.enqueued, webhookIDs...)
+	return len(webhookIDs), nil
+}
+
+func (m *mockWebhookStore) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]structures.PendingDelivery, error) {
+	m.claims = append(m.claims, limit)
+	n := min(limit, len(m.pending))
+	claimed := m.pending[:n]
+	m.pending = m.pending[n:]
+	return claimed, nil
+}
+
+func (m *mockWebhookStore) MarkDeliveryDelivered(ctx context.Context, id int64, statusCode int) error {
+	if m.delivered == nil {
+		m.delivered = make(map[int64]int)
+	}
+	m.delivered[id] = statusCode
+	return nil
+}
+
+func (m *mockWebhookStore) MarkDeliveryFailed(ctx context.Context, id int64, statusCode *int, cause string, retryAt *time.Time) error {
+	if m.failed == nil {
+		m.failed = make(map[int64]*time.Time)
+	}
+	m.failed[id] = retryAt
+	return nil
+}
+
+func TestWebhookService_CreateWebhook_GeneratesSecret(t *testing.T) {
+	store := &mockWebhookStore{}
+	svc := NewWebhookService(store)
+
+	w, err := svc.CreateWebhook(context.Background(), &structures.Webhook{ID: uuid.New(), URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("CreateWebhook returned error: %v", err)
+	}
+	if len(w.Secret) != 64 {
+		t.Fatalf("expected a generated 64 character secret, got %q", w.Secret)
+	}
+	if w.EventTypes == nil {
+		t.Fatalf("expected event types to default to an empty list")
+	}
+}
+
+func TestWebhookService_CreateWebhook_OwnedByCaller(t *testing.T) {
+	store := &mockWebhookStore{}
+	svc := NewWebhookService(store)
+
+	ctx := structures.WithActor(context.Background(), "token:ana")
+	w, err := svc.CreateWebhook(ctx, &structures.Webhook{ID: uuid.New(), URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("CreateWebhook returned error: %v", err)
+	}
+	if w.Owner != "token:ana" {
+		t.Fatalf("expected the webhook to belong to the caller, got %q", w.Owner)
+	}
+}
+
+// actorCalendars lets each actor read the calendars listed for it.
+type actorCalendars struct {
+	CalendarService
+
+	readable map[string][]uuid.UUID
+}
+
+func (a actorCalendars) ListCalendars(ctx context.Context) ([]structures.Calendar, error) {
+	out := make([]structures.Calendar, 0)
+	for _, id := range a.readable[structures.ActorFromContext(ctx)] {
+		out = append(out, structures.Calendar{ID: id, Permission: structures.PermissionRead})
+	}
+	return out, nil
+}
+
+func TestWebhookDispatcher_OnlyQueuesReadableChanges(t *testing.T) {
+	shared, private := uuid.New(), uuid.New()
+	ana, bob := uuid.New(), uuid.New()
+	store := &mockWebhookStore{subscribers: []structures.Webhook{
+		{ID: ana, Owner: "token:ana"},
+		{ID: bob, Owner: "token:bob"},
+	}}
+	access := NewEventAccess(fakeEventCalendars{}, actorCalendars{readable: map[string][]uuid.UUID{
+		"token:ana": {shared, private},
+		"token:bob": {shared},
+	}})
+	d := NewWebhookDispatcher(store, access)
+
+	change := func(calendarID uuid.UUID) structures.OutboxMessage {
+		payload, _ := json.Marshal(structures.Event{ID: uuid.New(), CalendarID: calendarID})
+		return structures.OutboxMessage{ID: 1, EventID: uuid.New(), Type: structures.EventUpdated, Payload: payload}
+	}
+	if err := d.Publish(context.Background(), change(shared)); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if len(store.enqueued) != 2 {
+		t.Fatalf("expected a shared change to reach both webhooks, got %v", store.enqueued)
+	}
+
+	store.enqueued = nil
+	if err := d.Publish(context.Background(), change(private)); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if len(store.enqueued) != 1 || store.enqueued[0] != ana {
+		t.Fatalf("expected a private change to reach only its owner's webhook, got %v", store.enqueued)
+	}
+}
+
+func TestWebhookDeliverer_DeliversToReceiver(t *testing.T) {
+	const secret = "0123456789abcdef"
+	now := time.Now()
+
+	var got structures.WebhookPayload
+	var verifyErr error
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = clients.VerifyWebhook(secret, r.Header.Get(clients.WebhookSignatureHeader), body, now, time.Minute)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	eventID := uuid.New()
+	msg := structures.OutboxMessage{ID: 5, EventID: eventID, Type: structures.EventUpdated, Payload: json.RawMessage(`{"title":"x"}`)}
+	store := &mockWebhookStore{pending: []structures.PendingDelivery{
+		{ID: 1, URL: receiver.URL, Secret: secret, Message: msg},
+		{ID: 2, URL: failing.URL, Secret: secret, Attempts: 2, Message: msg},
+		{ID: 3, URL: failing.URL, Secret: secret, Attempts: maxWebhookAttempts - 1, Message: msg},
+	}}
+
+	d := NewWebhookDeliverer(store, clients.NewWebhookClient(time.Second), time.Second)
+	d.now = func() time.Time { return now }
+
+	n, err := d.DeliverOnce(context.Background())
+	if err != nil {
+		t.Fatalf("DeliverOnce returned error: %v", err)
+	}
+	if n != 1 || store.delivered[1] != http.StatusOK {
+		t.Fatalf("expected delivery 1 to succeed, got n=%d delivered=%v", n, store.delivered)
+	}
+	if verifyErr != nil {
+		t.Fatalf("receiver could not verify signature: %v", verifyErr)
+	}
+	if got.ID != 5 || got.Type != structures.EventUpdated || got.EventID != eventID {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+	if retryAt := store.failed[2]; retryAt == nil || !retryAt.Equal(now.Add(4*time.Second)) {
+		t.Fatalf("expected delivery 2 to be retried in 4s, got %v", retryAt)
+	}
+	if retryAt, ok := store.failed[3]; !ok || retryAt != nil {
+		t.Fatalf("expected delivery 3 to be given up, got %v", retryAt)
+	}
+}
+
+// slowPoster answers after the context it is given runs out.
+type slowPoster struct {
+	timeouts []time.Duration
+}
+
+func (p *slowPoster) Post(ctx context.Context, url, secret string, deliveryID int64, body []byte) (int, error) {
+	deadline, _ := ctx.Deadline()
+	p.timeouts = append(p.timeouts, time.Until(deadline))
+	return 0, context.DeadlineExceeded
+}
+
+func TestWebhookDeliverer_LeasesEachDeliveryForItsOwnAttempt(t *testing.T) {
+	store := &mockWebhookStore{}
+	for id := range int64(webhookBatchSize + 5) {
+		store.pending = append(store.pending, structures.PendingDelivery{ID: id, URL: "https://example.com/hook"})
+	}
+	poster := &slowPoster{}
+	d := NewWebhookDeliverer(store, poster, time.Second)
+
+	if _, err := d.DeliverOnce(context.Background()); err != nil {
+		t.Fatalf("DeliverOnce returned error: %v", err)
+	}
+	if len(store.claims) != webhookBatchSize || store.claims[0] != 1 {
+		t.Fatalf("expected %d claims of one delivery, got %v", webhookBatchSize, store.claims)
+	}
+	if len(store.failed) != webhookBatchSize || len(store.pending) != 5 {
+		t.Fatalf("expected one batch to be attempted, got %d failed and %d left", len(store.failed), len(store.pending))
+	}
+	for _, timeout := range poster.timeouts {
+		if timeout <= 0 || timeout > webhookPostTimeout || webhookPostTimeout >= webhookLease {
+			t.Fatalf("expected every post to end inside its lease, got a %v timeout", timeout)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package structures
+
+import (
+	"mime"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxAttachmentSize bounds one uploaded file.
+const MaxAttachmentSize = 25 << 20
+
+// AttachmentTypes are the media types an attachment may have: documents,
+// slides, spreadsheets and images.
+var AttachmentTypes = []string{
+	"application/pdf",
+	"text/plain",
+	"text/markdown",
+	"text/csv",
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.oasis.opendocument.text",
+	"application/vnd.oasis.opendocument.presentation",
+	"application/vnd.oasis.opendocument.spreadsheet",
+}
+
+// AllowedAttachmentType reports whether contentType, parameters aside, is
+// one of AttachmentTypes.
+func AllowedAttachmentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && slices.Contains(AttachmentTypes, mediaType)
+}
+
+// Attachment describes a file attached to an event. The content lives in
+// a blob store under BlobKey; SHA256 is the hex digest of that content.
+type Attachment struct {
+	ID          uuid.UUID `json:"id"`
+	EventID     uuid.UUID `json:"event_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	BlobKey     string    `json:"-"`
+}
@@ -0,0 +1,69 @@
+package structures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleOrganizer = "organizer"
+	RoleRequired  = "required"
+	RoleOptional  = "optional"
+)
+
+const (
+	RSVPPending   = "pending"
+	RSVPAccepted  = "accepted"
+	RSVPDeclined  = "declined"
+	RSVPTentative = "tentative"
+)
+
+type Attendee struct {
+	EventID     uuid.UUID `json:"event_id"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Role        string    `json:"role"`
+	RSVPStatus  string    `json:"rsvp_status"`
+	Waitlisted  bool      `json:"waitlisted"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type AttendeeCounts struct {
+	Total     int `json:"total"`
+	Accepted  int `json:"accepted"`
+	Declined  int `json:"declined"`
+	Tentative int `json:"tentative"`
+	Pending   int `json:"pending"`
+
+	// Waitlisted attendees are included in Total and the RSVP counts above;
+	// seats taken is Total minus Declined minus Waitlisted.
+	Waitlisted int `json:"waitlisted"`
+}
+
+type AddAttendeeRequest struct {
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name,omitempty"`
+	Role        string `json:"role,omitempty"`
+}
+
+type UpdateRSVPRequest struct {
+	Status string `json:"status"`
+}
+
+func ValidRole(role string) bool {
+	switch role {
+	case RoleOrganizer, RoleRequired, RoleOptional:
+		return true
+	}
+	return false
+}
+
+func ValidRSVPStatus(status string) bool {
+	switch status {
+	case RSVPPending, RSVPAccepted, RSVPDeclined, RSVPTentative:
+		return true
+	}
+	return false
+}
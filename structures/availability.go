@@ -0,0 +1,27 @@
+package structures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type AvailabilityQuery struct {
+	ResourceIDs []uuid.UUID
+	Attendees   []string
+	From        time.Time
+	To          time.Time
+	Duration    time.Duration
+}
+
+type Availability struct {
+	From      time.Time  `json:"from"`
+	To        time.Time  `json:"to"`
+	Busy      []Interval `json:"busy"`
+	FreeSlots []Interval `json:"free_slots"`
+}
@@ -0,0 +1,79 @@
+package structures
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultCalendarID is the calendar created by the migration. Events made
+// through POST /events go there, and every caller may read and write it.
+var DefaultCalendarID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Permission levels on a calendar, each including the ones before it. The
+// owner always has PermissionManage.
+const (
+	PermissionRead   = "read"
+	PermissionWrite  = "write"
+	PermissionManage = "manage"
+)
+
+var Permissions = []string{PermissionRead, PermissionWrite, PermissionManage}
+
+// Everyone is the share actor that grants a permission to every caller.
+const Everyone = "*"
+
+const DefaultCalendarColor = "#3b82f6"
+
+type Calendar struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	Owner     string    `json:"owner"`
+	TimeZone  string    `json:"time_zone"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Permission is what the caller may do with the calendar; empty when
+	// they have no access.
+	Permission string `json:"permission,omitempty"`
+}
+
+// CalendarRequest creates or replaces a calendar. Events created in the
+// calendar without a time zone get TimeZone.
+type CalendarRequest struct {
+	Name     string `json:"name"`
+	Color    string `json:"color,omitempty"`
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// CalendarShare grants Actor, as recorded in the event history (for
+// example token:1a2b3c4d5e6f), or Everyone a permission on a calendar.
+type CalendarShare struct {
+	CalendarID uuid.UUID `json:"calendar_id"`
+	Actor      string    `json:"actor"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ShareCalendarRequest struct {
+	Permission string `json:"permission"`
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func ValidCalendarColor(color string) bool {
+	return colorPattern.MatchString(color)
+}
+
+func ValidPermission(p string) bool {
+	return slices.Contains(Permissions, p)
+}
+
+// PermissionAllows reports whether having permission have is enough for
+// an operation that needs want.
+func PermissionAllows(have, want string) bool {
+	h, w := slices.Index(Permissions, have), slices.Index(Permissions, want)
+	return h >= 0 && w >= 0 && h >= w
+}
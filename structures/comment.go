@@ -0,0 +1,108 @@
+package structures
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommentLength is the longest comment body, in bytes of markdown.
+const MaxCommentLength = 10000
+
+const (
+	DefaultCommentPageSize = 20
+	MaxCommentPageSize     = 100
+)
+
+// Comment is a message in the discussion of an event. Comments with a
+// ParentID are replies. Body is markdown as written; BodyHTML is its
+// sanitised rendering, filled in when the comment is read.
+//
+// A deleted comment that still has replies stays in its thread as a
+// tombstone: Deleted is set and Body, BodyHTML and EditedAt are empty.
+type Comment struct {
+	ID         uuid.UUID  `json:"id"`
+	EventID    uuid.UUID  `json:"event_id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	Author     string     `json:"author"`
+	Body       string     `json:"body"`
+	BodyHTML   string     `json:"body_html"`
+	ReplyCount int        `json:"reply_count"`
+	Deleted    bool       `json:"deleted,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EditedAt   *time.Time `json:"edited_at,omitempty"`
+}
+
+// CommentRevision is an earlier body of a comment, kept when the author
+// edited it at ReplacedAt.
+type CommentRevision struct {
+	ID         int64     `json:"id"`
+	CommentID  uuid.UUID `json:"comment_id"`
+	Body       string    `json:"body"`
+	BodyHTML   string    `json:"body_html"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type CreateCommentRequest struct {
+	Body     string     `json:"body"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// ValidateCommentBody checks that a body has text and fits
+// MaxCommentLength.
+func ValidateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("body is required")
+	}
+	if len(body) > MaxCommentLength {
+		return errors.New("body must be at most 10000 characters")
+	}
+	return nil
+}
+
+// CommentQuery selects a page of the comments of an event that reply to
+// ParentID, or of its top-level comments when ParentID is nil. Comments
+// are ordered oldest first.
+type CommentQuery struct {
+	EventID  uuid.UUID
+	ParentID *uuid.UUID
+	After    *CommentCursor
+	Limit    int
+}
+
+// CommentPage is one page of a CommentQuery. NextCursor is empty on the
+// last page.
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// CommentCursor is the position of a comment in creation order; a page
+// continues strictly after it.
+type CommentCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func CommentCursorOf(c Comment) CommentCursor {
+	return CommentCursor{CreatedAt: c.CreatedAt, ID: c.ID}
+}
+
+// Encode returns an opaque cursor for clients.
+func (c CommentCursor) Encode() string {
+	return encodeCursor(c.CreatedAt, c.ID)
+}
+
+func DecodeCommentCursor(s string) (*CommentCursor, error) {
+	t, id, err := decodeCursor(s)
+	if err != nil {
+		return nil, err
+	}
+	return &CommentCursor{CreatedAt: t, ID: id}, nil
+}
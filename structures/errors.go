@@ -0,0 +1,51 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrEventNotDeleted  = errors.New("event is not in the trash")
+	ErrStatusChanged    = errors.New("event status changed concurrently")
+	ErrAttendeeNotFound = errors.New("attendee not found")
+	ErrAttendeeExists   = errors.New("attendee already registered")
+	ErrResourceNotFound = errors.New("resource not found")
+	ErrResourceExists   = errors.New("resource name already in use")
+	ErrBookingNotFound  = errors.New("booking not found")
+	ErrBookingExists    = errors.New("resource already booked for this event")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrReminderNotFound = errors.New("reminder not found")
+
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrBlobNotFound is returned by blob stores for keys they do not hold.
+	ErrBlobNotFound = errors.New("blob not found")
+
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrParentCommentNotFound is returned for replies to a comment that
+	// is not in the event's discussion or has been deleted.
+	ErrParentCommentNotFound = errors.New("parent comment not found")
+	ErrNotCommentAuthor      = errors.New("only the author can change a comment")
+
+	ErrCalendarNotFound = errors.New("calendar not found")
+	ErrCalendarNotEmpty = errors.New("calendar still has events")
+	ErrShareNotFound    = errors.New("calendar is not shared with this actor")
+	// ErrForbidden is returned when the caller can see a calendar but
+	// lacks the permission the operation needs.
+	ErrForbidden = errors.New("insufficient permission on calendar")
+)
+
+// BookingConflictError reports that a resource is already booked by other
+// events during the requested time range.
+type BookingConflictError struct {
+	ResourceID uuid.UUID
+	EventIDs   []uuid.UUID
+}
+
+func (e *BookingConflictError) Error() string {
+	return fmt.Sprintf("resource %s is already booked by %d overlapping event(s)", e.ResourceID, len(e.EventIDs))
+}
@@ -1,18 +1,52 @@
 package structures
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// DateLayout is the format of the date-only start_date / end_date fields
+// used by all-day events.
+const DateLayout = "2006-01-02"
+
 type Event struct {
 	ID          uuid.UUID `json:"id"`
+	CalendarID  uuid.UUID `json:"calendar_id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description,omitempty"`
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time"`
+	TimeZone    string    `json:"time_zone"`
+	AllDay      bool      `json:"all_day"`
+	StartDate   string    `json:"start_date,omitempty"`
+	EndDate     string    `json:"end_date,omitempty"`
+	Capacity    *int      `json:"capacity,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	// Status is one of EventStatuses; StatusReason explains the last
+	// transition.
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason,omitempty"`
+
+	// Tags are normalized by NormalizeTags; Metadata is free-form and
+	// checked by ValidateMetadata.
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+
+	Location *Location `json:"location,omitempty"`
+	// Language is the text search configuration the title and description
+	// are indexed with, one of SearchLanguages.
+	Language string `json:"language"`
+	// DistanceKm is only set in the results of a near search.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+
+	// DeletedAt is set while the event is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	Attendees AttendeeCounts `json:"attendees"`
 }
 
 type CreateEventRequest struct {
@@ -20,4 +54,85 @@ type CreateEventRequest struct {
 	Description string    `json:"description"`
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time"`
+	TimeZone    string    `json:"time_zone,omitempty"`
+	AllDay      bool      `json:"all_day,omitempty"`
+	StartDate   string    `json:"start_date,omitempty"`
+	EndDate     string    `json:"end_date,omitempty"`
+	Capacity    *int      `json:"capacity,omitempty"`
+
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Location *Location      `json:"location,omitempty"`
+	// Language defaults to DefaultSearchLanguage.
+	Language string `json:"language,omitempty"`
+
+	// Status is only read on create, where it may be draft or scheduled
+	// (the default). Later changes go through the transition endpoints.
+	Status string `json:"status,omitempty"`
+	// CalendarID is only read on create; the default calendar when unset.
+	// Events do not move between calendars on replace.
+	CalendarID uuid.UUID `json:"calendar_id,omitempty"`
+}
+
+// In returns a copy of the event with its instants rendered in loc. A nil
+// loc renders them in the event's own time zone. All-day events also get
+// their date-only StartDate / EndDate (inclusive), which are always taken
+// from the event's own zone so they do not shift with the viewer.
+func (e Event) In(loc *time.Location) Event {
+	own, err := LoadTimeZone(e.TimeZone)
+	if err != nil {
+		own = time.UTC
+	}
+	if loc == nil {
+		loc = own
+	}
+	if e.AllDay {
+		e.StartDate = e.StartTime.In(own).Format(DateLayout)
+		e.EndDate = e.EndTime.In(own).AddDate(0, 0, -1).Format(DateLayout)
+	}
+	e.StartTime = e.StartTime.In(loc)
+	e.EndTime = e.EndTime.In(loc)
+	e.CreatedAt = e.CreatedAt.In(loc)
+	if e.DeletedAt != nil {
+		deleted := e.DeletedAt.In(loc)
+		e.DeletedAt = &deleted
+	}
+	return e
+}
+
+// AllDayBounds converts inclusive start/end dates into the instants an
+// all-day event occupies in loc: midnight of the first day up to midnight
+// after the last one.
+func AllDayBounds(startDate, endDate string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(DateLayout, startDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.ParseInLocation(DateLayout, endDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// timeZones caches the zones LoadTimeZone has resolved, since
+// time.LoadLocation reads the zone database on every call. Only valid
+// names are kept, so the cache cannot grow past the IANA zone list.
+var timeZones sync.Map
+
+// LoadTimeZone resolves an IANA zone name. Unlike time.LoadLocation it
+// rejects "Local", whose meaning depends on the server.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, errors.New("unknown time zone Local")
+	}
+	if loc, ok := timeZones.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	timeZones.Store(name, loc)
+	return loc, nil
 }
@@ -0,0 +1,99 @@
+package structures
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operations recorded in an event's history.
+const (
+	HistoryCreate  = "create"
+	HistoryUpdate  = "update"
+	HistoryDelete  = "delete"
+	HistoryRestore = "restore"
+	HistoryPurge   = "purge"
+)
+
+// SystemActor is recorded for changes made without a caller in the
+// context, such as background jobs.
+const SystemActor = "system"
+
+// EventHistoryEntry is one mutation of an event. Diff maps the JSON name of
+// every field that changed to its value before and after; on create every
+// before is null, on purge every after is. Deleting and restoring only
+// change deleted_at.
+type EventHistoryEntry struct {
+	ID        int64                  `json:"id"`
+	EventID   uuid.UUID              `json:"event_id"`
+	Operation string                 `json:"operation"`
+	Actor     string                 `json:"actor"`
+	ChangedAt time.Time              `json:"changed_at"`
+	Diff      map[string]FieldChange `json:"diff"`
+}
+
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// DiffEvents compares two versions of an event field by field, as they are
+// encoded in JSON. Either may be nil. Attendee counts are left out: they
+// change through RSVPs, which are not event mutations.
+func DiffEvents(before, after *Event) (map[string]FieldChange, error) {
+	b, err := eventFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := eventFields(after)
+	if err != nil {
+		return nil, err
+	}
+	diff := make(map[string]FieldChange)
+	for name, v := range b {
+		if w, ok := a[name]; !ok || !reflect.DeepEqual(v, w) {
+			diff[name] = FieldChange{Before: v, After: a[name]}
+		}
+	}
+	for name, w := range a {
+		if _, ok := b[name]; !ok {
+			diff[name] = FieldChange{After: w}
+		}
+	}
+	return diff, nil
+}
+
+func eventFields(e *Event) (map[string]any, error) {
+	fields := make(map[string]any)
+	if e == nil {
+		return fields, nil
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "attendees")
+	return fields, nil
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes the changes made with it to
+// actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or SystemActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
@@ -0,0 +1,94 @@
+package structures
+
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadiusKm is the mean radius used for haversine distances.
+const EarthRadiusKm = 6371.0088
+
+// MaxSearchRadiusKm is half the Earth's circumference; every point is
+// within it.
+const MaxSearchRadiusKm = 20016
+
+// Location is where an event takes place. Any of the fields may be given,
+// but the coordinates only together; events without them never match a
+// near search.
+type Location struct {
+	Name      string   `json:"name,omitempty"`
+	Address   string   `json:"address,omitempty"`
+	Latitude  *float64 `json:"lat,omitempty"`
+	Longitude *float64 `json:"lng,omitempty"`
+}
+
+// Validate checks the lengths of the text fields and the range of the
+// coordinates.
+func (l *Location) Validate() error {
+	if l.Name == "" && l.Address == "" && l.Latitude == nil && l.Longitude == nil {
+		return errors.New("location needs a name, an address or coordinates")
+	}
+	if len(l.Name) > 200 {
+		return errors.New("location.name must be at most 200 characters")
+	}
+	if len(l.Address) > 500 {
+		return errors.New("location.address must be at most 500 characters")
+	}
+	if (l.Latitude == nil) != (l.Longitude == nil) {
+		return errors.New("location.lat and location.lng must be given together")
+	}
+	if l.Latitude != nil && !(GeoPoint{Lat: *l.Latitude, Lng: *l.Longitude}).Valid() {
+		return errors.New("location.lat must be between -90 and 90 and location.lng between -180 and 180")
+	}
+	return nil
+}
+
+// Point returns the coordinates of the location, if it has them.
+func (l *Location) Point() (GeoPoint, bool) {
+	if l == nil || l.Latitude == nil || l.Longitude == nil {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Lat: *l.Latitude, Lng: *l.Longitude}, true
+}
+
+// GeoPoint is a position in decimal degrees.
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+func (p GeoPoint) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// DistanceKm is the great-circle distance to q by the haversine formula.
+func (p GeoPoint) DistanceKm(q GeoPoint) float64 {
+	dLat := radians(q.Lat - p.Lat)
+	dLng := radians(q.Lng - p.Lng)
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(p.Lat))*math.Cos(radians(q.Lat))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox returns latitude and longitude ranges that hold every point
+// within radiusKm of p, so an index can discard far away rows before the
+// exact distance is computed. When the circle reaches a pole or crosses
+// the antimeridian the longitude range is the whole globe.
+func (p GeoPoint) BoundingBox(radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	angular := radiusKm / EarthRadiusKm
+	dLat := degrees(angular)
+	minLat, maxLat = p.Lat-dLat, p.Lat+dLat
+	minLng, maxLng = -180, 180
+	if minLat <= -90 || maxLat >= 90 {
+		return max(minLat, -90), min(maxLat, 90), minLng, maxLng
+	}
+	dLng := degrees(math.Asin(math.Sin(angular) / math.Cos(radians(p.Lat))))
+	if p.Lng-dLng >= -180 && p.Lng+dLng <= 180 {
+		minLng, maxLng = p.Lng-dLng, p.Lng+dLng
+	}
+	return minLat, maxLat, minLng, maxLng
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
@@ -0,0 +1,21 @@
+package structures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventSchemaVersion is the version of the EventMessage layout. Bump it on
+// any incompatible change so consumers can tell payloads apart.
+const EventSchemaVersion = 1
+
+// EventMessage is what the outbox relay hands to a Publisher for each
+// event change. Event is nil for deletions.
+type EventMessage struct {
+	SchemaVersion int       `json:"schema_version"`
+	Type          string    `json:"type"`
+	EventID       uuid.UUID `json:"event_id"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Event         *Event    `json:"event,omitempty"`
+}
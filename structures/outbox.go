@@ -0,0 +1,35 @@
+package structures
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox message types, one per mutation that downstream systems can
+// subscribe to.
+const (
+	EventCreated        = "event.created"
+	EventUpdated        = "event.updated"
+	EventDeleted        = "event.deleted"
+	AttendeeAdded       = "attendee.added"
+	AttendeeRemoved     = "attendee.removed"
+	AttendeeRSVPUpdated = "attendee.rsvp_updated"
+	AttendeePromoted    = "attendee.promoted"
+	ResourceBooked      = "resource.booked"
+	ResourceReleased    = "resource.released"
+)
+
+// OutboxMessage is a change notification written in the same transaction as
+// the mutation it describes. EventID is the event the change belongs to and
+// Payload the JSON of the affected entity after the change (or before it,
+// for deletions).
+type OutboxMessage struct {
+	ID        int64           `json:"id"`
+	EventID   uuid.UUID       `json:"event_id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"-"`
+}
@@ -0,0 +1,104 @@
+package structures
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultEventPageSize = 20
+	MaxEventPageSize     = 100
+)
+
+// EventQuery selects a page of events ordered by start time. From / To
+// keep events overlapping [From, To); either may be nil.
+type EventQuery struct {
+	From          *time.Time
+	To            *time.Time
+	TitleContains string
+	After         *EventCursor
+	Limit         int
+
+	// IncludeDeleted also returns events in the trash.
+	IncludeDeleted bool
+	// Statuses keeps events in any of these statuses; empty keeps all.
+	Statuses []string
+	// Near keeps events whose location is within RadiusKm of it, and
+	// sets their DistanceKm.
+	Near     *GeoPoint
+	RadiusKm float64
+	// CalendarID keeps the events of one calendar.
+	CalendarID *uuid.UUID
+	// CalendarIDs keeps the events of these calendars; nil keeps all. The
+	// event service sets it to the calendars the caller can read.
+	CalendarIDs []uuid.UUID
+	// Tags keeps events that have all of these tags.
+	Tags []string
+	// Metadata keeps events whose metadata has these string values, keyed
+	// by dotted path (see MetadataContainment).
+	Metadata map[string]string
+}
+
+// EventPage is one page of an EventQuery. NextCursor is empty on the last
+// page.
+type EventPage struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// EventCursor is the position of an event in start time order; a page
+// continues strictly after it.
+type EventCursor struct {
+	StartTime time.Time
+	ID        uuid.UUID
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func CursorOf(e Event) EventCursor {
+	return EventCursor{StartTime: e.StartTime, ID: e.ID}
+}
+
+// Encode returns an opaque cursor for clients.
+func (c EventCursor) Encode() string {
+	return encodeCursor(c.StartTime, c.ID)
+}
+
+func DecodeEventCursor(s string) (*EventCursor, error) {
+	t, id, err := decodeCursor(s)
+	if err != nil {
+		return nil, err
+	}
+	return &EventCursor{StartTime: t, ID: id}, nil
+}
+
+// encodeCursor and decodeCursor give a position in a (time, id) ordering
+// an opaque form.
+func encodeCursor(t time.Time, id uuid.UUID) string {
+	raw := t.UTC().Format(time.RFC3339Nano) + "," + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	ts, rawID, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	return t, id, nil
+}
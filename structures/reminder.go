@@ -0,0 +1,60 @@
+package structures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reminder channels.
+const (
+	ReminderEmail   = "email"
+	ReminderWebhook = "webhook"
+	ReminderLog     = "log"
+)
+
+// Reminder states.
+const (
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	ReminderFailed  = "failed"
+)
+
+// MaxReminderMinutes is how far ahead of an event a reminder can fire.
+const MaxReminderMinutes = 4 * 7 * 24 * 60
+
+// Reminder is a rule to notify Target through Channel MinutesBefore the
+// event starts. RemindAt follows the event when it is rescheduled.
+type Reminder struct {
+	ID            uuid.UUID  `json:"id"`
+	EventID       uuid.UUID  `json:"event_id"`
+	MinutesBefore int        `json:"minutes_before"`
+	Channel       string     `json:"channel"`
+	Target        string     `json:"target,omitempty"`
+	RemindAt      time.Time  `json:"remind_at"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type CreateReminderRequest struct {
+	MinutesBefore int    `json:"minutes_before"`
+	Channel       string `json:"channel"`
+	Target        string `json:"target"`
+}
+
+func ValidReminderChannel(c string) bool {
+	switch c {
+	case ReminderEmail, ReminderWebhook, ReminderLog:
+		return true
+	}
+	return false
+}
+
+// DueReminder is a claimed reminder together with the event it is about.
+type DueReminder struct {
+	Reminder Reminder
+	Event    Event
+}
@@ -0,0 +1,123 @@
+package structures
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ResourceRoom      = "room"
+	ResourceEquipment = "equipment"
+)
+
+type Resource struct {
+	ID           uuid.UUID     `json:"id"`
+	Name         string        `json:"name"`
+	Kind         string        `json:"kind"`
+	WorkingHours *WorkingHours `json:"working_hours,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// WorkingHours limits when a resource can be scheduled. Start and End are
+// wall-clock "HH:MM" times in TimeZone; Days uses time.Weekday numbering
+// (0 = Sunday) and defaults to Monday to Friday when empty.
+type WorkingHours struct {
+	Start    string         `json:"start"`
+	End      string         `json:"end"`
+	Days     []time.Weekday `json:"days,omitempty"`
+	TimeZone string         `json:"time_zone,omitempty"`
+}
+
+type CreateResourceRequest struct {
+	Name         string        `json:"name"`
+	Kind         string        `json:"kind"`
+	WorkingHours *WorkingHours `json:"working_hours,omitempty"`
+}
+
+type Booking struct {
+	EventID    uuid.UUID `json:"event_id"`
+	ResourceID uuid.UUID `json:"resource_id"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type BookResourceRequest struct {
+	ResourceID uuid.UUID `json:"resource_id"`
+}
+
+func ValidResourceKind(kind string) bool {
+	return kind == ResourceRoom || kind == ResourceEquipment
+}
+
+// Validate checks the clock times, weekdays and time zone of the schedule.
+func (h *WorkingHours) Validate() error {
+	start, err := time.Parse("15:04", h.Start)
+	if err != nil {
+		return errors.New("working_hours.start must be HH:MM")
+	}
+	end, err := time.Parse("15:04", h.End)
+	if err != nil {
+		return errors.New("working_hours.end must be HH:MM")
+	}
+	if !start.Before(end) {
+		return errors.New("working_hours.start must be before working_hours.end")
+	}
+	for _, d := range h.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return errors.New("working_hours.days must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+	if _, err := LoadTimeZone(h.TimeZone); err != nil {
+		return errors.New("working_hours.time_zone must be an IANA time zone")
+	}
+	return nil
+}
+
+// Windows returns the working windows that overlap [from, to), clipped to it.
+func (h *WorkingHours) Windows(from, to time.Time) []Interval {
+	loc, err := LoadTimeZone(h.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, _ := time.Parse("15:04", h.Start)
+	end, _ := time.Parse("15:04", h.End)
+	days := h.Days
+	if len(days) == 0 {
+		days = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+
+	var windows []Interval
+	local := from.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !slices.Contains(days, day.Weekday()) {
+			continue
+		}
+		w := Interval{
+			Start: time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc),
+			End:   time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc),
+		}
+		if w.Start.Before(from) {
+			w.Start = from
+		}
+		if w.End.After(to) {
+			w.End = to
+		}
+		if w.Start.Before(w.End) {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// BookingConflictResponse is the 409 body returned when a booking overlaps
+// existing ones.
+type BookingConflictResponse struct {
+	Error               string      `json:"error"`
+	ResourceID          uuid.UUID   `json:"resource_id"`
+	ConflictingEventIDs []uuid.UUID `json:"conflicting_event_ids"`
+}
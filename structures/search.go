@@ -0,0 +1,110 @@
+package structures
+
+import (
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSearchLanguage is the text search configuration of events that do
+// not name one.
+const DefaultSearchLanguage = "english"
+
+// SearchLanguages are the text search configurations Postgres ships with.
+// An event's title and description are stemmed and stripped of stop words
+// by the rules of its language; simple does neither.
+var SearchLanguages = []string{
+	"simple", "arabic", "danish", "dutch", "english", "finnish", "french",
+	"german", "greek", "hungarian", "indonesian", "irish", "italian",
+	"lithuanian", "nepali", "norwegian", "portuguese", "romanian",
+	"russian", "spanish", "swedish", "tamil", "turkish",
+}
+
+func ValidSearchLanguage(lang string) bool {
+	return slices.Contains(SearchLanguages, lang)
+}
+
+// MaxSearchTextLength bounds the q parameter of a search.
+const MaxSearchTextLength = 256
+
+// HighlightStart and HighlightStop are asked of the store to mark matches
+// in headlines, so the service can escape a headline and only then turn
+// them into markup. They are control characters the store strips from the
+// text it highlights, so every one in a headline is a real mark.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// SearchQuery selects a page of the events, outside the trash, whose title
+// or description match Text, best match first. Text is read like a web
+// search: words, "quoted phrases", or and -excluded. With Language only
+// events in that language are searched; without it each event is matched
+// in its own.
+type SearchQuery struct {
+	Text     string
+	Language string
+	After    *SearchCursor
+	Limit    int
+	// CalendarIDs keeps the events of these calendars, like
+	// EventQuery.CalendarIDs.
+	CalendarIDs []uuid.UUID
+}
+
+// SearchResult is an event that matched a search, with the title and an
+// excerpt of the description where the matches are wrapped in <mark>.
+// The headlines are HTML: everything else in them is escaped.
+type SearchResult struct {
+	Event               Event   `json:"event"`
+	Rank                float32 `json:"rank"`
+	TitleHeadline       string  `json:"title_headline"`
+	DescriptionHeadline string  `json:"description_headline,omitempty"`
+}
+
+// SearchPage is one page of a SearchQuery. NextCursor is empty on the last
+// page.
+type SearchPage struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// SearchCursor is the position of a result in rank order; a page continues
+// strictly after it.
+type SearchCursor struct {
+	Rank float32
+	ID   uuid.UUID
+}
+
+func SearchCursorOf(r SearchResult) SearchCursor {
+	return SearchCursor{Rank: r.Rank, ID: r.Event.ID}
+}
+
+// Encode returns an opaque cursor for clients. The rank is written with
+// every digit so the next page starts exactly after it.
+func (c SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(float64(c.Rank), 'g', -1, 32) + "," + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeSearchCursor(s string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	rank, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	r, err := strconv.ParseFloat(rank, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := SearchCursor{Rank: float32(r)}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
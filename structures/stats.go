@@ -0,0 +1,90 @@
+package structures
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stats intervals, the width of the buckets events are counted in. Weeks
+// start on Monday.
+const (
+	StatsDay   = "day"
+	StatsWeek  = "week"
+	StatsMonth = "month"
+)
+
+var StatsIntervals = []string{StatsDay, StatsWeek, StatsMonth}
+
+func ValidStatsInterval(interval string) bool {
+	return slices.Contains(StatsIntervals, interval)
+}
+
+// Stats groupings. By tag an event counts once for each of its tags, and
+// untagged events only in the totals.
+const (
+	StatsByStatus = "status"
+	StatsByTag    = "tag"
+)
+
+var StatsGroupings = []string{StatsByStatus, StatsByTag}
+
+func ValidStatsGrouping(groupBy string) bool {
+	return slices.Contains(StatsGroupings, groupBy)
+}
+
+// MaxStatsBuckets bounds the range of a StatsQuery: at most this many days,
+// weeks or months.
+const MaxStatsBuckets = 366
+
+// MaxStatsRange is the longest range a query with interval may cover.
+func MaxStatsRange(interval string) time.Duration {
+	day := 24 * time.Hour
+	switch interval {
+	case StatsWeek:
+		return MaxStatsBuckets * 7 * day
+	case StatsMonth:
+		return MaxStatsBuckets * 31 * day
+	}
+	return MaxStatsBuckets * day
+}
+
+// StatsQuery counts the events outside the trash that start in
+// [From, To), bucketed by Interval in TimeZone and, with GroupBy, split by
+// status or tag.
+type StatsQuery struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	TimeZone string
+	GroupBy  string
+	// CalendarIDs keeps the events of these calendars, like
+	// EventQuery.CalendarIDs.
+	CalendarIDs []uuid.UUID
+}
+
+// EventStats answers a StatsQuery. Buckets are ordered by start and group;
+// those without events are left out.
+type EventStats struct {
+	Interval string        `json:"interval"`
+	TimeZone string        `json:"time_zone"`
+	GroupBy  string        `json:"group_by,omitempty"`
+	Total    StatsTotal    `json:"total"`
+	Buckets  []StatsBucket `json:"buckets"`
+}
+
+// StatsTotal counts every matching event once, whatever the grouping.
+type StatsTotal struct {
+	Events int     `json:"events"`
+	Hours  float64 `json:"hours"`
+}
+
+// StatsBucket counts the events starting in the interval at Start, or
+// those of them in Group. Hours is the sum of their durations.
+type StatsBucket struct {
+	Start  time.Time `json:"start"`
+	Group  string    `json:"group,omitempty"`
+	Events int       `json:"events"`
+	Hours  float64   `json:"hours"`
+}
@@ -0,0 +1,66 @@
+package structures
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Event lifecycle statuses. Events start as drafts or scheduled and move
+// between statuses only along EventTransitions.
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPostponed = "postponed"
+	StatusCancelled = "cancelled"
+	StatusCompleted = "completed"
+)
+
+var EventStatuses = []string{StatusDraft, StatusScheduled, StatusPostponed, StatusCancelled, StatusCompleted}
+
+// EventTransitions lists the statuses each status can move to. Completed
+// is final.
+var EventTransitions = map[string][]string{
+	StatusDraft:     {StatusScheduled, StatusCancelled},
+	StatusScheduled: {StatusPostponed, StatusCancelled, StatusCompleted},
+	StatusPostponed: {StatusScheduled, StatusCancelled},
+	StatusCancelled: {StatusScheduled},
+	StatusCompleted: {},
+}
+
+func ValidEventStatus(s string) bool {
+	return slices.Contains(EventStatuses, s)
+}
+
+func CanTransition(from, to string) bool {
+	return slices.Contains(EventTransitions[from], to)
+}
+
+// StatusChange moves an event from one status to another. From is filled
+// in by the service from the stored event; the store only applies the
+// change while the event is still in that status.
+type StatusChange struct {
+	EventID uuid.UUID
+	From    string
+	To      string
+	Reason  string
+}
+
+// StatusChangeRequest is the body of the transition endpoints.
+type StatusChangeRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// MaxStatusReason is the longest reason a transition accepts.
+const MaxStatusReason = 500
+
+// InvalidTransitionError reports a status change the lifecycle does not
+// allow.
+type InvalidTransitionError struct {
+	From, To string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change status from %s to %s", e.From, e.To)
+}
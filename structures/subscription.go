@@ -0,0 +1,47 @@
+package structures
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeFilter selects which change notifications a live subscriber gets.
+// A change matches if it belongs to one of EventIDs or if its time range
+// overlaps [From, To). A filter with neither set matches every change.
+type ChangeFilter struct {
+	EventIDs []uuid.UUID `json:"event_ids,omitempty"`
+	From     *time.Time  `json:"from,omitempty"`
+	To       *time.Time  `json:"to,omitempty"`
+}
+
+func (f *ChangeFilter) Validate() error {
+	if (f.From == nil) != (f.To == nil) {
+		return errors.New("from and to must be given together")
+	}
+	if f.From != nil && !f.To.After(*f.From) {
+		return errors.New("to must be after from")
+	}
+	if len(f.EventIDs) > 1000 {
+		return errors.New("at most 1000 event_ids can be watched")
+	}
+	return nil
+}
+
+// Matches reports whether a change to eventID matches the filter. start and
+// end are the time range of the change, or nil when it has none (for
+// example attendee changes), in which case only EventIDs can match.
+func (f *ChangeFilter) Matches(eventID uuid.UUID, start, end *time.Time) bool {
+	if len(f.EventIDs) == 0 && f.From == nil {
+		return true
+	}
+	if slices.Contains(f.EventIDs, eventID) {
+		return true
+	}
+	if f.From != nil && start != nil && end != nil {
+		return start.Before(*f.To) && end.After(*f.From)
+	}
+	return false
+}
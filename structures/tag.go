@@ -0,0 +1,123 @@
+package structures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Limits on the tags and metadata of an event.
+const (
+	MaxEventTags       = 20
+	MaxTagLength       = 50
+	MaxMetadataBytes   = 16 << 10
+	MaxMetadataDepth   = 5
+	MaxMetadataKeySize = 64
+)
+
+// Tags are lower case and may be namespaced with a colon, such as
+// team:payments or type:workshop, which is how categories are expressed.
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:/-]*$`)
+
+// NormalizeTags trims and lower-cases tags, drops duplicates and sorts
+// them, the form in which they are stored.
+func NormalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if len(t) > MaxTagLength || !tagPattern.MatchString(t) {
+			return nil, fmt.Errorf("tag %q must be 1 to %d lower case letters, digits or _ . : / -, starting with a letter or digit", t, MaxTagLength)
+		}
+		out = append(out, t)
+	}
+	slices.Sort(out)
+	out = slices.Compact(out)
+	if len(out) > MaxEventTags {
+		return nil, fmt.Errorf("an event can have at most %d tags", MaxEventTags)
+	}
+	return out, nil
+}
+
+// ValidateMetadata checks that free-form metadata stays small enough to
+// index: at most MaxMetadataBytes once encoded and MaxMetadataDepth levels
+// of nesting, counting the top-level object. Keys may not contain dots,
+// which separate the levels of a metadata filter.
+func ValidateMetadata(m map[string]any) error {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return errors.New("metadata must be a JSON object")
+	}
+	if len(raw) > MaxMetadataBytes {
+		return fmt.Errorf("metadata must be at most %d bytes", MaxMetadataBytes)
+	}
+	return validateMetadataValue(m, 1)
+}
+
+func validateMetadataValue(v any, depth int) error {
+	switch v := v.(type) {
+	case map[string]any:
+		if depth > MaxMetadataDepth {
+			return fmt.Errorf("metadata must be nested at most %d levels deep", MaxMetadataDepth)
+		}
+		for k, child := range v {
+			if k == "" || len(k) > MaxMetadataKeySize || strings.Contains(k, ".") {
+				return fmt.Errorf("metadata key %q must be 1 to %d characters without dots", k, MaxMetadataKeySize)
+			}
+			if err := validateMetadataValue(child, depth+1); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if depth > MaxMetadataDepth {
+			return fmt.Errorf("metadata must be nested at most %d levels deep", MaxMetadataDepth)
+		}
+		for _, child := range v {
+			if err := validateMetadataValue(child, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MetadataContainment turns metadata filters, keyed by dotted paths such as
+// team.lead, into the object they require the metadata to contain. Values
+// are matched as strings. Two filters where one path is a prefix of the
+// other cannot both hold and are rejected.
+func MetadataContainment(filters map[string]string) (map[string]any, error) {
+	doc := make(map[string]any)
+	paths := make([]string, 0, len(filters))
+	for path := range filters {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		obj := doc
+		for i, k := range keys {
+			if k == "" {
+				return nil, fmt.Errorf("invalid metadata filter %q", path)
+			}
+			if i == len(keys)-1 {
+				if _, taken := obj[k]; taken {
+					return nil, fmt.Errorf("metadata filters %q conflict", path)
+				}
+				obj[k] = filters[path]
+				break
+			}
+			next, ok := obj[k].(map[string]any)
+			if !ok {
+				if _, taken := obj[k]; taken {
+					return nil, fmt.Errorf("metadata filters %q conflict", path)
+				}
+				next = make(map[string]any)
+				obj[k] = next
+			}
+			obj = next
+		}
+	}
+	return doc, nil
+}
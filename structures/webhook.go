@@ -0,0 +1,80 @@
+package structures
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageTypes lists every outbox message type a webhook can subscribe to.
+var MessageTypes = []string{
+	EventCreated, EventUpdated, EventDeleted,
+	AttendeeAdded, AttendeeRemoved, AttendeeRSVPUpdated, AttendeePromoted,
+	ResourceBooked, ResourceReleased,
+}
+
+func ValidMessageType(t string) bool {
+	return slices.Contains(MessageTypes, t)
+}
+
+// Webhook is a subscriber URL. An empty EventTypes subscribes to every
+// message type. Secret is only returned when the webhook is created. Owner
+// is the actor that created it; only that actor can manage it, and it is
+// only sent changes of calendars the owner can read.
+type Webhook struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	Owner      string    `json:"owner"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+// Webhook delivery states.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one outbox message queued for one webhook, together
+// with the outcome of its latest attempt.
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	WebhookID      uuid.UUID  `json:"webhook_id"`
+	MessageID      int64      `json:"message_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PendingDelivery is a claimed delivery with everything needed to send it.
+type PendingDelivery struct {
+	ID       int64
+	URL      string
+	Secret   string
+	Attempts int
+	Message  OutboxMessage
+}
+
+// WebhookPayload is the JSON body POSTed to subscribers.
+type WebhookPayload struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	EventID   uuid.UUID       `json:"event_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"events/structures"
+)
+
+func TestTokenAuth_Check(t *testing.T) {
+	a := NewTokenAuth([]string{"secret-one", " secret-two ", ""})
+
+	cases := map[string]bool{
+		"Bearer secret-one": true,
+		"bearer secret-two": true,
+		"Bearer secret":     false,
+		"Basic secret-one":  false,
+		"secret-one":        false,
+		"":                  false,
+	}
+	for header, ok := range cases {
+		if err := a.Check(header); (err == nil) != ok {
+			t.Errorf("Check(%q) = %v, want ok=%v", header, err, ok)
+		}
+	}
+
+	if err := NewTokenAuth(nil).Check(""); err != nil {
+		t.Fatalf("expected auth to be disabled without tokens, got %v", err)
+	}
+}
+
+func TestTokenAuth_Middleware(t *testing.T) {
+	th := &testHandler{}
+	mw := NewTokenAuth([]string{"secret-one"}).Middleware(th)
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized || th.called {
+		t.Fatalf("expected 401 without calling the handler, got %d (called=%v)", w.Code, th.called)
+	}
+	if w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatal("expected a WWW-Authenticate challenge")
+	}
+
+	req.Header.Set("Authorization", "Bearer secret-one")
+	w = httptest.NewRecorder()
+	mw.ServeHTTP(w, req)
+	if !th.called {
+		t.Fatal("expected the handler to be called with a valid token")
+	}
+}
+
+func TestTokenAuth_AttributesRequestsToTheToken(t *testing.T) {
+	var actor string
+	mw := NewTokenAuth([]string{"secret-one"}).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = structures.ActorFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/events", nil)
+	req.Header.Set("Authorization", "Bearer secret-one")
+	mw.ServeHTTP(httptest.NewRecorder(), req)
+	if !strings.HasPrefix(actor, "token:") || strings.Contains(actor, "secret") {
+		t.Fatalf("expected an actor naming the token without revealing it, got %q", actor)
+	}
+
+	if actor, err := NewTokenAuth(nil).Authenticate(""); err != nil || actor != AnonymousActor {
+		t.Fatalf("expected anonymous without tokens, got %q, %v", actor, err)
+	}
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc loads many keys at once. Keys missing from the result load as
+// the zero value.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader coalesces the Load calls made within a short window into a single
+// BatchFunc call and caches the results, so resolving a field for every
+// item of a list costs one query instead of one per item. A Loader is meant
+// to live for one request.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending *loaderBatch[K, V]
+	cache   map[K]*loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys   []K
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, wait: wait, maxBatch: maxBatch, cache: make(map[K]*loaderBatch[K, V])}
+}
+
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b, ok := l.cache[key]
+	if !ok {
+		b = l.pending
+		if b == nil {
+			b = &loaderBatch[K, V]{done: make(chan struct{})}
+			l.pending = b
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+		}
+		b.keys = append(b.keys, key)
+		l.cache[key] = b
+		if len(b.keys) >= l.maxBatch {
+			go l.dispatch(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.values[key], b.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch runs b once, whichever of the timer and the size limit fires
+// first.
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	b.values, b.err = l.fetch(ctx, b.keys)
+	close(b.done)
+}
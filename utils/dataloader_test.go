@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoader_BatchesAndCaches(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]int
+	l := NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		mu.Lock()
+		batches = append(batches, keys)
+		mu.Unlock()
+		out := make(map[int]string)
+		for _, k := range keys {
+			out[k] = strconv.Itoa(k)
+		}
+		return out, nil
+	}, 5*time.Millisecond, 100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), k%5)
+			if err != nil || v != strconv.Itoa(k%5) {
+				t.Errorf("Load(%d) = %q, %v", k%5, v, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(batches) != 1 || len(batches[0]) != 5 {
+		t.Fatalf("expected one batch of 5 distinct keys, got %v", batches)
+	}
+
+	if v, _ := l.Load(context.Background(), 3); v != "3" || len(batches) != 1 {
+		t.Fatalf("expected a cached value without a new batch, got %q after %d batches", v, len(batches))
+	}
+}
+
+func TestLoader_SplitsAtMaxBatch(t *testing.T) {
+	var mu sync.Mutex
+	sizes := []int{}
+	l := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		mu.Lock()
+		sizes = append(sizes, len(keys))
+		mu.Unlock()
+		return nil, nil
+	}, time.Hour, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			l.Load(context.Background(), k)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+		t.Fatalf("expected two full batches, got %v", sizes)
+	}
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/google/uuid"
+)
+
+// ValidationMode selects what SpecValidator checks.
+type ValidationMode int
+
+const (
+	// ValidateRequests rejects requests that do not match the spec with
+	// 400 before they reach the handler.
+	ValidateRequests ValidationMode = 1 << iota
+	// ValidateResponses logs responses that do not match the spec. They
+	// are still sent as they are.
+	ValidateResponses
+)
+
+// maxValidatedRequest bounds the request bodies the validator reads into
+// memory; larger ones get 413 before they reach the handler.
+const maxValidatedRequest = 1 << 20
+
+// maxValidatedResponse is how much of a response body is kept for
+// validation; larger responses are not checked.
+const maxValidatedResponse = 1 << 20
+
+// ParseValidationMode reads a comma separated list of "requests" and
+// "responses"; "" and "off" disable validation.
+func ParseValidationMode(s string) (ValidationMode, error) {
+	var mode ValidationMode
+	for _, part := range strings.Split(s, ",") {
+		switch strings.TrimSpace(part) {
+		case "", "off":
+		case "requests":
+			mode |= ValidateRequests
+		case "responses":
+			mode |= ValidateResponses
+		default:
+			return 0, fmt.Errorf("unknown validation mode %q", part)
+		}
+	}
+	return mode, nil
+}
+
+// SpecValidator checks traffic against an OpenAPI description. Routes the
+// spec does not describe pass through unchecked.
+type SpecValidator struct {
+	router routers.Router
+	mode   ValidationMode
+	report func(r *http.Request, err error)
+}
+
+// defineFormats registers the string formats kin-openapi does not check
+// by default. Formats are global in kin-openapi, hence the Once.
+var defineFormats sync.Once
+
+func NewSpecValidator(spec []byte, mode ValidationMode) (*SpecValidator, error) {
+	defineFormats.Do(func() {
+		openapi3.DefineStringFormatCallback("uuid", func(s string) error {
+			_, err := uuid.Parse(s)
+			return err
+		})
+	})
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	registerFileParts(doc)
+	// The servers list names the development host; match any host.
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &SpecValidator{router: router, mode: mode, report: logMismatch}, nil
+}
+
+// registerFileParts lets kin-openapi read multipart file parts, which it
+// decodes by their own Content-Type and knows few types for. Every type an
+// encoding in the spec lists is read as opaque bytes. Decoders are global
+// in kin-openapi, so only missing ones are added.
+func registerFileParts(doc *openapi3.T) {
+	for _, item := range doc.Paths {
+		for _, op := range item.Operations() {
+			if op.RequestBody == nil || op.RequestBody.Value == nil {
+				continue
+			}
+			form := op.RequestBody.Value.Content.Get("multipart/form-data")
+			if form == nil {
+				continue
+			}
+			for _, enc := range form.Encoding {
+				for _, ct := range strings.Split(enc.ContentType, ",") {
+					ct = strings.TrimSpace(ct)
+					if ct != "" && openapi3filter.RegisteredBodyDecoder(ct) == nil {
+						openapi3filter.RegisterBodyDecoder(ct, openapi3filter.FileBodyDecoder)
+					}
+				}
+			}
+		}
+	}
+}
+
+// OnMismatch replaces the default logging of response mismatches.
+func (v *SpecValidator) OnMismatch(fn func(r *http.Request, err error)) {
+	v.report = fn
+}
+
+func logMismatch(r *http.Request, err error) {
+	log.Printf("OpenAPI response mismatch for %s %s: %v", r.Method, r.URL.Path, err)
+}
+
+// Middleware validates around next. It sits inside the authentication
+// middleware, so requests that reach it are already authenticated.
+func (v *SpecValidator) Middleware(next http.Handler) http.Handler {
+	if v.mode == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if v.mode&ValidateRequests != 0 {
+			// Uploads are streamed by their handler, which enforces its own
+			// size limit, so only their parameters are checked here.
+			if isMultipart(r) {
+				input.Options.ExcludeRequestBody = true
+			} else if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxValidatedRequest)
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "request does not match the API description: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if v.mode&ValidateResponses == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tw := &teeWriter{ResponseWriter: w}
+		next.ServeHTTP(tw, r)
+		if tw.hijacked || tw.overflow || strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+			return
+		}
+		status := tw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 status,
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(&tw.body),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+		})
+		if err != nil {
+			v.report(r, err)
+		}
+	})
+}
+
+func isMultipart(r *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return strings.HasPrefix(mt, "multipart/")
+}
+
+// teeWriter passes the response through while keeping a copy of it.
+type teeWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+	hijacked bool
+}
+
+func (w *teeWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.overflow {
+		if w.body.Len()+len(p) > maxValidatedResponse {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(p)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *teeWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack is asserted directly by the WebSocket upgrader.
+func (w *teeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *teeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: Things
+  version: 1.0.0
+paths:
+  /things:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  maxLength: 10
+      responses:
+        '201':
+          description: Created
+  /things/{id}/photo:
+    post:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            format: uuid
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file:
+                  type: string
+                  format: binary
+      responses:
+        '201':
+          description: Uploaded
+  /things/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            format: uuid
+      responses:
+        '200':
+          description: Found
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+`
+
+func TestParseValidationMode(t *testing.T) {
+	cases := map[string]ValidationMode{
+		"":                    0,
+		"off":                 0,
+		"requests":            ValidateRequests,
+		"requests, responses": ValidateRequests | ValidateResponses,
+	}
+	for in, want := range cases {
+		got, err := ParseValidationMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseValidationMode(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseValidationMode("everything"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
+
+func TestSpecValidator_RejectsInvalidRequests(t *testing.T) {
+	v, err := NewSpecValidator([]byte(testSpec), ValidateRequests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	th := &testHandler{}
+	h := v.Middleware(th)
+
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{"name": "far too long a name"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || th.called {
+		t.Fatalf("expected 400 without calling the handler, got %d (called %v)", w.Code, th.called)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/things/not-a-uuid", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || th.called {
+		t.Fatalf("expected 400 for a bad path parameter, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{"name": "ok"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if !th.called {
+		t.Fatalf("expected a valid request to reach the handler, got %d", w.Code)
+	}
+
+	th.called = false
+	req = httptest.NewRequest(http.MethodGet, "/undocumented", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if !th.called {
+		t.Fatalf("expected an undocumented route to pass through")
+	}
+}
+
+func TestSpecValidator_ReportsResponseMismatches(t *testing.T) {
+	v, err := NewSpecValidator([]byte(testSpec), ValidateResponses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reported []error
+	v.OnMismatch(func(r *http.Request, err error) { reported = append(reported, err) })
+
+	body := `{"name": "widget"}`
+	h := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	path := "/things/0b6f1d2e-53c2-4f6a-9a51-0c1a1f2b3c4d"
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	if len(reported) != 0 {
+		t.Fatalf("unexpected mismatch: %v", reported)
+	}
+
+	body = `{"title": "widget"}`
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if len(reported) != 1 {
+		t.Fatalf("expected the missing name to be reported, got %v", reported)
+	}
+	if w.Body.String() != body {
+		t.Errorf("expected the response to be sent unchanged, got %q", w.Body.String())
+	}
+
+	// The teapot status is not documented.
+	w = httptest.NewRecorder()
+	v.Middleware(&testHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if len(reported) != 2 || w.Code != http.StatusTeapot {
+		t.Fatalf("expected an undocumented status to be reported, got %v", reported)
+	}
+}
+
+func TestSpecValidator_LeavesLargeBodiesToTheHandler(t *testing.T) {
+	v, err := NewSpecValidator([]byte(testSpec), ValidateRequests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An upload is streamed to the handler untouched, however large.
+	upload := &countingReader{r: strings.NewReader("--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.bin\"\r\n\r\n" +
+		strings.Repeat("x", 2*maxValidatedRequest) + "\r\n--b--\r\n")}
+	var readBefore, readBy int
+	h := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readBefore = upload.n
+		n, _ := io.Copy(io.Discard, r.Body)
+		readBy = int(n)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/things/0b6f1d2e-53c2-4f6a-9a51-0c1a1f2b3c4d/photo", upload)
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if readBefore != 0 || readBy <= 2*maxValidatedRequest {
+		t.Fatalf("expected the handler to read the whole upload, validator read %d bytes, handler %d", readBefore, readBy)
+	}
+
+	// Other bodies are validated, up to maxValidatedRequest.
+	th := &testHandler{}
+	req = httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{"name": "`+strings.Repeat("x", maxValidatedRequest)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	v.Middleware(th).ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge || th.called {
+		t.Fatalf("expected 413 without calling the handler, got %d (called %v)", w.Code, th.called)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}